@@ -3,6 +3,7 @@ package app
 import (
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/runtime"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
 )
 
 type ServerOption struct {
@@ -10,6 +11,13 @@ type ServerOption struct {
 	ProbeAddr            string
 	EnableLeaderElection bool
 
+	DefaultSchedulingPolicy string
+
+	SealKeySecret string
+
+	UnstructuredJobKinds    []string
+	RoleBindingClusterRoles []string
+
 	Scheme *runtime.Scheme
 }
 
@@ -29,5 +37,17 @@ func (s *ServerOption) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&s.EnableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	fs.StringVar(&s.DefaultSchedulingPolicy, "default-scheduling-policy", appsv1alpha1.FIFOSchedulingPolicy,
+		"The scheduling policy of items used by jobs not setting one, "+
+			"one of FIFO, Priority, ShortestExpectedDuration and CriticalPath.")
+	fs.StringVar(&s.SealKeySecret, "seal-key-secret", "",
+		"The Secret holding the RSA private key in key private.pem as <namespace>/<name>, "+
+			"which decrypts the encrypted data of secret templates sealed by \"songf seal\".")
+	fs.StringSliceVar(&s.UnstructuredJobKinds, "unstructured-job-kinds", nil,
+		"The namespaced kinds unstructured jobs are allowed to create as <apiVersion>/<kind>, "+
+			"like kubeflow.org/v1/PyTorchJob. The role of operator must grant them, unstructured jobs fail if not set.")
+	fs.StringSliceVar(&s.RoleBindingClusterRoles, "role-binding-cluster-roles", nil,
+		"The cluster roles role bindings of items are allowed to refer to, like view. The role of operator must "+
+			"grant bind of them, role bindings can only refer to the roles of their items if not set.")
 
 }
@@ -2,11 +2,14 @@ package app
 
 import (
 	"fmt"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"songf.sh/songf/internal/controller"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"strings"
 )
 
 func Run(opt ServerOption) error {
@@ -35,11 +38,24 @@ func Run(opt ServerOption) error {
 		return fmt.Errorf("%s:%s", err.Error(), "unable to start manager")
 	}
 
-	reconciler, err := controller.NewJobReconciler(mgr.GetClient(), mgr.GetScheme())
+	reconciler, err := controller.NewJobReconciler(mgr.GetClient(), mgr.GetScheme(), opt.DefaultSchedulingPolicy)
 	if err != nil {
 		return err
 	}
 
+	if opt.SealKeySecret != "" {
+		namespace, name, ok := strings.Cut(opt.SealKeySecret, "/")
+		if !ok || namespace == "" || name == "" {
+			return fmt.Errorf("seal key secret %s is not <namespace>/<name>", opt.SealKeySecret)
+		}
+		reconciler.SealKeySecret = types.NamespacedName{Namespace: namespace, Name: name}
+	}
+
+	if reconciler.UnstructuredJobKinds, err = appsv1alpha1.ParseUnstructuredJobKinds(opt.UnstructuredJobKinds); err != nil {
+		return fmt.Errorf("invalid unstructured job kinds: %s", err.Error())
+	}
+	reconciler.RoleBindingClusterRoles = opt.RoleBindingClusterRoles
+
 	if err = reconciler.SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("%s:%s-%s-%s", err.Error(), "unable to create controller", "controller", "Job")
 	}
@@ -49,6 +65,18 @@ func Run(opt ServerOption) error {
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("%s:%s-%s-%s", err.Error(), "unable to create controller", "controller", "JobBatch")
 	}
+	if err = (&controller.QueueReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("%s:%s-%s-%s", err.Error(), "unable to create controller", "controller", "Queue")
+	}
+	if err = (&controller.ScheduledJobReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("%s:%s-%s-%s", err.Error(), "unable to create controller", "controller", "ScheduledJob")
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"github.com/spf13/pflag"
+	"io"
+	"os"
+	"path/filepath"
+	"songf.sh/songf/pkg/seal"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// runSeal encrypts the data given by flags with the public key of operator, and writes the encryptedData
+// of a secret template.
+func runSeal(args []string, out io.Writer) error {
+	fs := pflag.NewFlagSet("seal", pflag.ContinueOnError)
+
+	cert := fs.String("cert", "", "PEM file of the public key or certificate of operator.")
+	namespace := fs.String("namespace", "", "Namespace of the Job using the secret template.")
+	name := fs.String("name", "", "Name of the secret template.")
+	literals := fs.StringArray("from-literal", nil, "Data to seal as key=value, can be repeated.")
+	files := fs.StringArray("from-file", nil, "File to seal as key=path or path with the file name as key, can be repeated.")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: songf seal --cert <pem> --namespace <namespace> --name <template> [--from-literal key=value] [--from-file [key=]path]\n\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *cert == "" || *namespace == "" || *name == "" {
+		fs.Usage()
+		return fmt.Errorf("cert, namespace and name are required")
+	}
+
+	pem, err := os.ReadFile(*cert)
+	if err != nil {
+		return fmt.Errorf("read cert err: %s", err.Error())
+	}
+
+	pub, err := seal.ParsePublicKey(pem)
+	if err != nil {
+		return err
+	}
+
+	data := map[string][]byte{}
+	for _, literal := range *literals {
+		key, value, ok := strings.Cut(literal, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("literal %s is not key=value", literal)
+		}
+		data[key] = []byte(value)
+	}
+
+	for _, file := range *files {
+		key, path, ok := strings.Cut(file, "=")
+		if !ok {
+			key, path = filepath.Base(file), file
+		}
+
+		value, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read file %s err: %s", path, err.Error())
+		}
+		data[key] = value
+	}
+
+	if len(data) == 0 {
+		return fmt.Errorf("no data to seal")
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintln(out, "encryptedData:")
+	for _, key := range keys {
+		sealed, err := seal.Seal(pub, *namespace, *name, data[key])
+		if err != nil {
+			return fmt.Errorf("seal %s err: %s", key, err.Error())
+		}
+		fmt.Fprintf(out, "  %s: %s\n", strconv.Quote(key), sealed)
+	}
+
+	return nil
+}
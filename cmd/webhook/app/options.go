@@ -15,6 +15,9 @@ type ServerOption struct {
 	KeyName       string
 	ClientCAName  string
 
+	UnstructuredJobKinds    []string
+	RoleBindingClusterRoles []string
+
 	Scheme *runtime.Scheme
 }
 
@@ -40,4 +43,11 @@ func (s *ServerOption) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.KeyName, "key-name", "tls.key", "tls key file name")
 	fs.StringVar(&s.ClientCAName, "client-ca-name", "", "tls client key, if not set,skip tls verify")
 
+	fs.StringSliceVar(&s.UnstructuredJobKinds, "unstructured-job-kinds", nil,
+		"The namespaced kinds unstructured jobs are allowed to create as <apiVersion>/<kind>, "+
+			"like kubeflow.org/v1/PyTorchJob. Unstructured jobs are rejected if not set.")
+	fs.StringSliceVar(&s.RoleBindingClusterRoles, "role-binding-cluster-roles", nil,
+		"The cluster roles role bindings of items are allowed to refer to, like view. "+
+			"Role bindings can only refer to the roles of their items if not set.")
+
 }
@@ -8,6 +8,7 @@ import (
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	hook "songf.sh/songf/internal/webhook"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
 )
 
 func Run(opt ServerOption) error {
@@ -42,15 +43,38 @@ func Run(opt ServerOption) error {
 		return fmt.Errorf("%s:%s", err.Error(), "unable to start manager")
 	}
 
-	hookImpl, err := hook.NewJobWebHook()
+	hookImpl, err := hook.NewJobWebHook(mgr.GetClient())
 	if err != nil {
 		return err
 	}
 
+	if hookImpl.UnstructuredJobKinds, err = appsv1alpha1.ParseUnstructuredJobKinds(opt.UnstructuredJobKinds); err != nil {
+		return fmt.Errorf("invalid unstructured job kinds: %s", err.Error())
+	}
+	hookImpl.RoleBindingClusterRoles = opt.RoleBindingClusterRoles
+
 	if err = hookImpl.SetupWebhookWithManager(mgr); err != nil {
 		return fmt.Errorf("%s:%s-%s-%s", err.Error(), "unable to create webhook", "webhook", "Job")
 	}
 
+	templateHook, err := hook.NewJobTemplateWebHook()
+	if err != nil {
+		return err
+	}
+
+	if err = templateHook.SetupWebhookWithManager(mgr); err != nil {
+		return fmt.Errorf("%s:%s-%s-%s", err.Error(), "unable to create webhook", "webhook", "JobTemplate")
+	}
+
+	scheduledJobHook, err := hook.NewScheduledJobWebHook(hookImpl)
+	if err != nil {
+		return err
+	}
+
+	if err = scheduledJobHook.SetupWebhookWithManager(mgr); err != nil {
+		return fmt.Errorf("%s:%s-%s-%s", err.Error(), "unable to create webhook", "webhook", "ScheduledJob")
+	}
+
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -3,6 +3,7 @@ package controller
 import (
 	"fmt"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
 	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
 	"songf.sh/songf/pkg/job_graph"
 	"sync"
@@ -12,11 +13,23 @@ type jobCache struct {
 	sync.RWMutex
 
 	jobItemGraphCache map[string]*job_graph.JobItemGraph
+
+	// dependents are the jobs whose items depend on a job, key is the depended job
+	dependents map[types.NamespacedName]map[types.NamespacedName]bool
+
+	// dependencies are the jobs depended by the items of a job
+	dependencies map[types.NamespacedName][]types.NamespacedName
+
+	// seenDependencies are the depended jobs found by a job once, which fail its items if not found later
+	seenDependencies map[types.NamespacedName]map[types.NamespacedName]bool
 }
 
 func newJobCache() *jobCache {
 	cache := &jobCache{
 		jobItemGraphCache: map[string]*job_graph.JobItemGraph{},
+		dependents:        map[types.NamespacedName]map[types.NamespacedName]bool{},
+		dependencies:      map[types.NamespacedName][]types.NamespacedName{},
+		seenDependencies:  map[types.NamespacedName]map[types.NamespacedName]bool{},
 	}
 
 	return cache
@@ -27,10 +40,15 @@ func (c *jobCache) syncGraphFromJob(job *appsv1alpha1.Job) error {
 	c.Lock()
 	defer c.Unlock()
 
+	key := types.NamespacedName{Namespace: job.Namespace, Name: job.Name}
+
 	switch job.Status.State.Phase {
 	case appsv1alpha1.Terminated:
 		delete(c.jobItemGraphCache, job.Name)
+		c.setJobDependencies(key, nil)
 	default:
+		c.setJobDependencies(key, appsv1alpha1.GetJobDependencies(job))
+
 		graph, ok := c.jobItemGraphCache[job.Name]
 		if !ok {
 			graph = job_graph.NewJobItemGraph()
@@ -45,6 +63,61 @@ func (c *jobCache) syncGraphFromJob(job *appsv1alpha1.Job) error {
 	return nil
 }
 
+// setJobDependencies replaces the jobs depended by job in index, caller must hold the lock.
+func (c *jobCache) setJobDependencies(key types.NamespacedName, dependencies []types.NamespacedName) {
+	for _, dependency := range c.dependencies[key] {
+		delete(c.dependents[dependency], key)
+		if len(c.dependents[dependency]) == 0 {
+			delete(c.dependents, dependency)
+		}
+	}
+	delete(c.dependencies, key)
+
+	listed := map[types.NamespacedName]bool{}
+	for _, dependency := range dependencies {
+		listed[dependency] = true
+	}
+	for dependency := range c.seenDependencies[key] {
+		if !listed[dependency] {
+			delete(c.seenDependencies[key], dependency)
+		}
+	}
+	if len(c.seenDependencies[key]) == 0 {
+		delete(c.seenDependencies, key)
+	}
+
+	if len(dependencies) == 0 {
+		return
+	}
+
+	c.dependencies[key] = dependencies
+	for _, dependency := range dependencies {
+		if c.dependents[dependency] == nil {
+			c.dependents[dependency] = map[types.NamespacedName]bool{}
+		}
+		c.dependents[dependency][key] = true
+	}
+}
+
+// setJobDependencySeen records that job found the depended job once.
+func (c *jobCache) setJobDependencySeen(key, dependency types.NamespacedName) {
+	c.Lock()
+	defer c.Unlock()
+
+	if c.seenDependencies[key] == nil {
+		c.seenDependencies[key] = map[types.NamespacedName]bool{}
+	}
+	c.seenDependencies[key][dependency] = true
+}
+
+// isJobDependencySeen returns true if job found the depended job before.
+func (c *jobCache) isJobDependencySeen(key, dependency types.NamespacedName) bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.seenDependencies[key][dependency]
+}
+
 func (c *jobCache) syncJobItemStatus(job *appsv1alpha1.Job) (bool, error) {
 	c.Lock()
 	defer c.Unlock()
@@ -58,7 +131,7 @@ func (c *jobCache) syncJobItemStatus(job *appsv1alpha1.Job) (bool, error) {
 
 	status := graph.GetAllItemStatus()
 
-	for _, item := range job.Spec.Items {
+	for _, item := range appsv1alpha1.CalJobItems(job) {
 		if item.Truncated != nil && *item.Truncated == true {
 			continue
 		}
@@ -82,6 +155,12 @@ func (c *jobCache) syncJobItemStatus(job *appsv1alpha1.Job) (bool, error) {
 
 	}
 
+	groupStatus := appsv1alpha1.CalJobGroupStatus(job)
+	if !apiequality.Semantic.DeepEqual(groupStatus, job.Status.GroupStatus) {
+		job.Status.GroupStatus = groupStatus
+		changedFlag = true
+	}
+
 	return changedFlag, nil
 
 }
@@ -104,7 +183,7 @@ func (c *jobCache) getFirstJobItem(jobName string) (*appsv1alpha1.Item, bool) {
 
 }
 
-func (c *jobCache) getNextScheduleJobItem(jobName string) ([]*appsv1alpha1.Item, bool) {
+func (c *jobCache) getNextScheduleJobItem(jobName string, policy job_graph.SchedulingPolicy) ([]*appsv1alpha1.Item, bool) {
 	c.Lock()
 	defer c.Unlock()
 
@@ -113,7 +192,7 @@ func (c *jobCache) getNextScheduleJobItem(jobName string) ([]*appsv1alpha1.Item,
 		return nil, false
 	}
 
-	nextItems := graph.ItemsNext2Scheduled()
+	nextItems := graph.ItemsNext2ScheduledByPolicy(policy)
 	if len(nextItems) == 0 {
 		return nil, false
 	}
@@ -121,6 +200,48 @@ func (c *jobCache) getNextScheduleJobItem(jobName string) ([]*appsv1alpha1.Item,
 
 }
 
+func (c *jobCache) setJobItemScheduling(jobName, itemName string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	graph, ok := c.jobItemGraphCache[jobName]
+	if !ok {
+		return fmt.Errorf("not found job %s from graph", jobName)
+	}
+
+	graph.SetItemScheduling(itemName)
+
+	return nil
+}
+
+func (c *jobCache) setJobItemPendingReason(jobName, itemName, reason, message string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	graph, ok := c.jobItemGraphCache[jobName]
+	if !ok {
+		return fmt.Errorf("not found job %s from graph", jobName)
+	}
+
+	graph.SetItemPendingReason(itemName, reason, message)
+
+	return nil
+}
+
+func (c *jobCache) getJobRunningItemNum(jobName string) (itemNum, podNum int32, err error) {
+	c.Lock()
+	defer c.Unlock()
+
+	graph, ok := c.jobItemGraphCache[jobName]
+	if !ok {
+		return 0, 0, fmt.Errorf("not found job %s from graph", jobName)
+	}
+
+	itemNum, podNum = graph.RunningItemNum()
+
+	return itemNum, podNum, nil
+}
+
 func (c *jobCache) isJobFinished(jobName string) (finished, failed bool, err error) {
 	c.Lock()
 	defer c.Unlock()
@@ -137,15 +258,22 @@ func (c *jobCache) isJobFinished(jobName string) (finished, failed bool, err err
 		return false, false, fmt.Errorf("not found job start item %s from graph", jobName)
 	}
 
-	finished, failed = c.isJobFinishedImpl(allItemStatus, startItemNode)
+	finished, failed = c.isJobFinishedImpl(graph, allItemStatus, startItemNode)
 
 	return finished, failed, nil
 
 }
 
-func (c *jobCache) isJobFinishedImpl(allStatus map[string]*appsv1alpha1.ItemStatus, node *appsv1alpha1.ItemNode) (finished, failed bool) {
+func (c *jobCache) isJobFinishedImpl(graph *job_graph.JobItemGraph, allStatus map[string]*appsv1alpha1.ItemStatus, node *appsv1alpha1.ItemNode) (finished, failed bool) {
 	status := allStatus[node.Item.Name]
-	switch status.Phase {
+
+	// items which will run again are taken as running
+	phase := status.Phase
+	if !graph.IsItemSettled(status) {
+		phase = appsv1alpha1.ItemScheduled
+	}
+
+	switch phase {
 	case appsv1alpha1.ItemFailed:
 		return true, true
 	case appsv1alpha1.ItemCompleted:
@@ -155,7 +283,7 @@ func (c *jobCache) isJobFinishedImpl(allStatus map[string]*appsv1alpha1.ItemStat
 				continue
 			}
 
-			subFinished, subFailed := c.isJobFinishedImpl(allStatus, child)
+			subFinished, subFailed := c.isJobFinishedImpl(graph, allStatus, child)
 			if subFailed {
 				hasSubFailed = true
 			}
@@ -178,7 +306,7 @@ func (c *jobCache) isJobFinishedImpl(allStatus map[string]*appsv1alpha1.ItemStat
 				continue
 			}
 
-			_, subFailed := c.isJobFinishedImpl(allStatus, child)
+			_, subFailed := c.isJobFinishedImpl(graph, allStatus, child)
 			if subFailed {
 				return true, true
 			}
@@ -206,3 +334,137 @@ func (c *jobCache) isJobDeleted(jobName string) (bool, error) {
 	return false, nil
 
 }
+
+func (c *jobCache) setJobItemFailed(jobName, itemName, reason, message string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	graph, ok := c.jobItemGraphCache[jobName]
+	if !ok {
+		return fmt.Errorf("not found job %s from graph", jobName)
+	}
+
+	graph.SetItemFailed(itemName, reason, message)
+
+	return nil
+}
+
+func (c *jobCache) getJobItemsToCollectOutputs(jobName string) ([]*appsv1alpha1.Item, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	graph, ok := c.jobItemGraphCache[jobName]
+	if !ok {
+		return nil, fmt.Errorf("not found job %s from graph", jobName)
+	}
+
+	return graph.ItemsToCollectOutputs(), nil
+}
+
+func (c *jobCache) setJobItemOutputs(jobName, itemName string, outputs map[string]string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	graph, ok := c.jobItemGraphCache[jobName]
+	if !ok {
+		return fmt.Errorf("not found job %s from graph", jobName)
+	}
+
+	graph.SetItemOutputs(itemName, outputs)
+
+	return nil
+}
+
+func (c *jobCache) getJobItemOutputs(jobName string) (map[string]map[string]string, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	graph, ok := c.jobItemGraphCache[jobName]
+	if !ok {
+		return nil, fmt.Errorf("not found job %s from graph", jobName)
+	}
+
+	return graph.GetAllItemOutputs(), nil
+}
+
+func (c *jobCache) getAllJobItemStatus(jobName string) (map[string]*appsv1alpha1.ItemStatus, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	graph, ok := c.jobItemGraphCache[jobName]
+	if !ok {
+		return nil, fmt.Errorf("not found job %s from graph", jobName)
+	}
+
+	return graph.GetAllItemStatus(), nil
+}
+
+func (c *jobCache) resetJobItemForRetry(jobName, itemName, message string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	graph, ok := c.jobItemGraphCache[jobName]
+	if !ok {
+		return fmt.Errorf("not found job %s from graph", jobName)
+	}
+
+	graph.ResetItemForRetry(itemName, message)
+
+	return nil
+}
+
+func (c *jobCache) recordJobItemIteration(jobName, itemName string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	graph, ok := c.jobItemGraphCache[jobName]
+	if !ok {
+		return fmt.Errorf("not found job %s from graph", jobName)
+	}
+
+	graph.RecordItemIteration(itemName)
+
+	return nil
+}
+
+func (c *jobCache) nextJobItemIteration(jobName, itemName, message string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	graph, ok := c.jobItemGraphCache[jobName]
+	if !ok {
+		return fmt.Errorf("not found job %s from graph", jobName)
+	}
+
+	graph.NextItemIteration(itemName, message)
+
+	return nil
+}
+
+func (c *jobCache) setJobItemCompleted(jobName, itemName, reason, message string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	graph, ok := c.jobItemGraphCache[jobName]
+	if !ok {
+		return fmt.Errorf("not found job %s from graph", jobName)
+	}
+
+	graph.SetItemCompleted(itemName, reason, message)
+
+	return nil
+}
+
+func (c *jobCache) setJobItemReason(jobName, itemName, reason, message string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	graph, ok := c.jobItemGraphCache[jobName]
+	if !ok {
+		return fmt.Errorf("not found job %s from graph", jobName)
+	}
+
+	graph.SetItemReason(itemName, reason, message)
+
+	return nil
+}
@@ -19,18 +19,25 @@ package controller
 import (
 	"context"
 	"fmt"
-	v1 "k8s.io/api/batch/v1"
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"songf.sh/songf/internal/driver"
 	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
-	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"songf.sh/songf/pkg/job_graph"
+	"sync"
+	"time"
 )
 
 // JobReconciler reconciles a Job object
@@ -42,14 +49,44 @@ type JobReconciler struct {
 	//VcClient   *vcclient.Clientset
 	Cache *jobCache
 
+	// APIReader reads objects not watched by operator, such as pods, from api server directly
+	APIReader client.Reader
+
 	Scheme *runtime.Scheme
+
+	// DefaultSchedulingPolicy is used by Jobs not setting their scheduling policy
+	DefaultSchedulingPolicy string
+
+	// SealKeySecret is the Secret holding the private key which decrypts the encrypted data of secrets
+	SealKeySecret types.NamespacedName
+
+	// UnstructuredJobKinds are the kinds unstructured jobs are allowed to create, none if empty
+	UnstructuredJobKinds []schema.GroupVersionKind
+
+	// RoleBindingClusterRoles are the cluster roles role bindings of items are allowed to refer to, none if empty
+	RoleBindingClusterRoles []string
+
+	// waitProber requests the endpoints of http wait items
+	waitProber httpProber
+
+	// controller and informerCache register the watches of unstructured jobs once their kinds are used
+	controller    controller.Controller
+	informerCache cache.Cache
+	watchLock     sync.Mutex
+	watchedKinds  map[schema.GroupVersionKind]bool
 }
 
-func NewJobReconciler(client client.Client, scheme *runtime.Scheme) (*JobReconciler, error) {
+func NewJobReconciler(client client.Client, scheme *runtime.Scheme, defaultSchedulingPolicy string) (*JobReconciler, error) {
+
+	if _, err := job_graph.GetSchedulingPolicy(defaultSchedulingPolicy); err != nil {
+		return nil, fmt.Errorf("invalid default scheduling policy: %s", err.Error())
+	}
 
 	r := &JobReconciler{
-		Client: client,
-		Scheme: scheme,
+		Client:                  client,
+		Scheme:                  scheme,
+		DefaultSchedulingPolicy: defaultSchedulingPolicy,
+		watchedKinds:            map[schema.GroupVersionKind]bool{},
 	}
 
 	r.Cache = newJobCache()
@@ -75,6 +112,9 @@ func NewJobReconciler(client client.Client, scheme *runtime.Scheme) (*JobReconci
 //+kubebuilder:rbac:groups=apps.songf.sh,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps.songf.sh,resources=jobs/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=apps.songf.sh,resources=jobs/finalizers,verbs=update
+//+kubebuilder:rbac:groups=apps.songf.sh,resources=queues,verbs=get;list;watch
+//+kubebuilder:rbac:groups=scheduling.volcano.sh,resources=podgroups,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -105,6 +145,12 @@ func (r *JobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return ctrl.Result{}, fmt.Errorf("reconcile job err: %s", err.Error())
 	}
 
+	// watch the kinds of unstructured jobs, jobs created before restart are watched again here
+	if err := r.ensureUnstructuredWatches(job); err != nil {
+		klog.Errorf(err.Error())
+		return ctrl.Result{}, fmt.Errorf("reconcile job err: %s", err.Error())
+	}
+
 	// if job was deleted, sync logic
 	deletedFlag, err := r.Cache.isJobDeleted(job.Name)
 	if err != nil {
@@ -130,6 +176,11 @@ func (r *JobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 
 			return ctrl.Result{}, nil
 		default:
+			if err := r.deleteJobClusterModules(context.Background(), job); err != nil {
+				klog.Errorf(err.Error())
+				return ctrl.Result{}, fmt.Errorf("reconcile job err: %s", err.Error())
+			}
+
 			job.Status.State.Phase = appsv1alpha1.Terminating
 			job.Status.State.Message = "job deleting"
 			if err := r.updateJobStatus(context.Background(), job); err != nil {
@@ -143,10 +194,16 @@ func (r *JobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	}
 
 	// if job was new created, update status
-	if job.Status.State.Phase == appsv1alpha1.Unknown {
-		job.Status.State.Phase = appsv1alpha1.Scheduled
-		job.Status.State.Message = "job scheduled"
+	if job.Status.State.Phase == "" || job.Status.State.Phase == appsv1alpha1.Unknown {
+		if job.Spec.Queue != "" {
+			job.Status.State.Phase = appsv1alpha1.Queued
+			job.Status.State.Message = "job queued"
+		} else {
+			job.Status.State.Phase = appsv1alpha1.Scheduled
+			job.Status.State.Message = "job scheduled"
+		}
 		job.Status.ItemStatus = map[string]appsv1alpha1.ItemStatus{}
+		job.Status.TemplateRevision = appsv1alpha1.CalJobTemplateRevision(job)
 
 		if err := r.updateJobStatus(context.Background(), job); err != nil {
 			klog.Errorf(err.Error())
@@ -156,6 +213,60 @@ func (r *JobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return ctrl.Result{}, nil
 	}
 
+	// if job was a queued one, try to admit it, items are scheduled after admitted
+	if job.Status.State.Phase == appsv1alpha1.Queued {
+		if _, err := r.admitJob(context.Background(), job); err != nil {
+			klog.Errorf(err.Error())
+			return ctrl.Result{}, fmt.Errorf("reconcile job err: %s", err.Error())
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	// outputs of completed items, persisted at once as pods they are read from may be deleted any time
+	collected, err := r.collectItemOutputs(context.Background(), job)
+	if err != nil {
+		klog.Errorf(err.Error())
+		return ctrl.Result{}, fmt.Errorf("reconcile job err: %s", err.Error())
+	}
+	if collected {
+		if _, err := r.Cache.syncJobItemStatus(job); err != nil {
+			klog.Errorf(err.Error())
+			return ctrl.Result{}, fmt.Errorf("reconcile job err: %s", err.Error())
+		}
+		if err := r.updateJobStatus(context.Background(), job); err != nil {
+			klog.Errorf(err.Error())
+			return ctrl.Result{}, fmt.Errorf("reconcile job err: %s", err.Error())
+		}
+	}
+
+	// next iterations of loop items
+	if err := r.syncItemLoops(job); err != nil {
+		klog.Errorf(err.Error())
+		return ctrl.Result{}, fmt.Errorf("reconcile job err: %s", err.Error())
+	}
+
+	// wait items ready or timed out
+	waitRequeueAfter, err := r.syncWaitItems(context.Background(), job)
+	if err != nil {
+		klog.Errorf(err.Error())
+		return ctrl.Result{}, fmt.Errorf("reconcile job err: %s", err.Error())
+	}
+
+	// timeout and retry of items in groups
+	requeueAfter, err := r.syncGroupPolicies(context.Background(), job)
+	if err != nil {
+		klog.Errorf(err.Error())
+		return ctrl.Result{}, fmt.Errorf("reconcile job err: %s", err.Error())
+	}
+	requeueAfter = minRequeueAfter(requeueAfter, waitRequeueAfter)
+
+	// items waiting for admission of Kueue
+	if err := r.syncKueueAdmission(context.Background(), job); err != nil {
+		klog.Errorf(err.Error())
+		return ctrl.Result{}, fmt.Errorf("reconcile job err: %s", err.Error())
+	}
+
 	// job items' status
 	changed, err := r.Cache.syncJobItemStatus(job)
 	if err != nil {
@@ -169,6 +280,12 @@ func (r *JobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		}
 	}
 
+	// workloads of long-running items no longer needed
+	if err := r.stopLongRunningItems(context.Background(), job); err != nil {
+		klog.Errorf(err.Error())
+		return ctrl.Result{}, fmt.Errorf("reconcile job err: %s", err.Error())
+	}
+
 	// job finished
 	finished, failed, err := r.Cache.isJobFinished(job.Name)
 	if err != nil {
@@ -195,6 +312,11 @@ func (r *JobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 			} else {
 				job.Status.State.Phase = appsv1alpha1.Completing
 				job.Status.State.Message = "job completing"
+
+				if err := r.resolveJobOutputs(job); err != nil {
+					job.Status.State.Phase = appsv1alpha1.Failed
+					job.Status.State.Message = err.Error()
+				}
 			}
 
 			if err := r.updateJobStatus(context.Background(), job); err != nil {
@@ -208,38 +330,98 @@ func (r *JobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 
 	// if job was a scheduled one, schedule next items
 	if job.Status.State.Phase == appsv1alpha1.Scheduled {
-		if err := r.createJobItem(context.Background(), job); err != nil {
+		createRequeueAfter, err := r.createJobItem(context.Background(), job)
+		if err != nil {
 			klog.Errorf(err.Error())
 			return ctrl.Result{}, fmt.Errorf("reconcile get job err: %s", err.Error())
 		}
+		requeueAfter = minRequeueAfter(requeueAfter, createRequeueAfter)
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// minRequeueAfter returns the sooner of two requeue durations, 0 means not requeued.
+func minRequeueAfter(a, b time.Duration) time.Duration {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+
+	return a
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *JobReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
-	filter := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+	r.APIReader = mgr.GetAPIReader()
+	r.informerCache = mgr.GetCache()
+
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1alpha1.Job{}).
+		WithEventFilter(jobObjectFilter()).
+		WithEventFilter(predicate.ResourceVersionChangedPredicate{})
+
+	// kinds not installed, like JobSet, are not watched, items using them fail to be created
+	for _, d := range driver.GetItemResourceDrivers() {
+		// kinds set by items are watched once the Jobs using them are seen
+		if d.Object() == nil {
+			continue
+		}
+
+		gvk, err := apiutil.GVKForObject(d.Object(), mgr.GetScheme())
+		if err != nil {
+			return err
+		}
+
+		if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+			klog.Warningf("%s of driver %s is not watched: %s", gvk.String(), d.Name(), err.Error())
+			continue
+		}
+
+		b = b.Watches(d.Object(), handler.EnqueueRequestsFromMapFunc(r.Cache.driverHandler(d)))
+	}
+
+	// Workloads are watched only if Kueue is installed
+	if _, err := mgr.GetRESTMapper().RESTMapping(workloadGroupVersionKind.GroupKind(), workloadGroupVersionKind.Version); err != nil {
+		klog.Warningf("%s is not watched: %s", workloadGroupVersionKind.String(), err.Error())
+	} else {
+		workload := &unstructured.Unstructured{}
+		workload.SetGroupVersionKind(workloadGroupVersionKind)
+		b = b.Watches(workload, handler.EnqueueRequestsFromMapFunc(r.workloadHandler))
+	}
+
+	c, err := b.
+		Watches(&appsv1alpha1.Queue{}, handler.EnqueueRequestsFromMapFunc(r.queueHandler)).
+		Watches(&appsv1alpha1.Job{}, handler.EnqueueRequestsFromMapFunc(r.Cache.dependentJobHandler)).
+		Build(r)
+	if err != nil {
+		return err
+	}
+
+	r.controller = c
+
+	return nil
+}
+
+// jobObjectFilter passes songf objects and the objects created by Jobs.
+func jobObjectFilter() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
 		if obj.GetObjectKind().GroupVersionKind().GroupVersion().Group == appsv1alpha1.GroupVersion.Group {
 			return true
 		}
 
+		switch obj.(type) {
+		case *appsv1alpha1.Job, *appsv1alpha1.Queue:
+			return true
+		}
+
+		// Workloads are created by Kueue for the jobs created by Jobs, which are found by owner
+		if obj.GetObjectKind().GroupVersionKind() == workloadGroupVersionKind {
+			return true
+		}
+
 		annotations := obj.GetAnnotations()
 		_, ok := annotations[appsv1alpha1.CreateByJob]
 		return ok
 	})
-
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&appsv1alpha1.Job{}).
-		WithEventFilter(filter).
-		WithEventFilter(predicate.ResourceVersionChangedPredicate{}).
-		Watches(&v1.Job{}, handler.EnqueueRequestsFromMapFunc(r.Cache.kubeJobHandler)).
-		Watches(&v1alpha1.Job{}, handler.EnqueueRequestsFromMapFunc(r.Cache.vcJobHandler)).
-		Watches(&corev1.Service{}, handler.EnqueueRequestsFromMapFunc(r.Cache.serviceHandler)).
-		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.Cache.configmapHandler)).
-		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.Cache.secretHandler)).
-		Watches(&corev1.PersistentVolumeClaim{}, handler.EnqueueRequestsFromMapFunc(r.Cache.pvcHandler)).
-		Watches(&corev1.PersistentVolume{}, handler.EnqueueRequestsFromMapFunc(r.Cache.pvHandler)).
-		Complete(r)
 }
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"time"
+)
+
+// jobDependencyGracePeriod is how long after a Job was created its depended Jobs not found are waited for.
+const jobDependencyGracePeriod = 10 * time.Minute
+
+// isJobDependencyReady checks the Jobs depended by the items in unit. The unit keeps pending until all of them
+// reached the phases, and an item fails if a Job it depends on can never reach the phase. A depended Job not
+// found fails the item once it was found before, or once the grace period after job created passed, the
+// returned duration requeues job at the end of grace period.
+func (r *JobReconciler) isJobDependencyReady(ctx context.Context, job *appsv1alpha1.Job, unit []*appsv1alpha1.Item) (bool, time.Duration, error) {
+
+	reason, message := "", ""
+	var requeueAfter time.Duration
+
+	jobKey := types.NamespacedName{Namespace: job.Namespace, Name: job.Name}
+
+	for _, item := range unit {
+		for i := range item.DependsOnJobs {
+			dependency := &item.DependsOnJobs[i]
+			key := appsv1alpha1.CalJobDependencyKey(job, dependency)
+
+			dependedJob := &appsv1alpha1.Job{}
+			if err := r.Client.Get(ctx, key, dependedJob); err != nil {
+				if !errors.IsNotFound(err) {
+					return false, 0, fmt.Errorf("get depended job %s err: %s", key.String(), err.Error())
+				}
+				dependedJob = nil
+			}
+
+			// the status of Jobs in other namespaces is only exposed to the namespaces they allow
+			if dependedJob != nil && key.Namespace != job.Namespace && !appsv1alpha1.IsNamespaceAllowed(dependedJob, job.Namespace) {
+				message := fmt.Sprintf("depended job %s does not allow namespace %s", key.String(), job.Namespace)
+				klog.Infof("job %s/%s item %s failed: %s", job.Namespace, job.Name, item.Name, message)
+
+				if err := r.Cache.setJobItemFailed(job.Name, item.Name, "DependentJobNotAllowed", message); err != nil {
+					return false, 0, err
+				}
+				return false, 0, nil
+			}
+
+			if dependedJob == nil {
+				notFoundMessage := ""
+				if r.Cache.isJobDependencySeen(jobKey, key) {
+					notFoundMessage = fmt.Sprintf("depended job %s was deleted", key.String())
+				} else if waited := time.Since(job.CreationTimestamp.Time); waited >= jobDependencyGracePeriod {
+					notFoundMessage = fmt.Sprintf("depended job %s not created in %s", key.String(), jobDependencyGracePeriod)
+				} else {
+					requeueAfter = minRequeueAfter(requeueAfter, jobDependencyGracePeriod-waited)
+				}
+
+				if notFoundMessage != "" {
+					klog.Infof("job %s/%s item %s failed: %s", job.Namespace, job.Name, item.Name, notFoundMessage)
+
+					if err := r.Cache.setJobItemFailed(job.Name, item.Name, "DependentJobNotFound", notFoundMessage); err != nil {
+						return false, 0, err
+					}
+					return false, 0, nil
+				}
+			} else {
+				r.Cache.setJobDependencySeen(jobKey, key)
+			}
+
+			satisfied, failed := appsv1alpha1.CheckJobDependency(dependency, dependedJob)
+			if failed {
+				message := fmt.Sprintf("depended job %s is %s, can not be %s", key.String(),
+					dependedJob.Status.State.Phase, appsv1alpha1.JobDependencyCompleted)
+				klog.Infof("job %s/%s item %s failed: %s", job.Namespace, job.Name, item.Name, message)
+
+				if err := r.Cache.setJobItemFailed(job.Name, item.Name, "DependentJobFailed", message); err != nil {
+					return false, 0, err
+				}
+				return false, 0, nil
+			}
+
+			if !satisfied && reason == "" {
+				reason = "WaitingForDependentJobs"
+				if dependedJob == nil {
+					message = fmt.Sprintf("waiting for depended job %s to be created", key.String())
+				} else {
+					message = fmt.Sprintf("waiting for depended job %s, now %s", key.String(), dependedJob.Status.State.Phase)
+				}
+			}
+		}
+	}
+
+	if reason == "" {
+		return true, 0, nil
+	}
+
+	for _, item := range unit {
+		if err := r.Cache.setJobItemPendingReason(job.Name, item.Name, reason, message); err != nil {
+			return false, 0, err
+		}
+	}
+
+	return false, requeueAfter, nil
+}
@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"context"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+	"time"
+)
+
+func TestIsJobDependencyReady(t *testing.T) {
+	tests := []struct {
+		name      string
+		phase     appsv1alpha1.JobPhase
+		exist     bool
+		namespace string
+		allowed   string
+		seen      bool
+		created   time.Duration
+		ready     bool
+		requeue   bool
+		item      appsv1alpha1.ItemPhase
+		reason    string
+	}{
+		{name: "depended job not created", requeue: true, item: appsv1alpha1.ItemPending, reason: "WaitingForDependentJobs"},
+		{name: "depended job not created in grace period", created: jobDependencyGracePeriod,
+			item: appsv1alpha1.ItemFailed, reason: "DependentJobNotFound"},
+		{name: "depended job deleted", seen: true, item: appsv1alpha1.ItemFailed, reason: "DependentJobNotFound"},
+		{name: "depended job running", exist: true, phase: appsv1alpha1.Scheduled, item: appsv1alpha1.ItemPending, reason: "WaitingForDependentJobs"},
+		{name: "depended job completed", exist: true, phase: appsv1alpha1.Completed, ready: true, item: appsv1alpha1.ItemPending},
+		{name: "depended job failed", exist: true, phase: appsv1alpha1.Failed, item: appsv1alpha1.ItemFailed, reason: "DependentJobFailed"},
+		{name: "depended job in namespace not allowing", exist: true, namespace: "other", phase: appsv1alpha1.Completed,
+			item: appsv1alpha1.ItemFailed, reason: "DependentJobNotAllowed"},
+		{name: "depended job in namespace allowing", exist: true, namespace: "other", allowed: "ns", phase: appsv1alpha1.Completed,
+			ready: true, item: appsv1alpha1.ItemPending},
+	}
+
+	for _, test := range tests {
+		job := &appsv1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns",
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-test.created))},
+			Spec: appsv1alpha1.JobSpec{Items: []appsv1alpha1.Item{
+				{
+					Name:          "a",
+					DependsOnJobs: []appsv1alpha1.JobDependency{{Namespace: test.namespace, Name: "x"}},
+					ItemJobs: appsv1alpha1.ItemJobResource{Jobs: []appsv1alpha1.ItemJobTemplate{{
+						TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "train"},
+						KubeJobSpec:      &batchv1.JobSpec{},
+					}}},
+				},
+			}},
+			Status: appsv1alpha1.JobStatus{ItemStatus: map[string]appsv1alpha1.ItemStatus{
+				"a": {Name: "a", Phase: appsv1alpha1.ItemPending},
+			}},
+		}
+		dependedKey := appsv1alpha1.CalJobDependencyKey(job, &job.Spec.Items[0].DependsOnJobs[0])
+
+		var objs []client.Object
+		if test.exist {
+			depended := &appsv1alpha1.Job{ObjectMeta: metav1.ObjectMeta{Name: dependedKey.Name, Namespace: dependedKey.Namespace}}
+			if test.allowed != "" {
+				depended.Annotations = map[string]string{appsv1alpha1.AllowedNamespacesAnnotation: test.allowed}
+			}
+			depended.Status.State.Phase = test.phase
+			objs = append(objs, depended)
+		}
+
+		c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(objs...).Build()
+		r := &JobReconciler{Client: c, Cache: newJobCache()}
+		if err := r.Cache.syncGraphFromJob(job); err != nil {
+			t.Fatalf("%s: sync graph err: %s", test.name, err.Error())
+		}
+		if test.seen {
+			r.Cache.setJobDependencySeen(types.NamespacedName{Namespace: job.Namespace, Name: job.Name}, dependedKey)
+		}
+
+		ready, requeueAfter, err := r.isJobDependencyReady(context.Background(), job, []*appsv1alpha1.Item{&job.Spec.Items[0]})
+		if err != nil {
+			t.Fatalf("%s: check dependency err: %s", test.name, err.Error())
+		}
+		if ready != test.ready {
+			t.Errorf("%s: expected ready %t, got %t", test.name, test.ready, ready)
+		}
+		if requeue := requeueAfter > 0 && requeueAfter <= jobDependencyGracePeriod; requeue != test.requeue {
+			t.Errorf("%s: expected requeue %t, got %s", test.name, test.requeue, requeueAfter)
+		}
+
+		allStatus, err := r.Cache.getAllJobItemStatus(job.Name)
+		if err != nil {
+			t.Fatalf("%s: get item status err: %s", test.name, err.Error())
+		}
+		if status := allStatus["a"]; status.Phase != test.item || status.Reason != test.reason {
+			t.Errorf("%s: expected item %s %s, got %s %s", test.name, test.item, test.reason, status.Phase, status.Reason)
+		}
+	}
+}
+
+func TestIsJobDependencyReadyDeleted(t *testing.T) {
+	job := &appsv1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns", CreationTimestamp: metav1.Now()},
+		Spec: appsv1alpha1.JobSpec{Items: []appsv1alpha1.Item{{
+			Name:          "a",
+			DependsOnJobs: []appsv1alpha1.JobDependency{{Name: "x"}},
+		}}},
+	}
+	depended := &appsv1alpha1.Job{ObjectMeta: metav1.ObjectMeta{Name: "x", Namespace: "ns"}}
+	depended.Status.State.Phase = appsv1alpha1.Scheduled
+
+	c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(depended).Build()
+	r := &JobReconciler{Client: c, Cache: newJobCache()}
+	if err := r.Cache.syncGraphFromJob(job); err != nil {
+		t.Fatalf("sync graph err: %s", err.Error())
+	}
+
+	unit := []*appsv1alpha1.Item{&job.Spec.Items[0]}
+	if ready, _, err := r.isJobDependencyReady(context.Background(), job, unit); err != nil || ready {
+		t.Fatalf("expected waiting for running depended job, got %t %v", ready, err)
+	}
+
+	// the depended job deleted within grace period fails the item at once
+	if err := c.Delete(context.Background(), depended); err != nil {
+		t.Fatalf("delete depended job err: %s", err.Error())
+	}
+	if _, requeueAfter, err := r.isJobDependencyReady(context.Background(), job, unit); err != nil || requeueAfter != 0 {
+		t.Fatalf("expected no requeue after depended job deleted, got %s %v", requeueAfter, err)
+	}
+
+	allStatus, err := r.Cache.getAllJobItemStatus(job.Name)
+	if err != nil {
+		t.Fatalf("get item status err: %s", err.Error())
+	}
+	if status := allStatus["a"]; status.Phase != appsv1alpha1.ItemFailed || status.Reason != "DependentJobNotFound" {
+		t.Errorf("expected item failed as depended job deleted, got %s %s", status.Phase, status.Reason)
+	}
+}
@@ -0,0 +1,245 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"songf.sh/songf/internal/driver"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"time"
+)
+
+// retryCheckInterval is the interval to check whether the jobs of previous attempt of item are deleted.
+const retryCheckInterval = 5 * time.Second
+
+// syncGroupPolicies applies the timeout and retry policies of groups to their items,
+// and returns the duration after which job should be reconciled again, 0 if not needed.
+func (r *JobReconciler) syncGroupPolicies(ctx context.Context, job *appsv1alpha1.Job) (time.Duration, error) {
+	if len(job.Spec.Groups) == 0 {
+		return 0, nil
+	}
+
+	allStatus, err := r.Cache.getAllJobItemStatus(job.Name)
+	if err != nil {
+		return 0, fmt.Errorf("sync group policies err: %s", err.Error())
+	}
+
+	var requeueAfter time.Duration
+	requeue := func(d time.Duration) {
+		if requeueAfter == 0 || d < requeueAfter {
+			requeueAfter = d
+		}
+	}
+
+	now := time.Now()
+	items := appsv1alpha1.CalJobItems(job)
+
+	for i := range items {
+		item := &items[i]
+
+		group := appsv1alpha1.GetItemGroup(job, item)
+		if group == nil {
+			continue
+		}
+
+		status, ok := allStatus[item.Name]
+		if !ok {
+			continue
+		}
+
+		switch status.Phase {
+		case appsv1alpha1.ItemScheduling, appsv1alpha1.ItemScheduled:
+			if group.TimeoutSeconds == nil || status.StartTime == nil {
+				continue
+			}
+
+			deadline := status.StartTime.Add(time.Duration(*group.TimeoutSeconds) * time.Second)
+			if now.Before(deadline) {
+				requeue(deadline.Sub(now))
+				continue
+			}
+
+			message := fmt.Sprintf("item running longer than timeout %ds of group %s", *group.TimeoutSeconds, group.Name)
+			klog.Infof("job %s/%s item %s failed: %s", job.Namespace, job.Name, item.Name, message)
+
+			if err := r.deleteJobItemJobs(ctx, job, item, status.Iteration); err != nil {
+				return 0, fmt.Errorf("sync group policies err: %s", err.Error())
+			}
+			if err := r.Cache.setJobItemFailed(job.Name, item.Name, "Timeout", message); err != nil {
+				return 0, fmt.Errorf("sync group policies err: %s", err.Error())
+			}
+			status.Phase = appsv1alpha1.ItemFailed
+
+		case appsv1alpha1.ItemPending:
+			if status.RetryCount > 0 && status.Reason == "WaitingForPreviousAttempt" {
+				requeue(retryCheckInterval)
+			}
+		}
+
+		if status.Phase != appsv1alpha1.ItemFailed || group.BackoffLimit == nil || status.RetryCount >= *group.BackoffLimit {
+			continue
+		}
+
+		message := fmt.Sprintf("retry %d of %d after %s: %s", status.RetryCount+1, *group.BackoffLimit, status.Reason, status.Message)
+		klog.Infof("job %s/%s item %s %s", job.Namespace, job.Name, item.Name, message)
+
+		if err := r.deleteJobItemJobs(ctx, job, item, status.Iteration); err != nil {
+			return 0, fmt.Errorf("sync group policies err: %s", err.Error())
+		}
+		if err := r.Cache.resetJobItemForRetry(job.Name, item.Name, message); err != nil {
+			return 0, fmt.Errorf("sync group policies err: %s", err.Error())
+		}
+		requeue(retryCheckInterval)
+	}
+
+	return requeueAfter, nil
+}
+
+// groupParallelismLimited checks whether dispatching unit exceeds the parallelism of groups,
+// groupRunning is the num of running items of each group.
+func groupParallelismLimited(job *appsv1alpha1.Job, unit []*appsv1alpha1.Item, groupRunning map[string]int32) (string, bool) {
+	unitGroupNum := map[string]int32{}
+	for _, item := range unit {
+		if group := appsv1alpha1.GetItemGroup(job, item); group != nil {
+			unitGroupNum[group.Name]++
+		}
+	}
+
+	for _, group := range job.Spec.Groups {
+		num, ok := unitGroupNum[group.Name]
+		if !ok || group.Parallelism == nil {
+			continue
+		}
+
+		if groupRunning[group.Name] > 0 && groupRunning[group.Name]+num > *group.Parallelism {
+			return group.Name, true
+		}
+	}
+
+	return "", false
+}
+
+// calGroupRunningItemNum returns the num of items in Scheduling or Scheduled phase of each group.
+func calGroupRunningItemNum(job *appsv1alpha1.Job, allStatus map[string]*appsv1alpha1.ItemStatus) map[string]int32 {
+	res := map[string]int32{}
+
+	if len(job.Spec.Groups) == 0 {
+		return res
+	}
+
+	items := appsv1alpha1.CalJobItems(job)
+	for i := range items {
+		group := appsv1alpha1.GetItemGroup(job, &items[i])
+		if group == nil {
+			continue
+		}
+
+		status, ok := allStatus[items[i].Name]
+		if !ok {
+			continue
+		}
+
+		switch status.Phase {
+		case appsv1alpha1.ItemScheduling, appsv1alpha1.ItemScheduled:
+			res[group.Name]++
+		}
+	}
+
+	return res
+}
+
+// isPreviousAttemptRunning checks whether the jobs of previous attempt of retried items in unit still exist,
+// the unit keeps pending until they are deleted.
+func (r *JobReconciler) isPreviousAttemptRunning(ctx context.Context, job *appsv1alpha1.Job,
+	unit []*appsv1alpha1.Item, allStatus map[string]*appsv1alpha1.ItemStatus) (bool, error) {
+
+	waiting := false
+	for _, item := range unit {
+		status, ok := allStatus[item.Name]
+		if !ok || status.RetryCount == 0 {
+			continue
+		}
+
+		exist, err := r.isJobItemJobsExist(ctx, job, item, status.Iteration)
+		if err != nil {
+			return false, err
+		}
+		if exist {
+			waiting = true
+			break
+		}
+	}
+
+	if !waiting {
+		return false, nil
+	}
+
+	for _, item := range unit {
+		if err := r.Cache.setJobItemPendingReason(job.Name, item.Name, "WaitingForPreviousAttempt",
+			"waiting for jobs of previous attempt to be deleted"); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// jobItemJobs returns the jobs and sub job of item in iteration, rendered by the drivers of jobs. Script jobs
+// are expanded into the kube jobs they run as, like they are created.
+func jobItemJobs(job *appsv1alpha1.Job, item *appsv1alpha1.Item, iteration int32) []client.Object {
+	var res []client.Object
+
+	item = appsv1alpha1.ExpandScriptJobs(job.Name, item)
+
+	rc := &driver.RenderContext{
+		Job:       job,
+		Item:      item,
+		Iteration: iteration,
+	}
+
+	for _, d := range driver.GetItemResourceDrivers() {
+		if d.IsModule() {
+			continue
+		}
+
+		objs, err := d.Render(rc)
+		if err != nil {
+			klog.Errorf("render %s of item %s err: %s", d.Name(), item.Name, err.Error())
+			continue
+		}
+		res = append(res, objs...)
+	}
+
+	return res
+}
+
+// deleteJobItemJobs deletes the jobs and sub job of item in iteration, modules of item are kept.
+func (r *JobReconciler) deleteJobItemJobs(ctx context.Context, job *appsv1alpha1.Job, item *appsv1alpha1.Item, iteration int32) error {
+	for _, obj := range jobItemJobs(job, item, iteration) {
+		if err := r.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("delete %s of item %s err: %s", obj.GetName(), item.Name, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// isJobItemJobsExist checks whether any job or sub job of item in iteration still exists.
+func (r *JobReconciler) isJobItemJobsExist(ctx context.Context, job *appsv1alpha1.Job, item *appsv1alpha1.Item, iteration int32) (bool, error) {
+	for _, obj := range jobItemJobs(job, item, iteration) {
+		if err := r.Get(ctx, types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, obj); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return false, fmt.Errorf("get %s of item %s err: %s", obj.GetName(), item.Name, err.Error())
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
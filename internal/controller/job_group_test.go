@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"context"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+	"time"
+)
+
+func TestSyncGroupPolicies(t *testing.T) {
+	timeout, backoffLimit := int64(60), int32(1)
+	started := func(d time.Duration) *metav1.Time {
+		start := metav1.NewTime(time.Now().Add(-d))
+		return &start
+	}
+	jobKey := types.NamespacedName{Namespace: "ns", Name: appsv1alpha1.CalJobItemSubName("job", "g-x", "train")}
+
+	tests := []struct {
+		name    string
+		status  appsv1alpha1.ItemStatus
+		phase   appsv1alpha1.ItemPhase
+		reason  string
+		retries int32
+		deleted bool
+		requeue bool
+	}{
+		{
+			name:    "running within timeout",
+			status:  appsv1alpha1.ItemStatus{Phase: appsv1alpha1.ItemScheduled, StartTime: started(10 * time.Second)},
+			phase:   appsv1alpha1.ItemScheduled,
+			requeue: true,
+		},
+		{
+			name:    "timed out and retried",
+			status:  appsv1alpha1.ItemStatus{Phase: appsv1alpha1.ItemScheduled, StartTime: started(2 * time.Minute)},
+			phase:   appsv1alpha1.ItemPending,
+			reason:  "Retrying",
+			retries: 1,
+			deleted: true,
+			requeue: true,
+		},
+		{
+			name:    "timed out after retries used up",
+			status:  appsv1alpha1.ItemStatus{Phase: appsv1alpha1.ItemScheduled, StartTime: started(2 * time.Minute), RetryCount: 1},
+			phase:   appsv1alpha1.ItemFailed,
+			reason:  "Timeout",
+			retries: 1,
+			deleted: true,
+		},
+		{
+			name:    "failed and retried",
+			status:  appsv1alpha1.ItemStatus{Phase: appsv1alpha1.ItemFailed, Reason: "BackoffLimitExceeded"},
+			phase:   appsv1alpha1.ItemPending,
+			reason:  "Retrying",
+			retries: 1,
+			deleted: true,
+			requeue: true,
+		},
+		{
+			name:    "failed after retries used up",
+			status:  appsv1alpha1.ItemStatus{Phase: appsv1alpha1.ItemFailed, Reason: "BackoffLimitExceeded", RetryCount: 1},
+			phase:   appsv1alpha1.ItemFailed,
+			reason:  "BackoffLimitExceeded",
+			retries: 1,
+		},
+		{
+			name:    "waiting for previous attempt",
+			status:  appsv1alpha1.ItemStatus{Phase: appsv1alpha1.ItemPending, Reason: "WaitingForPreviousAttempt", RetryCount: 1},
+			phase:   appsv1alpha1.ItemPending,
+			reason:  "WaitingForPreviousAttempt",
+			retries: 1,
+			requeue: true,
+		},
+	}
+
+	for _, test := range tests {
+		now := metav1.Now()
+		job := &appsv1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns"},
+			Spec: appsv1alpha1.JobSpec{
+				Items: []appsv1alpha1.Item{{Name: "s"}},
+				Groups: []appsv1alpha1.ItemGroup{{
+					Name:           "g",
+					RunAfter:       []string{"s"},
+					TimeoutSeconds: &timeout,
+					BackoffLimit:   &backoffLimit,
+					Items: []appsv1alpha1.Item{{
+						Name: "x",
+						ItemJobs: appsv1alpha1.ItemJobResource{Jobs: []appsv1alpha1.ItemJobTemplate{{
+							TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "train"},
+							KubeJobSpec:      &batchv1.JobSpec{},
+						}}},
+					}},
+				}},
+			},
+		}
+		status := test.status
+		status.Name = "g-x"
+		job.Status.ItemStatus = map[string]appsv1alpha1.ItemStatus{
+			"s":   {Name: "s", Phase: appsv1alpha1.ItemCompleted, CompletionTime: &now},
+			"g-x": status,
+		}
+
+		kubeJob := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: jobKey.Namespace, Name: jobKey.Name}}
+		c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(kubeJob).Build()
+		r := &JobReconciler{Client: c, Cache: newJobCache()}
+		if err := r.Cache.syncGraphFromJob(job); err != nil {
+			t.Fatalf("%s: sync graph err: %s", test.name, err.Error())
+		}
+
+		requeueAfter, err := r.syncGroupPolicies(context.Background(), job)
+		if err != nil {
+			t.Fatalf("%s: sync group policies err: %s", test.name, err.Error())
+		}
+		if (requeueAfter > 0) != test.requeue || requeueAfter > time.Duration(timeout)*time.Second {
+			t.Errorf("%s: expected requeue %t, got %s", test.name, test.requeue, requeueAfter)
+		}
+
+		allStatus, err := r.Cache.getAllJobItemStatus(job.Name)
+		if err != nil {
+			t.Fatalf("%s: get item status err: %s", test.name, err.Error())
+		}
+		got := allStatus["g-x"]
+		if got.Phase != test.phase || got.Reason != test.reason || got.RetryCount != test.retries {
+			t.Errorf("%s: expected %s %s retries %d, got %s %s retries %d", test.name,
+				test.phase, test.reason, test.retries, got.Phase, got.Reason, got.RetryCount)
+		}
+
+		err = c.Get(context.Background(), jobKey, &batchv1.Job{})
+		if deleted := errors.IsNotFound(err); deleted != test.deleted {
+			t.Errorf("%s: expected job deleted %t, got %v", test.name, test.deleted, err)
+		}
+	}
+}
+
+func TestSyncGroupPoliciesScriptItem(t *testing.T) {
+	timeout, backoffLimit := int64(60), int32(1)
+	start := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	jobKey := types.NamespacedName{Namespace: "ns", Name: appsv1alpha1.CalJobItemSubName("job", "g-x", "fetch")}
+	ctx := context.Background()
+
+	job := &appsv1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns"},
+		Spec: appsv1alpha1.JobSpec{Groups: []appsv1alpha1.ItemGroup{{
+			Name:           "g",
+			TimeoutSeconds: &timeout,
+			BackoffLimit:   &backoffLimit,
+			Items: []appsv1alpha1.Item{{
+				Name: "x",
+				ItemJobs: appsv1alpha1.ItemJobResource{Jobs: []appsv1alpha1.ItemJobTemplate{{
+					TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "fetch"},
+					Script:           &appsv1alpha1.ScriptJobSpec{Image: "busybox", Source: "echo fetch"},
+				}}},
+			}},
+		}}},
+	}
+	job.Status.ItemStatus = map[string]appsv1alpha1.ItemStatus{
+		"g-x": {Name: "g-x", Phase: appsv1alpha1.ItemScheduled, StartTime: &start},
+	}
+
+	kubeJob := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: jobKey.Namespace, Name: jobKey.Name}}
+	c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(kubeJob).Build()
+	r := &JobReconciler{Client: c, Cache: newJobCache()}
+	if err := r.Cache.syncGraphFromJob(job); err != nil {
+		t.Fatalf("sync graph err: %s", err.Error())
+	}
+
+	// the kube job of script blocks the retry while it exists
+	item := &appsv1alpha1.CalJobItems(job)[0]
+	allStatus, err := r.Cache.getAllJobItemStatus(job.Name)
+	if err != nil {
+		t.Fatalf("get item status err: %s", err.Error())
+	}
+	retried := *allStatus["g-x"]
+	retried.RetryCount = 1
+	waiting, err := r.isPreviousAttemptRunning(ctx, job, []*appsv1alpha1.Item{item},
+		map[string]*appsv1alpha1.ItemStatus{"g-x": &retried})
+	if err != nil {
+		t.Fatalf("check previous attempt err: %s", err.Error())
+	}
+	if !waiting {
+		t.Errorf("retried script item should wait for the kube job of previous attempt")
+	}
+
+	// the timed out item is retried, and the kube job of script is deleted
+	if _, err := r.syncGroupPolicies(ctx, job); err != nil {
+		t.Fatalf("sync group policies err: %s", err.Error())
+	}
+	allStatus, err = r.Cache.getAllJobItemStatus(job.Name)
+	if err != nil {
+		t.Fatalf("get item status err: %s", err.Error())
+	}
+	if got := allStatus["g-x"]; got.Phase != appsv1alpha1.ItemPending || got.RetryCount != 1 {
+		t.Errorf("timed out script item should be retried, got %s retries %d", got.Phase, got.RetryCount)
+	}
+	if err := c.Get(ctx, jobKey, &batchv1.Job{}); !errors.IsNotFound(err) {
+		t.Errorf("kube job of timed out script item should be deleted, got %v", err)
+	}
+
+	// once deleted, the retry is not blocked
+	waiting, err = r.isPreviousAttemptRunning(ctx, job, []*appsv1alpha1.Item{item},
+		map[string]*appsv1alpha1.ItemStatus{"g-x": &retried})
+	if err != nil {
+		t.Fatalf("check previous attempt err: %s", err.Error())
+	}
+	if waiting {
+		t.Errorf("retried script item should not wait once the kube job of previous attempt deleted")
+	}
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	v1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+// workloadGroupVersionKind is the kind of Workload, which Kueue creates for every job submitted to it.
+var workloadGroupVersionKind = schema.GroupVersionKind{Group: "kueue.x-k8s.io", Version: "v1beta1", Kind: "Workload"}
+
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads,verbs=get;list;watch
+
+// workloadAdmission returns whether Workload was admitted, and why not if it was not.
+func workloadAdmission(workload *unstructured.Unstructured) (bool, string) {
+	conditions, _, _ := unstructured.NestedSlice(workload.Object, "status", "conditions")
+
+	message := "waiting for admission of Kueue"
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		conditionType, _, _ := unstructured.NestedString(condition, "type")
+		conditionStatus, _, _ := unstructured.NestedString(condition, "status")
+		conditionMessage, _, _ := unstructured.NestedString(condition, "message")
+
+		switch conditionType {
+		case "Admitted":
+			if conditionStatus == string(metav1.ConditionTrue) {
+				return true, ""
+			}
+		case "QuotaReserved", "Evicted":
+			// the message of quota explains why admission is pending, like the lack of quota
+			if conditionMessage != "" {
+				message = conditionMessage
+			}
+		}
+	}
+
+	return false, message
+}
+
+// workloadHandler enqueues the Job whose item created the kube job or JobSet of a Workload, the Job is found
+// from cache by the name of the owner.
+func (r *JobReconciler) workloadHandler(ctx context.Context, object client.Object) []reconcile.Request {
+	workload, ok := object.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+
+	owner := metav1.GetControllerOf(workload)
+	if owner == nil {
+		return nil
+	}
+
+	switch {
+	case owner.APIVersion == v1.SchemeGroupVersion.String() && owner.Kind == "Job":
+	case owner.APIVersion == appsv1alpha1.JobSetGroupVersionKind.GroupVersion().String() && owner.Kind == appsv1alpha1.JobSetGroupVersionKind.Kind:
+	default:
+		return nil
+	}
+
+	jobName, ok := r.Cache.getJobOfItemJob(workload.GetNamespace(), owner.Name)
+	if !ok {
+		return nil
+	}
+
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      jobName,
+				Namespace: workload.GetNamespace(),
+			},
+		},
+	}
+}
+
+// syncKueueAdmission records why the scheduled items whose jobs are waiting for admission of Kueue are not
+// admitted yet, the message is read from the Workloads of these jobs.
+func (r *JobReconciler) syncKueueAdmission(ctx context.Context, job *appsv1alpha1.Job) error {
+	allStatus, err := r.Cache.getAllJobItemStatus(job.Name)
+	if err != nil {
+		return err
+	}
+
+	// item of every job waiting for admission
+	waitingItems := map[string]string{}
+	for itemName, status := range allStatus {
+		if status.Phase != appsv1alpha1.ItemScheduled {
+			continue
+		}
+
+		for objName, state := range status.JobStatus {
+			if state.Reason == appsv1alpha1.WaitingAdmissionReason {
+				waitingItems[objName] = itemName
+			}
+		}
+	}
+	if len(waitingItems) == 0 {
+		return nil
+	}
+
+	workloads := &unstructured.UnstructuredList{}
+	workloads.SetGroupVersionKind(workloadGroupVersionKind.GroupVersion().WithKind(workloadGroupVersionKind.Kind + "List"))
+	if err := r.APIReader.List(ctx, workloads, client.InNamespace(job.Namespace)); err != nil {
+		// Kueue is not installed
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+
+		return fmt.Errorf("list workloads of job %s/%s err: %s", job.Namespace, job.Name, err.Error())
+	}
+
+	for i := range workloads.Items {
+		owner := metav1.GetControllerOf(&workloads.Items[i])
+		if owner == nil {
+			continue
+		}
+
+		itemName, ok := waitingItems[owner.Name]
+		if !ok {
+			continue
+		}
+
+		admitted, message := workloadAdmission(&workloads.Items[i])
+		if admitted {
+			// the job is resumed by Kueue then, which updates its state
+			continue
+		}
+
+		if err := r.Cache.setJobItemWaitingAdmission(job.Name, itemName, owner.Name, message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getJobOfItemJob returns the Job in namespace whose items created the job of objName.
+func (c *jobCache) getJobOfItemJob(namespace, objName string) (string, bool) {
+	c.RLock()
+	defer c.RUnlock()
+
+	for jobName, graph := range c.jobItemGraphCache {
+		if graph.NameSpace == namespace && graph.HasItemJob(objName) {
+			return jobName, true
+		}
+	}
+
+	return "", false
+}
+
+// setJobItemWaitingAdmission records the message of Workload into the item whose job of objName is waiting
+// for admission.
+func (c *jobCache) setJobItemWaitingAdmission(jobName, itemName, objName, message string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	graph, ok := c.jobItemGraphCache[jobName]
+	if !ok {
+		return fmt.Errorf("not found job %s from graph", jobName)
+	}
+
+	graph.SetItemWaitingAdmission(itemName, objName, message)
+
+	return nil
+}
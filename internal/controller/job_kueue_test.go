@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"context"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func newTestWorkload(ownerName string, conditions ...interface{}) *unstructured.Unstructured {
+	workload := &unstructured.Unstructured{}
+	workload.SetGroupVersionKind(workloadGroupVersionKind)
+	workload.SetNamespace("ns")
+	workload.SetName("job-" + ownerName)
+
+	controller := true
+	workload.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: batchv1.SchemeGroupVersion.String(),
+		Kind:       "Job",
+		Name:       ownerName,
+		Controller: &controller,
+	}})
+	_ = unstructured.SetNestedSlice(workload.Object, conditions, "status", "conditions")
+
+	return workload
+}
+
+func newTestKueueJob(itemPhase appsv1alpha1.ItemPhase, state v1alpha1.JobState) *appsv1alpha1.Job {
+	job := &appsv1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns"},
+		Spec: appsv1alpha1.JobSpec{
+			KueueQueueName: "team-a",
+			Items: []appsv1alpha1.Item{{
+				Name: "a",
+				ItemJobs: appsv1alpha1.ItemJobResource{Jobs: []appsv1alpha1.ItemJobTemplate{{
+					TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "train"},
+					KubeJobSpec:      &batchv1.JobSpec{},
+				}}},
+			}},
+		},
+	}
+	job.Status.ItemStatus = map[string]appsv1alpha1.ItemStatus{
+		"a": {
+			Name:      "a",
+			Phase:     itemPhase,
+			JobStatus: map[string]v1alpha1.JobState{appsv1alpha1.CalJobItemSubName("job", "a", "train"): state},
+		},
+	}
+
+	return job
+}
+
+func TestSyncKueueAdmission(t *testing.T) {
+	kubeJobName := appsv1alpha1.CalJobItemSubName("job", "a", "train")
+	waiting := v1alpha1.JobState{Phase: v1alpha1.Pending, Reason: appsv1alpha1.WaitingAdmissionReason, Message: "Job suspended"}
+	quota := map[string]interface{}{"type": "QuotaReserved", "status": "False", "message": "insufficient quota"}
+	admitted := map[string]interface{}{"type": "Admitted", "status": "True"}
+
+	tests := []struct {
+		name      string
+		itemPhase appsv1alpha1.ItemPhase
+		state     v1alpha1.JobState
+		workload  *unstructured.Unstructured
+		reason    string
+		message   string
+	}{
+		{name: "waiting for quota", itemPhase: appsv1alpha1.ItemScheduled, state: waiting,
+			workload: newTestWorkload(kubeJobName, quota),
+			reason:   appsv1alpha1.WaitingAdmissionReason, message: "insufficient quota"},
+		{name: "waiting without condition", itemPhase: appsv1alpha1.ItemScheduled, state: waiting,
+			workload: newTestWorkload(kubeJobName),
+			reason:   appsv1alpha1.WaitingAdmissionReason, message: "waiting for admission of Kueue"},
+		{name: "admitted", itemPhase: appsv1alpha1.ItemScheduled, state: waiting,
+			workload: newTestWorkload(kubeJobName, admitted)},
+		{name: "workload of other job", itemPhase: appsv1alpha1.ItemScheduled, state: waiting,
+			workload: newTestWorkload("other", quota)},
+		{name: "job running", itemPhase: appsv1alpha1.ItemScheduled, state: v1alpha1.JobState{Phase: v1alpha1.Running},
+			workload: newTestWorkload(kubeJobName, quota)},
+	}
+
+	for _, test := range tests {
+		job := newTestKueueJob(test.itemPhase, test.state)
+
+		c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(test.workload).Build()
+		r := &JobReconciler{Client: c, APIReader: c, Cache: newJobCache()}
+		if err := r.Cache.syncGraphFromJob(job); err != nil {
+			t.Fatalf("%s: sync graph err: %s", test.name, err.Error())
+		}
+
+		if err := r.syncKueueAdmission(context.Background(), job); err != nil {
+			t.Fatalf("%s: sync admission err: %s", test.name, err.Error())
+		}
+
+		allStatus, err := r.Cache.getAllJobItemStatus(job.Name)
+		if err != nil {
+			t.Fatalf("%s: get item status err: %s", test.name, err.Error())
+		}
+		status := allStatus["a"]
+		if status.Reason != test.reason || status.Message != test.message {
+			t.Errorf("%s: expected %s/%s, got %s/%s", test.name, test.reason, test.message, status.Reason, status.Message)
+		}
+		if test.message != "" && status.JobStatus[kubeJobName].Message != test.message {
+			t.Errorf("%s: expected job message %s, got %s", test.name, test.message, status.JobStatus[kubeJobName].Message)
+		}
+	}
+}
+
+func TestWorkloadHandler(t *testing.T) {
+	kubeJobName := appsv1alpha1.CalJobItemSubName("job", "a", "train")
+	job := newTestKueueJob(appsv1alpha1.ItemScheduled, v1alpha1.JobState{Phase: v1alpha1.Pending})
+
+	c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).Build()
+	r := &JobReconciler{Client: c, APIReader: c, Cache: newJobCache()}
+	if err := r.Cache.syncGraphFromJob(job); err != nil {
+		t.Fatalf("sync graph err: %s", err.Error())
+	}
+
+	orphan := newTestWorkload(kubeJobName)
+	orphan.SetOwnerReferences(nil)
+
+	tests := []struct {
+		name     string
+		workload client.Object
+		expected string
+	}{
+		{name: "workload of item job", workload: newTestWorkload(kubeJobName), expected: "job"},
+		{name: "workload of other job", workload: newTestWorkload("other")},
+		{name: "workload without owner", workload: orphan},
+	}
+
+	for _, test := range tests {
+		requests := r.workloadHandler(context.Background(), test.workload)
+
+		name := ""
+		if len(requests) == 1 {
+			name = requests[0].Name
+		}
+		if len(requests) > 1 || name != test.expected {
+			t.Errorf("%s: expected request of %q, got %v", test.name, test.expected, requests)
+		}
+	}
+
+	// mapping events does not change the status of items
+	allStatus, err := r.Cache.getAllJobItemStatus(job.Name)
+	if err != nil {
+		t.Fatalf("get item status err: %s", err.Error())
+	}
+	if status := allStatus["a"]; status.Reason != "" || status.JobStatus[kubeJobName].Reason != "" {
+		t.Errorf("workload handler should not change status of item, got %s", status.Reason)
+	}
+}
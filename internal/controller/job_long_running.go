@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+// stopLongRunningItems tears down the workloads of long-running items once the items in their stopAfter completed,
+// and the workloads of all long-running items once job finished.
+func (r *JobReconciler) stopLongRunningItems(ctx context.Context, job *appsv1alpha1.Job) error {
+
+	allStatus, err := r.Cache.getAllJobItemStatus(job.Name)
+	if err != nil {
+		return fmt.Errorf("stop long-running items err: %s", err.Error())
+	}
+
+	jobFinished := false
+	switch job.Status.State.Phase {
+	case appsv1alpha1.Completing, appsv1alpha1.Completed, appsv1alpha1.Failed:
+		jobFinished = true
+	}
+
+	items := appsv1alpha1.CalJobItems(job)
+	for i := range items {
+		item := &items[i]
+		if item.LongRunning == nil {
+			continue
+		}
+
+		status, ok := allStatus[item.Name]
+		if !ok {
+			continue
+		}
+
+		if !jobFinished && !isStopAfterCompleted(item.LongRunning.StopAfter, allStatus) {
+			continue
+		}
+
+		for _, obj := range jobItemJobs(job, item, status.Iteration) {
+			if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+				if client.IgnoreNotFound(err) != nil {
+					return fmt.Errorf("stop long-running item %s err: %s", item.Name, err.Error())
+				}
+				continue
+			}
+
+			if obj.GetDeletionTimestamp() != nil {
+				continue
+			}
+
+			klog.Infof("job %s/%s long-running item %s stopped, delete %s", job.Namespace, job.Name, item.Name, obj.GetName())
+
+			if err := r.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+				return fmt.Errorf("delete %s of long-running item %s err: %s", obj.GetName(), item.Name, err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// isStopAfterCompleted returns true if stopAfter is set and all the items in it completed.
+func isStopAfterCompleted(stopAfter []string, allStatus map[string]*appsv1alpha1.ItemStatus) bool {
+	if len(stopAfter) == 0 {
+		return false
+	}
+
+	for _, name := range stopAfter {
+		if status, ok := allStatus[name]; !ok || status.Phase != appsv1alpha1.ItemCompleted {
+			return false
+		}
+	}
+
+	return true
+}
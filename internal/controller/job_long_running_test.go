@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+)
+
+func TestStopLongRunningItems(t *testing.T) {
+	key := types.NamespacedName{Namespace: "ns", Name: appsv1alpha1.CalJobItemSubName("job", "serve", "server")}
+
+	tests := []struct {
+		name      string
+		stopAfter []string
+		jobPhase  appsv1alpha1.JobPhase
+		status    map[string]appsv1alpha1.ItemPhase
+		deleted   bool
+	}{
+		{
+			name:      "downstream item running",
+			stopAfter: []string{"eval"},
+			jobPhase:  appsv1alpha1.Scheduled,
+			status:    map[string]appsv1alpha1.ItemPhase{"serve": appsv1alpha1.ItemCompleted, "eval": appsv1alpha1.ItemScheduled},
+		},
+		{
+			name:      "downstream item completed",
+			stopAfter: []string{"eval"},
+			jobPhase:  appsv1alpha1.Scheduled,
+			status:    map[string]appsv1alpha1.ItemPhase{"serve": appsv1alpha1.ItemCompleted, "eval": appsv1alpha1.ItemCompleted},
+			deleted:   true,
+		},
+		{
+			name:      "one of downstream items completed",
+			stopAfter: []string{"eval", "report"},
+			jobPhase:  appsv1alpha1.Scheduled,
+			status: map[string]appsv1alpha1.ItemPhase{"serve": appsv1alpha1.ItemCompleted, "eval": appsv1alpha1.ItemCompleted,
+				"report": appsv1alpha1.ItemScheduled},
+		},
+		{
+			name:      "downstream item failed",
+			stopAfter: []string{"eval"},
+			jobPhase:  appsv1alpha1.Scheduled,
+			status:    map[string]appsv1alpha1.ItemPhase{"serve": appsv1alpha1.ItemCompleted, "eval": appsv1alpha1.ItemFailed},
+		},
+		{
+			name:     "no downstream item while job running",
+			jobPhase: appsv1alpha1.Scheduled,
+			status:   map[string]appsv1alpha1.ItemPhase{"serve": appsv1alpha1.ItemCompleted, "eval": appsv1alpha1.ItemCompleted},
+		},
+		{
+			name:     "no downstream item after job completed",
+			jobPhase: appsv1alpha1.Completed,
+			status:   map[string]appsv1alpha1.ItemPhase{"serve": appsv1alpha1.ItemCompleted, "eval": appsv1alpha1.ItemCompleted},
+			deleted:  true,
+		},
+		{
+			name:      "downstream item pending after job failed",
+			stopAfter: []string{"eval"},
+			jobPhase:  appsv1alpha1.Failed,
+			status:    map[string]appsv1alpha1.ItemPhase{"serve": appsv1alpha1.ItemCompleted, "eval": appsv1alpha1.ItemPending},
+			deleted:   true,
+		},
+	}
+
+	for _, test := range tests {
+		job := &appsv1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns"},
+			Spec: appsv1alpha1.JobSpec{Items: []appsv1alpha1.Item{
+				{
+					Name: "serve",
+					LongRunning: &appsv1alpha1.LongRunningTemplate{
+						TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "server"},
+						Deployment:       &appsv1.DeploymentSpec{},
+						StopAfter:        test.stopAfter,
+					},
+				},
+				{
+					Name:     "eval",
+					RunAfter: []string{"serve"},
+					ItemJobs: appsv1alpha1.ItemJobResource{Jobs: []appsv1alpha1.ItemJobTemplate{{
+						TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "eval"},
+						KubeJobSpec:      &batchv1.JobSpec{},
+					}}},
+				},
+				{
+					Name:     "report",
+					RunAfter: []string{"serve"},
+					ItemJobs: appsv1alpha1.ItemJobResource{Jobs: []appsv1alpha1.ItemJobTemplate{{
+						TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "report"},
+						KubeJobSpec:      &batchv1.JobSpec{},
+					}}},
+				},
+			}},
+		}
+		job.Status.State.Phase = test.jobPhase
+		job.Status.ItemStatus = map[string]appsv1alpha1.ItemStatus{}
+		for _, name := range []string{"serve", "eval", "report"} {
+			phase, ok := test.status[name]
+			if !ok {
+				phase = appsv1alpha1.ItemPending
+			}
+			status := appsv1alpha1.ItemStatus{Name: name, Phase: phase}
+			if phase == appsv1alpha1.ItemCompleted || phase == appsv1alpha1.ItemFailed {
+				now := metav1.Now()
+				status.CompletionTime = &now
+			}
+			job.Status.ItemStatus[name] = status
+		}
+
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+		c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(deployment).Build()
+		r := &JobReconciler{Client: c, Cache: newJobCache()}
+		if err := r.Cache.syncGraphFromJob(job); err != nil {
+			t.Fatalf("%s: sync graph err: %s", test.name, err.Error())
+		}
+
+		if err := r.stopLongRunningItems(context.Background(), job); err != nil {
+			t.Fatalf("%s: stop long-running items err: %s", test.name, err.Error())
+		}
+
+		err := c.Get(context.Background(), key, &appsv1.Deployment{})
+		if deleted := errors.IsNotFound(err); deleted != test.deleted {
+			t.Errorf("%s: expected deployment deleted %t, got %v", test.name, test.deleted, err)
+		}
+	}
+
+	// workload already deleted is not an error
+	job := &appsv1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns"},
+		Spec: appsv1alpha1.JobSpec{Items: []appsv1alpha1.Item{{
+			Name: "serve",
+			LongRunning: &appsv1alpha1.LongRunningTemplate{
+				TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "server"},
+				Deployment:       &appsv1.DeploymentSpec{},
+			},
+		}}},
+	}
+	job.Status.State.Phase = appsv1alpha1.Completed
+	job.Status.ItemStatus = map[string]appsv1alpha1.ItemStatus{"serve": {Name: "serve", Phase: appsv1alpha1.ItemCompleted}}
+
+	r := &JobReconciler{Client: fake.NewClientBuilder().WithScheme(newTestScheme(t)).Build(), Cache: newJobCache()}
+	if err := r.Cache.syncGraphFromJob(job); err != nil {
+		t.Fatalf("sync graph err: %s", err.Error())
+	}
+	if err := r.stopLongRunningItems(context.Background(), job); err != nil {
+		t.Errorf("stop long-running item without workload err: %s", err.Error())
+	}
+}
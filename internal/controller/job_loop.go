@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"fmt"
+	"k8s.io/klog/v2"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+// syncItemLoops records the iterations of loop items, and decides whether a completed loop item
+// runs its next iteration or stops, by the condition over the outputs of the iteration.
+func (r *JobReconciler) syncItemLoops(job *appsv1alpha1.Job) error {
+
+	allStatus, err := r.Cache.getAllJobItemStatus(job.Name)
+	if err != nil {
+		return fmt.Errorf("sync item loops err: %s", err.Error())
+	}
+
+	for _, item := range appsv1alpha1.CalJobItems(job) {
+		if item.Loop == nil {
+			continue
+		}
+
+		status, ok := allStatus[item.Name]
+		if !ok || (status.Phase == appsv1alpha1.ItemPending && status.Iteration == 0) {
+			continue
+		}
+
+		// outputs of completed iteration are needed by condition
+		if status.Phase != appsv1alpha1.ItemCompleted || status.Outputs == nil {
+			if status.Phase != appsv1alpha1.ItemCompleted {
+				if err := r.Cache.recordJobItemIteration(job.Name, item.Name); err != nil {
+					return fmt.Errorf("sync item loops err: %s", err.Error())
+				}
+			}
+			continue
+		}
+
+		// loop already stopped
+		if n := len(status.Iterations); n > 0 && status.Iterations[n-1].Iteration == status.Iteration &&
+			status.Iterations[n-1].Phase == appsv1alpha1.ItemCompleted {
+			continue
+		}
+
+		stop, err := appsv1alpha1.EvalItemLoopCondition(item.Loop.Condition, status.Outputs, status.Iteration)
+		if err != nil {
+			message := fmt.Sprintf("evaluate loop condition of iteration %d err: %s", status.Iteration, err.Error())
+			klog.Errorf("job %s/%s item %s %s", job.Namespace, job.Name, item.Name, message)
+
+			if err := r.Cache.setJobItemFailed(job.Name, item.Name, "LoopConditionError", message); err != nil {
+				return fmt.Errorf("sync item loops err: %s", err.Error())
+			}
+			if err := r.Cache.recordJobItemIteration(job.Name, item.Name); err != nil {
+				return fmt.Errorf("sync item loops err: %s", err.Error())
+			}
+			continue
+		}
+
+		if stop || status.Iteration+1 >= item.Loop.MaxIterations {
+			klog.Infof("job %s/%s loop item %s stopped after %d iterations", job.Namespace, job.Name, item.Name, status.Iteration+1)
+
+			if err := r.Cache.recordJobItemIteration(job.Name, item.Name); err != nil {
+				return fmt.Errorf("sync item loops err: %s", err.Error())
+			}
+			continue
+		}
+
+		message := fmt.Sprintf("iteration %d of max %d", status.Iteration+1, item.Loop.MaxIterations)
+		klog.Infof("job %s/%s loop item %s run %s", job.Namespace, job.Name, item.Name, message)
+
+		if err := r.Cache.nextJobItemIteration(job.Name, item.Name, message); err != nil {
+			return fmt.Errorf("sync item loops err: %s", err.Error())
+		}
+	}
+
+	return nil
+}
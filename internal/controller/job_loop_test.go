@@ -0,0 +1,107 @@
+package controller
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+)
+
+func TestSyncItemLoops(t *testing.T) {
+	tests := []struct {
+		name           string
+		condition      string
+		iteration      int32
+		outputs        map[string]string
+		phase          appsv1alpha1.ItemPhase
+		reason         string
+		iterationAfter int32
+		recorded       int
+	}{
+		{
+			name:           "condition holds",
+			condition:      `outputs["loss"] == "0.1"`,
+			outputs:        map[string]string{"loss": "0.1"},
+			phase:          appsv1alpha1.ItemCompleted,
+			recorded:       1,
+			iterationAfter: 0,
+		},
+		{
+			name:           "condition not hold",
+			condition:      `outputs["loss"] == "0.1"`,
+			outputs:        map[string]string{"loss": "0.5"},
+			phase:          appsv1alpha1.ItemPending,
+			reason:         "NextIteration",
+			recorded:       1,
+			iterationAfter: 1,
+		},
+		{
+			name:           "max iterations reached",
+			condition:      `outputs["loss"] == "0.1"`,
+			iteration:      2,
+			outputs:        map[string]string{"loss": "0.5"},
+			phase:          appsv1alpha1.ItemCompleted,
+			recorded:       1,
+			iterationAfter: 2,
+		},
+		{
+			name:           "condition error",
+			condition:      `outputs["loss"] == "0.1"`,
+			outputs:        map[string]string{},
+			phase:          appsv1alpha1.ItemFailed,
+			reason:         "LoopConditionError",
+			recorded:       1,
+			iterationAfter: 0,
+		},
+		{
+			name:           "outputs not collected",
+			condition:      `outputs["loss"] == "0.1"`,
+			phase:          appsv1alpha1.ItemCompleted,
+			iterationAfter: 0,
+		},
+	}
+
+	for _, test := range tests {
+		now := metav1.Now()
+		job := &appsv1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns"},
+			Spec: appsv1alpha1.JobSpec{Items: []appsv1alpha1.Item{{
+				Name: "a",
+				Loop: &appsv1alpha1.ItemLoop{Condition: test.condition, MaxIterations: 3},
+				ItemJobs: appsv1alpha1.ItemJobResource{Jobs: []appsv1alpha1.ItemJobTemplate{{
+					TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "train"},
+					KubeJobSpec:      &batchv1.JobSpec{},
+				}}},
+			}}},
+			Status: appsv1alpha1.JobStatus{ItemStatus: map[string]appsv1alpha1.ItemStatus{"a": {
+				Name:           "a",
+				Phase:          appsv1alpha1.ItemCompleted,
+				Iteration:      test.iteration,
+				Outputs:        test.outputs,
+				CompletionTime: &now,
+			}}},
+		}
+
+		r := &JobReconciler{Cache: newJobCache()}
+		if err := r.Cache.syncGraphFromJob(job); err != nil {
+			t.Fatalf("%s: sync graph err: %s", test.name, err.Error())
+		}
+
+		if err := r.syncItemLoops(job); err != nil {
+			t.Fatalf("%s: sync item loops err: %s", test.name, err.Error())
+		}
+
+		allStatus, err := r.Cache.getAllJobItemStatus(job.Name)
+		if err != nil {
+			t.Fatalf("%s: get item status err: %s", test.name, err.Error())
+		}
+		status := allStatus["a"]
+		if status.Phase != test.phase || status.Reason != test.reason || status.Iteration != test.iterationAfter {
+			t.Errorf("%s: expected %s %s iteration %d, got %s %s iteration %d", test.name,
+				test.phase, test.reason, test.iterationAfter, status.Phase, status.Reason, status.Iteration)
+		}
+		if len(status.Iterations) != test.recorded {
+			t.Errorf("%s: expected %d iterations recorded, got %v", test.name, test.recorded, status.Iterations)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+// ensureJobNetworkPolicy creates the NetworkPolicy isolating the pods of job if job is isolated.
+// The NetworkPolicy is owned by job and deleted with it. It is labeled with job but not annotated,
+// so that it is not taken as a module of any item.
+func (r *JobReconciler) ensureJobNetworkPolicy(ctx context.Context, job *appsv1alpha1.Job) error {
+	if job.Spec.NetworkIsolation == nil {
+		return nil
+	}
+
+	name := appsv1alpha1.CalJobNetworkPolicyName(job.Name)
+
+	policy := &networkingv1.NetworkPolicy{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: job.Namespace, Name: name}, policy)
+	if err == nil {
+		return nil
+	}
+	if !errors.IsNotFound(err) {
+		return fmt.Errorf("get network policy %s err: %s", name, err.Error())
+	}
+
+	policy = &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: job.Namespace,
+			Labels: map[string]string{
+				appsv1alpha1.CreateByJob: job.Name,
+			},
+		},
+		Spec: appsv1alpha1.CalJobNetworkPolicySpec(job),
+	}
+
+	if err := controllerutil.SetControllerReference(job, policy, r.Scheme); err != nil {
+		return err
+	}
+
+	if err := r.Create(ctx, policy); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("create network policy %s err: %s", name, err.Error())
+	}
+
+	klog.Infof("job %s/%s isolated by network policy %s", job.Namespace, job.Name, name)
+
+	return nil
+}
@@ -3,255 +3,367 @@ package controller
 import (
 	"context"
 	"fmt"
-	v1 "k8s.io/api/batch/v1"
-	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"songf.sh/songf/internal/driver"
 	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
-	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	"songf.sh/songf/pkg/job_graph"
+	"time"
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 )
 
-func (r *JobReconciler) createJobItem(ctx context.Context, job *appsv1alpha1.Job) error {
+// createJobItem dispatches the next items of job, the returned duration requeues job to check the items
+// pending on time again.
+func (r *JobReconciler) createJobItem(ctx context.Context, job *appsv1alpha1.Job) (time.Duration, error) {
 
-	schedulingItems, ok := r.Cache.getNextScheduleJobItem(job.Name)
-	if !ok {
-		klog.Errorf("create job item err: not find %s first item", job.Name)
+	policyName := job.Spec.SchedulingPolicy
+	if policyName == "" {
+		policyName = r.DefaultSchedulingPolicy
 	}
 
-	for _, item := range schedulingItems {
-		if err := r.createJobItemImpl(ctx, job, item); err != nil {
-			return fmt.Errorf("create job item err: %s", err.Error())
-		}
+	policy, err := job_graph.GetSchedulingPolicy(policyName)
+	if err != nil {
+		return 0, fmt.Errorf("create job item err: %s", err.Error())
 	}
 
-	return nil
-}
-
-func (r *JobReconciler) createJobItemImpl(ctx context.Context, job *appsv1alpha1.Job, item *appsv1alpha1.Item) error {
-
-	job.Status.ItemStatus[item.Name] = appsv1alpha1.ItemStatus{
-		Name:  item.Name,
-		Phase: appsv1alpha1.ItemScheduling,
+	schedulingItems, ok := r.Cache.getNextScheduleJobItem(job.Name, policy)
+	if !ok {
+		return 0, nil
 	}
 
-	baseAnnotations := job.Annotations
-	baseAnnotations[appsv1alpha1.CreateByJob] = job.Name
-	baseAnnotations[appsv1alpha1.CreateByJobItem] = item.Name
+	// pods of isolated job are isolated before the first item runs
+	if err := r.ensureJobNetworkPolicy(ctx, job); err != nil {
+		return 0, fmt.Errorf("create job item err: %s", err.Error())
+	}
 
-	baseLabels := job.Labels
-	baseLabels[appsv1alpha1.CreateByJob] = job.Name
-	baseLabels[appsv1alpha1.CreateByJobItem] = item.Name
+	budget, err := r.queueItemBudget(ctx, job)
+	if err != nil {
+		return 0, fmt.Errorf("create job item err: %s", err.Error())
+	}
 
-	expendAnnotationFn := func(extend map[string]string) map[string]string {
-		res := map[string]string{}
+	itemNum, podNum, err := r.Cache.getJobRunningItemNum(job.Name)
+	if err != nil {
+		return 0, fmt.Errorf("create job item err: %s", err.Error())
+	}
 
-		for k, v := range baseAnnotations {
-			res[k] = v
+	allStatus, err := r.Cache.getAllJobItemStatus(job.Name)
+	if err != nil {
+		return 0, fmt.Errorf("create job item err: %s", err.Error())
+	}
+	groupRunning := calGroupRunningItemNum(job, allStatus)
+
+	// items are dispatched in the order of policy, once an item is limited,
+	// the items after it keep pending too, so that they can not starve it.
+	reason, message := "", ""
+	var requeueAfter time.Duration
+	for _, unit := range coScheduleUnits(schedulingItems) {
+		unitItemNum := int32(len(unit))
+		var unitPodNum int32
+		for _, item := range unit {
+			unitPodNum += appsv1alpha1.CalItemPodNum(item)
 		}
 
-		for k, v := range extend {
-			res[k] = v
+		if reason == "" {
+			if budget >= 0 && budget < unitItemNum {
+				reason = "QueueItemLimitReached"
+				message = fmt.Sprintf("queue %s reached max running items", job.Spec.Queue)
+			} else if job.Spec.Parallelism != nil && itemNum > 0 && itemNum+unitItemNum > *job.Spec.Parallelism {
+				reason = "ParallelismLimitReached"
+				message = fmt.Sprintf("job reached parallelism %d", *job.Spec.Parallelism)
+			} else if job.Spec.MaxRunningPods != nil && podNum > 0 && podNum+unitPodNum > *job.Spec.MaxRunningPods {
+				reason = "MaxRunningPodsLimitReached"
+				message = fmt.Sprintf("job running %d pods, item %s wants %d pods, max running pods %d",
+					podNum, unit[0].Name, unitPodNum, *job.Spec.MaxRunningPods)
+			}
 		}
 
-		return res
-	}
-
-	expendLabelFn := func(extend map[string]string) map[string]string {
-		res := map[string]string{}
-
-		for k, v := range baseLabels {
-			res[k] = v
+		if reason != "" {
+			for _, item := range unit {
+				if err := r.Cache.setJobItemPendingReason(job.Name, item.Name, reason, message); err != nil {
+					return 0, fmt.Errorf("create job item err: %s", err.Error())
+				}
+			}
+			continue
 		}
 
-		for k, v := range extend {
-			res[k] = v
+		ready, dependencyRequeueAfter, err := r.isJobDependencyReady(ctx, job, unit)
+		if err != nil {
+			return 0, fmt.Errorf("create job item err: %s", err.Error())
+		}
+		requeueAfter = minRequeueAfter(requeueAfter, dependencyRequeueAfter)
+		if !ready {
+			continue
 		}
 
-		return res
-	}
-
-	var createdObj []client.Object
-
-	defer func() {
-		for _, obj := range createdObj {
-			if err := r.Delete(ctx, obj); err != nil {
-				klog.Errorf(err.Error())
+		// limits of groups only keep the unit itself pending, items of other groups can still run
+		if group, limited := groupParallelismLimited(job, unit, groupRunning); limited {
+			for _, item := range unit {
+				if err := r.Cache.setJobItemPendingReason(job.Name, item.Name, "GroupParallelismLimitReached",
+					fmt.Sprintf("group %s reached parallelism", group)); err != nil {
+					return 0, fmt.Errorf("create job item err: %s", err.Error())
+				}
 			}
+			continue
 		}
-	}()
-
-	for _, itemJob := range item.ItemJobs.Jobs {
-		// todo container extend and node name apply
 
-		if itemJob.KubeJobSpec == nil && itemJob.VolcanoJobSpec == nil {
-			return fmt.Errorf("%s k8s itemJob and volcano itemJob can not be total nil", itemJob.Name)
+		waiting, err := r.isPreviousAttemptRunning(ctx, job, unit, allStatus)
+		if err != nil {
+			return 0, fmt.Errorf("create job item err: %s", err.Error())
 		}
-
-		if itemJob.KubeJobSpec != nil && itemJob.VolcanoJobSpec != nil {
-			return fmt.Errorf("%s k8s itemJob and volcano itemJob can not be total exists", itemJob.Name)
+		if waiting {
+			continue
 		}
 
-		jobName := appsv1alpha1.CalJobItemSubName(job.Name, item.Name, itemJob.Name)
-		jobObjectMeta := metav1.ObjectMeta{
-			Name:        jobName,
-			Annotations: expendAnnotationFn(itemJob.Annotations),
-			Labels:      expendLabelFn(itemJob.Labels),
+		unit, ok, err := r.renderItemOutputs(job, unit)
+		if err != nil {
+			return 0, fmt.Errorf("create job item err: %s", err.Error())
+		}
+		if !ok {
+			continue
 		}
 
-		var job2Create client.Object
-		if itemJob.KubeJobSpec != nil {
-
-			job2Create = &v1.Job{
-				ObjectMeta: jobObjectMeta,
-				Spec:       *itemJob.KubeJobSpec,
-			}
-
-		} else if itemJob.VolcanoJobSpec != nil {
-
-			job2Create = &v1alpha1.Job{
-				ObjectMeta: jobObjectMeta,
-				Spec:       *itemJob.VolcanoJobSpec,
-			}
-
+		unit, ok, err = r.renderItemNames(job, unit, allStatus)
+		if err != nil {
+			return 0, fmt.Errorf("create job item err: %s", err.Error())
+		}
+		if !ok {
+			continue
 		}
 
-		if err := controllerutil.SetControllerReference(job, job2Create, r.Scheme); err != nil {
-			return err
+		ok, err = r.checkItemPermissions(job, unit)
+		if err != nil {
+			return 0, fmt.Errorf("create job item err: %s", err.Error())
+		}
+		if !ok {
+			continue
 		}
 
-		if err := r.Create(ctx, job2Create); err != nil {
-			return err
+		// script jobs run as kube jobs
+		for i, item := range unit {
+			unit[i] = appsv1alpha1.ExpandScriptJobs(job.Name, item)
 		}
 
-		createdObj = append(createdObj, job2Create)
+		// modules of retried items and later iterations of loop items were created by the first attempt and are kept
+		for i, item := range unit {
+			if status, ok := allStatus[item.Name]; ok && (status.RetryCount > 0 || status.Iteration > 0) {
+				unit[i] = item.DeepCopy()
+				unit[i].ItemModules = appsv1alpha1.ItemModuleResource{}
+			}
+		}
 
-	}
+		if group := unit[0].CoScheduleGroup; group != "" {
+			if err := r.createCoScheduleGroup(ctx, job, group, unit); err != nil {
+				return 0, fmt.Errorf("create job item err: %s", err.Error())
+			}
+		}
 
-	for _, service := range item.ItemModules.Services {
+		for _, item := range unit {
+			var iteration, attempt int32
+			if status, ok := allStatus[item.Name]; ok {
+				iteration = status.Iteration
+				attempt = status.RetryCount
+			}
 
-		serviceName := appsv1alpha1.CalJobItemSubName(job.Name, item.Name, service.Name)
+			if err := r.createJobItemImpl(ctx, job, item, iteration, attempt); err != nil {
+				return 0, fmt.Errorf("create job item err: %s", err.Error())
+			}
 
-		serviceObjectMeta := metav1.ObjectMeta{
-			Name:        serviceName,
-			Annotations: expendAnnotationFn(service.Annotations),
-			Labels:      expendLabelFn(service.Labels),
+			if err := r.Cache.setJobItemScheduling(job.Name, item.Name); err != nil {
+				return 0, fmt.Errorf("create job item err: %s", err.Error())
+			}
 		}
 
-		service2Create := &corev1.Service{
-			ObjectMeta: serviceObjectMeta,
-			Spec:       service.Spec,
+		for _, item := range unit {
+			if group := appsv1alpha1.GetItemGroup(job, item); group != nil {
+				groupRunning[group.Name]++
+			}
 		}
 
-		if err := controllerutil.SetControllerReference(job, service2Create, r.Scheme); err != nil {
-			return err
+		itemNum += unitItemNum
+		podNum += unitPodNum
+		if budget > 0 {
+			budget -= unitItemNum
 		}
+	}
 
-		if err := r.Create(ctx, service2Create); err != nil {
-			return err
+	changed, err := r.Cache.syncJobItemStatus(job)
+	if err != nil {
+		return 0, fmt.Errorf("create job item err: %s", err.Error())
+	}
+	if changed {
+		if err := r.updateJobStatus(ctx, job); err != nil {
+			return 0, fmt.Errorf("create job item err: %s", err.Error())
 		}
-
-		createdObj = append(createdObj, service2Create)
-
 	}
 
-	for _, cm := range item.ItemModules.ConfigMaps {
-
-		cmName := appsv1alpha1.CalJobItemSubName(job.Name, item.Name, cm.Name)
-
-		cmImpl := cm.ConfigMap.DeepCopy()
+	return requeueAfter, nil
+}
 
-		cmImpl.Labels = expendLabelFn(cm.Labels)
-		cmImpl.Annotations = expendAnnotationFn(cm.Annotations)
-		cmImpl.Name = cmName
+// coScheduleUnits groups items which must be dispatched together, keeping the order of items.
+// Members of a co-schedule group are put at the position of the first member.
+func coScheduleUnits(items []*appsv1alpha1.Item) [][]*appsv1alpha1.Item {
+	var units [][]*appsv1alpha1.Item
+	groupIndex := map[string]int{}
 
-		if err := controllerutil.SetControllerReference(job, cmImpl, r.Scheme); err != nil {
-			return err
+	for _, item := range items {
+		if item.CoScheduleGroup == "" {
+			units = append(units, []*appsv1alpha1.Item{item})
+			continue
 		}
 
-		if err := r.Create(ctx, cmImpl); err != nil {
-			return err
+		i, ok := groupIndex[item.CoScheduleGroup]
+		if !ok {
+			groupIndex[item.CoScheduleGroup] = len(units)
+			units = append(units, []*appsv1alpha1.Item{item})
+			continue
 		}
 
-		createdObj = append(createdObj, cmImpl)
-
+		units[i] = append(units[i], item)
 	}
 
-	for _, secret := range item.ItemModules.Secrets {
-
-		secretName := appsv1alpha1.CalJobItemSubName(job.Name, item.Name, secret.Name)
+	return units
+}
 
-		secretImpl := secret.Secret.DeepCopy()
+// createCoScheduleGroup creates the volcano PodGroup shared by the kube jobs of co-scheduled items. The PodGroup
+// left by an earlier dispatch of group, like the one before some members retried, is resized to items.
+func (r *JobReconciler) createCoScheduleGroup(ctx context.Context, job *appsv1alpha1.Job, group string, items []*appsv1alpha1.Item) error {
 
-		secretImpl.Labels = expendLabelFn(secret.Labels)
-		secretImpl.Annotations = expendAnnotationFn(secret.Annotations)
-		secretImpl.Name = secretName
+	minMember := calCoScheduleGroupMinMember(items)
+	if minMember == 0 {
+		return nil
+	}
 
-		if err := controllerutil.SetControllerReference(job, secretImpl, r.Scheme); err != nil {
-			return err
-		}
+	podGroup := &schedulingv1beta1.PodGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      appsv1alpha1.CalJobCoScheduleGroupName(job.Name, group),
+			Namespace: job.Namespace,
+			Annotations: map[string]string{
+				appsv1alpha1.CreateByJob: job.Name,
+			},
+			Labels: map[string]string{
+				appsv1alpha1.CreateByJob: job.Name,
+			},
+		},
+		Spec: schedulingv1beta1.PodGroupSpec{
+			MinMember: minMember,
+		},
+	}
 
-		if err := r.Create(ctx, secretImpl); err != nil {
-			return err
-		}
+	if err := controllerutil.SetControllerReference(job, podGroup, r.Scheme); err != nil {
+		return err
+	}
 
-		createdObj = append(createdObj, secretImpl)
+	err := r.Create(ctx, podGroup)
+	if err == nil {
+		return nil
+	}
+	if !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("create co-schedule group %s err: %s", podGroup.Name, err.Error())
+	}
 
+	existing := &schedulingv1beta1.PodGroup{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(podGroup), existing); err != nil {
+		return fmt.Errorf("get co-schedule group %s err: %s", podGroup.Name, err.Error())
+	}
+	if existing.Spec.MinMember == minMember {
+		return nil
 	}
 
-	for _, pv := range item.ItemModules.Pvs {
+	existing.Spec.MinMember = minMember
+	if err := r.Update(ctx, existing); err != nil {
+		return fmt.Errorf("resize co-schedule group %s err: %s", podGroup.Name, err.Error())
+	}
 
-		pvName := appsv1alpha1.CalJobItemSubName(job.Name, item.Name, pv.Name)
+	return nil
+}
 
-		pvObjectMeta := metav1.ObjectMeta{
-			Name:        pvName,
-			Annotations: expendAnnotationFn(pv.Annotations),
-			Labels:      expendLabelFn(pv.Labels),
-		}
+// calCoScheduleGroupMinMember returns the num of pods of the kube jobs of items, all of which must be
+// scheduled together.
+func calCoScheduleGroupMinMember(items []*appsv1alpha1.Item) int32 {
+	var res int32
+	for _, item := range items {
+		for _, itemJob := range item.ItemJobs.Jobs {
+			if itemJob.KubeJobSpec == nil {
+				continue
+			}
 
-		pv2Create := &corev1.PersistentVolume{
-			ObjectMeta: pvObjectMeta,
-			Spec:       pv.Pv,
+			if itemJob.KubeJobSpec.Parallelism != nil {
+				res += *itemJob.KubeJobSpec.Parallelism
+			} else {
+				res++
+			}
 		}
+	}
 
-		if err := controllerutil.SetControllerReference(job, pv2Create, r.Scheme); err != nil {
-			return err
-		}
+	return res
+}
 
-		if err := r.Create(ctx, pv2Create); err != nil {
-			return err
-		}
+func (r *JobReconciler) createJobItemImpl(ctx context.Context, job *appsv1alpha1.Job, item *appsv1alpha1.Item, iteration, attempt int32) (err error) {
 
-		createdObj = append(createdObj, pv2Create)
+	// value sources, like secrets in other namespaces, are not watched and read from api server
+	rc := &driver.RenderContext{
+		Job:       job,
+		Item:      item,
+		Iteration: iteration,
+		Attempt:   attempt,
+		Reader:    r.APIReader,
+	}
 
+	if hasEncryptedSecrets(item) {
+		key, err := r.loadSealKey(ctx)
+		if err != nil {
+			return fmt.Errorf("item %s err: %s", item.Name, err.Error())
+		}
+		rc.SealKey = key
 	}
 
-	for _, pvc := range item.ItemModules.Pvcs {
+	type createdObject struct {
+		driver driver.ItemResourceDriver
+		obj    client.Object
+	}
+	var createdObj []createdObject
 
-		pvcName := appsv1alpha1.CalJobItemSubName(job.Name, item.Name, pvc.Name)
+	// clean up created objects if item can not be created completely
+	defer func() {
+		if err == nil {
+			return
+		}
 
-		pvcObjectMeta := metav1.ObjectMeta{
-			Name:        pvcName,
-			Annotations: expendAnnotationFn(pvc.Annotations),
-			Labels:      expendLabelFn(pvc.Labels),
+		for _, created := range createdObj {
+			if err := created.driver.Delete(ctx, r.Client, created.obj); err != nil {
+				klog.Errorf(err.Error())
+			}
 		}
+	}()
+
+	for _, itemJob := range item.ItemJobs.Jobs {
+		// todo container extend and node name apply
 
-		pvc2Create := &corev1.PersistentVolumeClaim{
-			ObjectMeta: pvcObjectMeta,
-			Spec:       pvc.Pvc,
+		if itemJob.KubeJobSpec == nil && itemJob.VolcanoJobSpec == nil && itemJob.Unstructured == nil && itemJob.JobSetSpec == nil {
+			return fmt.Errorf("%s k8s itemJob, volcano itemJob, unstructured itemJob and jobset can not be total nil", itemJob.Name)
 		}
 
-		if err := controllerutil.SetControllerReference(job, pvc2Create, r.Scheme); err != nil {
-			return err
+		if itemJob.KubeJobSpec != nil && itemJob.VolcanoJobSpec != nil {
+			return fmt.Errorf("%s k8s itemJob and volcano itemJob can not be total exists", itemJob.Name)
 		}
+	}
 
-		if err := r.Create(ctx, pvc2Create); err != nil {
-			return err
+	for _, d := range driver.GetItemResourceDrivers() {
+		objs, err := d.Render(rc)
+		if err != nil {
+			return fmt.Errorf("render %s of item %s err: %s", d.Name(), item.Name, err.Error())
 		}
 
-		createdObj = append(createdObj, pvc2Create)
+		for _, obj := range objs {
+			if err := d.Create(ctx, r.Client, job, obj); err != nil {
+				return err
+			}
 
+			createdObj = append(createdObj, createdObject{driver: d, obj: obj})
+		}
 	}
 
 	return nil
@@ -275,3 +387,38 @@ func (r *JobReconciler) deleteJob(ctx context.Context, job *appsv1alpha1.Job) er
 
 	return nil
 }
+
+// deleteJobClusterModules deletes the cluster scoped modules of job, like pv, which can not be owned by job.
+func (r *JobReconciler) deleteJobClusterModules(ctx context.Context, job *appsv1alpha1.Job) error {
+	items := appsv1alpha1.CalJobItems(job)
+	for i := range items {
+		item := &items[i]
+		rc := &driver.RenderContext{
+			Job:  job,
+			Item: item,
+		}
+
+		for _, d := range driver.GetItemResourceDrivers() {
+			if !d.IsModule() {
+				continue
+			}
+
+			objs, err := d.Render(rc)
+			if err != nil {
+				return fmt.Errorf("render %s of item %s err: %s", d.Name(), item.Name, err.Error())
+			}
+
+			for _, obj := range objs {
+				if obj.GetNamespace() != "" {
+					continue
+				}
+
+				if err := d.Delete(ctx, r.Client, obj); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+)
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme err: %s", err.Error())
+	}
+	if err := appsv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme err: %s", err.Error())
+	}
+	if err := schedulingv1beta1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme err: %s", err.Error())
+	}
+
+	return scheme
+}
+
+func newTestCoScheduleItem(name string, parallelism *int32) *appsv1alpha1.Item {
+	return &appsv1alpha1.Item{
+		Name:            name,
+		CoScheduleGroup: "g",
+		ItemJobs: appsv1alpha1.ItemJobResource{Jobs: []appsv1alpha1.ItemJobTemplate{{
+			TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "train"},
+			KubeJobSpec:      &batchv1.JobSpec{Parallelism: parallelism},
+		}}},
+	}
+}
+
+func TestCreateCoScheduleGroup(t *testing.T) {
+	two := int32(2)
+	job := &appsv1alpha1.Job{ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns", UID: "uid"}}
+	key := types.NamespacedName{Namespace: "ns", Name: appsv1alpha1.CalJobCoScheduleGroupName("job", "g")}
+
+	scheme := newTestScheme(t)
+	r := &JobReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Scheme: scheme}
+	ctx := context.Background()
+
+	// items without kube jobs need no PodGroup
+	if err := r.createCoScheduleGroup(ctx, job, "g", []*appsv1alpha1.Item{{Name: "a", CoScheduleGroup: "g"}}); err != nil {
+		t.Fatalf("create co-schedule group err: %s", err.Error())
+	}
+	if err := r.Get(ctx, key, &schedulingv1beta1.PodGroup{}); err == nil {
+		t.Errorf("PodGroup of min member 0 should not be created")
+	}
+
+	items := []*appsv1alpha1.Item{newTestCoScheduleItem("a", &two), newTestCoScheduleItem("b", nil)}
+	if err := r.createCoScheduleGroup(ctx, job, "g", items); err != nil {
+		t.Fatalf("create co-schedule group err: %s", err.Error())
+	}
+	podGroup := &schedulingv1beta1.PodGroup{}
+	if err := r.Get(ctx, key, podGroup); err != nil {
+		t.Fatalf("get PodGroup err: %s", err.Error())
+	}
+	if podGroup.Spec.MinMember != 3 {
+		t.Errorf("expected min member 3, got %d", podGroup.Spec.MinMember)
+	}
+
+	// only b is retried, the PodGroup is resized to its pods
+	if err := r.createCoScheduleGroup(ctx, job, "g", items[1:]); err != nil {
+		t.Fatalf("create co-schedule group err: %s", err.Error())
+	}
+	if err := r.Get(ctx, key, podGroup); err != nil {
+		t.Fatalf("get PodGroup err: %s", err.Error())
+	}
+	if podGroup.Spec.MinMember != 1 {
+		t.Errorf("expected min member resized to 1, got %d", podGroup.Spec.MinMember)
+	}
+}
+
+func TestCreateJobItemImpl(t *testing.T) {
+	item := &appsv1alpha1.Item{
+		Name: "a",
+		ItemModules: appsv1alpha1.ItemModuleResource{ConfigMaps: []appsv1alpha1.ConfigMapTemplate{{
+			TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "config"},
+		}}},
+		ItemJobs: appsv1alpha1.ItemJobResource{Jobs: []appsv1alpha1.ItemJobTemplate{{
+			TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "train"},
+			KubeJobSpec:      &batchv1.JobSpec{},
+		}}},
+	}
+	configKey := types.NamespacedName{Namespace: "ns", Name: appsv1alpha1.CalJobItemSubName("job", "a", "config")}
+
+	for _, failJob := range []bool{false, true} {
+		job := &appsv1alpha1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name:      "job",
+			Namespace: "ns",
+			UID:       "uid",
+			Labels:    map[string]string{"team": "a"},
+		}}
+
+		scheme := newTestScheme(t)
+		c := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if _, ok := obj.(*batchv1.Job); ok && failJob {
+					return fmt.Errorf("quota exceeded")
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).Build()
+		r := &JobReconciler{Client: c, Scheme: scheme}
+
+		err := r.createJobItemImpl(context.Background(), job, item, 0, 0)
+		if (err != nil) != failJob {
+			t.Fatalf("fail job %t: unexpected err %v", failJob, err)
+		}
+
+		// objects created are kept unless item can not be created completely
+		cm := &corev1.ConfigMap{}
+		if getErr := c.Get(context.Background(), configKey, cm); (getErr == nil) == failJob {
+			t.Errorf("fail job %t: configmap kept %t", failJob, getErr == nil)
+		}
+
+		if len(job.Labels) != 1 || len(job.Annotations) != 0 {
+			t.Errorf("fail job %t: meta of job changed by creating item, got %v %v", failJob, job.Labels, job.Annotations)
+		}
+		if len(job.Status.ItemStatus) != 0 {
+			t.Errorf("fail job %t: status of item should be set by cache, got %v", failJob, job.Status.ItemStatus)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	v1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"sort"
+	"strings"
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+// collectItemOutputs collects the outputs of completed items, so that the items after them can reference them.
+// Completed items whose outputs referenced by the job can not be read are failed, as their pods or sub job may
+// be deleted already. It returns true if outputs of any item are collected.
+func (r *JobReconciler) collectItemOutputs(ctx context.Context, job *appsv1alpha1.Job) (bool, error) {
+
+	items, err := r.Cache.getJobItemsToCollectOutputs(job.Name)
+	if err != nil {
+		return false, fmt.Errorf("collect item outputs err: %s", err.Error())
+	}
+	if len(items) == 0 {
+		return false, nil
+	}
+
+	allStatus, err := r.Cache.getAllJobItemStatus(job.Name)
+	if err != nil {
+		return false, fmt.Errorf("collect item outputs err: %s", err.Error())
+	}
+
+	refs, err := appsv1alpha1.CalJobOutputReferences(job)
+	if err != nil {
+		return false, fmt.Errorf("collect item outputs err: %s", err.Error())
+	}
+
+	for _, item := range items {
+		outputs := map[string]string{}
+
+		// outputs of loop item are the outputs of its current iteration
+		var iteration int32
+		if status, ok := allStatus[item.Name]; ok {
+			iteration = status.Iteration
+		}
+
+		var notFound []string
+		for _, itemJob := range item.ItemJobs.Jobs {
+			name := appsv1alpha1.CalItemJobName(job.Name, item, itemJob.Name, iteration)
+
+			selector := client.MatchingLabels{v1.JobNameLabel: name}
+			if itemJob.VolcanoJobSpec != nil {
+				selector = client.MatchingLabels{v1alpha1.JobNameKey: name}
+			}
+
+			podNum, err := r.collectPodOutputs(ctx, job.Namespace, selector, outputs)
+			if err != nil {
+				return false, fmt.Errorf("collect item %s outputs err: %s", item.Name, err.Error())
+			}
+			if podNum == 0 {
+				notFound = append(notFound, fmt.Sprintf("succeeded pods of job %s", name))
+			}
+		}
+
+		if item.SubJob != nil {
+			subJob := &appsv1alpha1.Job{}
+			name := appsv1alpha1.CalItemJobName(job.Name, item, item.SubJob.Name, iteration)
+			if err := r.Client.Get(ctx, types.NamespacedName{Namespace: job.Namespace, Name: name}, subJob); err != nil {
+				if !errors.IsNotFound(err) {
+					return false, fmt.Errorf("collect item %s outputs err: %s", item.Name, err.Error())
+				}
+				klog.Warningf("sub job %s/%s not found while collecting outputs", job.Namespace, name)
+				notFound = append(notFound, fmt.Sprintf("sub job %s", name))
+			}
+
+			for k, v := range subJob.Status.Outputs {
+				outputs[k] = v
+			}
+		}
+
+		if err := r.Cache.setJobItemOutputs(job.Name, item.Name, outputs); err != nil {
+			return false, fmt.Errorf("collect item %s outputs err: %s", item.Name, err.Error())
+		}
+
+		var missing []string
+		for _, name := range refs[item.Name] {
+			if _, ok := outputs[name]; !ok {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		message := fmt.Sprintf("outputs %s referenced by job can not be read", strings.Join(missing, ", "))
+		if len(notFound) > 0 {
+			message = fmt.Sprintf("%s, %s not found", message, strings.Join(notFound, ", "))
+		}
+		klog.Errorf("job %s/%s item %s failed: %s", job.Namespace, job.Name, item.Name, message)
+
+		if err := r.Cache.setJobItemFailed(job.Name, item.Name, "OutputNotCollected", message); err != nil {
+			return false, fmt.Errorf("collect item %s outputs err: %s", item.Name, err.Error())
+		}
+	}
+
+	return true, nil
+}
+
+// collectPodOutputs reads the termination messages of succeeded pods, messages which are json objects
+// of strings are taken as outputs, others are ignored. It returns the num of succeeded pods.
+func (r *JobReconciler) collectPodOutputs(ctx context.Context, namespace string, selector client.MatchingLabels, outputs map[string]string) (int, error) {
+
+	// pods are not watched by operator, read them from api server directly
+	podList := &corev1.PodList{}
+	if err := r.APIReader.List(ctx, podList, client.InNamespace(namespace), selector); err != nil {
+		return 0, fmt.Errorf("list pods err: %s", err.Error())
+	}
+
+	pods := podList.Items
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+
+	podNum := 0
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+		podNum++
+
+		for _, containerStatus := range pod.Status.ContainerStatuses {
+			if containerStatus.State.Terminated == nil || containerStatus.State.Terminated.Message == "" {
+				continue
+			}
+
+			values := map[string]string{}
+			if err := json.Unmarshal([]byte(containerStatus.State.Terminated.Message), &values); err != nil {
+				continue
+			}
+
+			for k, v := range values {
+				outputs[k] = v
+			}
+		}
+	}
+
+	return podNum, nil
+}
+
+// renderItemOutputs substitutes the outputs referenced by items. Items referencing outputs not collected yet
+// are skipped, and items referencing outputs not exist are failed, then ok is false.
+func (r *JobReconciler) renderItemOutputs(job *appsv1alpha1.Job, items []*appsv1alpha1.Item) ([]*appsv1alpha1.Item, bool, error) {
+
+	outputs, err := r.Cache.getJobItemOutputs(job.Name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var res []*appsv1alpha1.Item
+	ok := true
+
+	for _, item := range items {
+		rendered, err := appsv1alpha1.RenderItemOutputs(item, outputs)
+		if err == nil {
+			res = append(res, rendered)
+			continue
+		}
+		ok = false
+
+		refs, refErr := appsv1alpha1.CalItemOutputReferences(item)
+		if refErr != nil {
+			return nil, false, refErr
+		}
+
+		collected := true
+		for _, ref := range refs {
+			if _, found := outputs[ref.ItemName]; !found {
+				collected = false
+				break
+			}
+		}
+
+		if collected {
+			klog.Errorf("job %s/%s item %s failed: %s", job.Namespace, job.Name, item.Name, err.Error())
+			if err := r.Cache.setJobItemFailed(job.Name, item.Name, "OutputNotFound", err.Error()); err != nil {
+				return nil, false, err
+			}
+			continue
+		}
+
+		if err := r.Cache.setJobItemPendingReason(job.Name, item.Name, "WaitingForOutputs",
+			"waiting for the outputs of items to be collected"); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return res, ok, nil
+}
+
+// resolveJobOutputs resolves the outputs declared by job from the outputs of its items.
+func (r *JobReconciler) resolveJobOutputs(job *appsv1alpha1.Job) error {
+	if len(job.Spec.Outputs) == 0 {
+		return nil
+	}
+
+	outputs, err := r.Cache.getJobItemOutputs(job.Name)
+	if err != nil {
+		return err
+	}
+
+	job.Status.Outputs, err = appsv1alpha1.CalJobOutputs(job, outputs)
+	return err
+}
@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"context"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+)
+
+func newTestOutputJob(reference string) *appsv1alpha1.Job {
+	now := metav1.Now()
+
+	return &appsv1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns"},
+		Spec: appsv1alpha1.JobSpec{Items: []appsv1alpha1.Item{
+			{
+				Name: "a",
+				ItemJobs: appsv1alpha1.ItemJobResource{Jobs: []appsv1alpha1.ItemJobTemplate{{
+					TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "train"},
+					KubeJobSpec:      &batchv1.JobSpec{},
+				}}},
+			},
+			{
+				Name:     "b",
+				RunAfter: []string{"a"},
+				ItemJobs: appsv1alpha1.ItemJobResource{Jobs: []appsv1alpha1.ItemJobTemplate{{
+					TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "serve", Labels: map[string]string{"model": reference}},
+					KubeJobSpec:      &batchv1.JobSpec{},
+				}}},
+			},
+		}},
+		Status: appsv1alpha1.JobStatus{
+			State: appsv1alpha1.JobState{Phase: appsv1alpha1.Scheduled},
+			ItemStatus: map[string]appsv1alpha1.ItemStatus{
+				"a": {Name: "a", Phase: appsv1alpha1.ItemCompleted, CompletionTime: &now},
+			},
+		},
+	}
+}
+
+func newTestOutputPod(message string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "train-0",
+			Namespace: "ns",
+			Labels:    map[string]string{batchv1.JobNameLabel: appsv1alpha1.CalJobItemSubName("job", "a", "train")},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodSucceeded,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: message}},
+			}},
+		},
+	}
+}
+
+func TestCollectItemOutputs(t *testing.T) {
+	tests := []struct {
+		name      string
+		reference string
+		pods      []client.Object
+		outputs   map[string]string
+		failed    bool
+	}{
+		{
+			name:      "referenced output collected",
+			reference: "$(items.a.outputs.model)",
+			pods:      []client.Object{newTestOutputPod(`{"model":"m1"}`)},
+			outputs:   map[string]string{"model": "m1"},
+		},
+		{
+			name:      "referenced output not written",
+			reference: "$(items.a.outputs.model)",
+			pods:      []client.Object{newTestOutputPod(`{"other":"o"}`)},
+			outputs:   map[string]string{"other": "o"},
+			failed:    true,
+		},
+		{
+			name:      "pods deleted before referenced output collected",
+			reference: "$(items.a.outputs.model)",
+			outputs:   map[string]string{},
+			failed:    true,
+		},
+		{
+			name:      "pods deleted but no output referenced",
+			reference: "none",
+			outputs:   map[string]string{},
+		},
+	}
+
+	for _, test := range tests {
+		job := newTestOutputJob(test.reference)
+
+		c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(test.pods...).Build()
+		r := &JobReconciler{Client: c, APIReader: c, Cache: newJobCache()}
+		if err := r.Cache.syncGraphFromJob(job); err != nil {
+			t.Fatalf("%s: sync graph err: %s", test.name, err.Error())
+		}
+
+		collected, err := r.collectItemOutputs(context.Background(), job)
+		if err != nil || !collected {
+			t.Fatalf("%s: expected outputs collected, got %t %v", test.name, collected, err)
+		}
+
+		allStatus, err := r.Cache.getAllJobItemStatus(job.Name)
+		if err != nil {
+			t.Fatalf("%s: get item status err: %s", test.name, err.Error())
+		}
+
+		status := allStatus["a"]
+		if len(status.Outputs) != len(test.outputs) || status.Outputs == nil {
+			t.Errorf("%s: expected outputs %v, got %v", test.name, test.outputs, status.Outputs)
+		}
+		for k, v := range test.outputs {
+			if status.Outputs[k] != v {
+				t.Errorf("%s: expected outputs %v, got %v", test.name, test.outputs, status.Outputs)
+			}
+		}
+
+		if failed := status.Phase == appsv1alpha1.ItemFailed; failed != test.failed {
+			t.Errorf("%s: expected failed %t, got phase %s reason %s", test.name, test.failed, status.Phase, status.Reason)
+		}
+		if test.failed && status.Reason != "OutputNotCollected" {
+			t.Errorf("%s: unexpected reason %s", test.name, status.Reason)
+		}
+
+		// items whose outputs are collected are not collected again
+		if collected, _ := r.collectItemOutputs(context.Background(), job); collected {
+			t.Errorf("%s: outputs should be collected once", test.name)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"k8s.io/klog/v2"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+// checkItemPermissions fails the items creating what operator does not allow, then ok is false: unstructured
+// jobs of kinds not allowed, and role bindings referring to cluster roles not allowed. Jobs are checked by
+// webhook too, this keeps Jobs admitted before the settings of operator changed from creating them.
+func (r *JobReconciler) checkItemPermissions(job *appsv1alpha1.Job, items []*appsv1alpha1.Item) (bool, error) {
+	ok := true
+
+	for _, item := range items {
+		reason := ""
+		flag, msg := appsv1alpha1.IsItemUnstructuredJobAllowed(item, r.UnstructuredJobKinds, r.RESTMapper())
+		if !flag {
+			reason = "UnstructuredJobNotAllowed"
+		} else if flag, msg = appsv1alpha1.IsItemRoleBindingAllowed(item, r.RoleBindingClusterRoles); !flag {
+			reason = "RoleBindingNotAllowed"
+		}
+		if reason == "" {
+			continue
+		}
+		ok = false
+
+		klog.Errorf("job %s/%s item %s failed: %s", job.Namespace, job.Name, item.Name, msg)
+		if err := r.Cache.setJobItemFailed(job.Name, item.Name, reason, msg); err != nil {
+			return false, err
+		}
+	}
+
+	return ok, nil
+}
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"songf.sh/songf/pkg/job_queue"
+)
+
+func listQueueJobs(ctx context.Context, c client.Client, queueName string) ([]*appsv1alpha1.Job, error) {
+	jobList := &appsv1alpha1.JobList{}
+	if err := c.List(ctx, jobList); err != nil {
+		return nil, fmt.Errorf("list jobs of queue %s err: %s", queueName, err.Error())
+	}
+
+	var res []*appsv1alpha1.Job
+	for i := range jobList.Items {
+		if jobList.Items[i].Spec.Queue == queueName {
+			res = append(res, &jobList.Items[i])
+		}
+	}
+
+	return res, nil
+}
+
+// admitJob tries to admit a queued job, and records its position in queue if not admitted.
+func (r *JobReconciler) admitJob(ctx context.Context, job *appsv1alpha1.Job) (bool, error) {
+
+	oldState := job.Status.State
+	var oldPosition *int32
+	if job.Status.QueuePosition != nil {
+		oldPosition = new(int32)
+		*oldPosition = *job.Status.QueuePosition
+	}
+
+	queue := &appsv1alpha1.Queue{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: job.Spec.Queue}, queue); err != nil {
+		if !errors.IsNotFound(err) {
+			return false, fmt.Errorf("get queue %s err: %s", job.Spec.Queue, err.Error())
+		}
+
+		job.Status.State.Reason = "QueueNotFound"
+		job.Status.State.Message = fmt.Sprintf("queue %s not found", job.Spec.Queue)
+		job.Status.QueuePosition = nil
+	} else {
+		jobs, err := listQueueJobs(ctx, r.Client, queue.Name)
+		if err != nil {
+			return false, err
+		}
+
+		usage := job_queue.CalUsage(jobs)
+
+		var queued []*appsv1alpha1.Job
+		found := false
+		for _, queueJob := range jobs {
+			if queueJob.Namespace == job.Namespace && queueJob.Name == job.Name {
+				queueJob = job
+				found = true
+			}
+
+			if job_queue.IsJobQueued(queueJob) {
+				queued = append(queued, queueJob)
+			}
+		}
+		if !found {
+			queued = append(queued, job)
+		}
+
+		var position int32
+		for i, queueJob := range job_queue.OrderQueuedJobs(queue, usage, queued) {
+			if queueJob.Namespace == job.Namespace && queueJob.Name == job.Name {
+				position = int32(i + 1)
+				break
+			}
+		}
+
+		job.Status.State.Reason = "Queued"
+		job.Status.State.Message = fmt.Sprintf("job waiting in queue %s", queue.Name)
+
+		if position == 1 {
+			ok, reason := job_queue.CanAdmit(queue, usage, job)
+			if ok {
+				klog.Infof("job %s/%s admitted by queue %s", job.Namespace, job.Name, queue.Name)
+
+				job.Status.State.Phase = appsv1alpha1.Scheduled
+				job.Status.State.Reason = "Admitted"
+				job.Status.State.Message = fmt.Sprintf("job admitted by queue %s", queue.Name)
+				job.Status.QueuePosition = nil
+
+				if err := r.updateJobStatus(ctx, job); err != nil {
+					return false, err
+				}
+
+				return true, nil
+			}
+
+			job.Status.State.Message = reason
+		}
+
+		job.Status.QueuePosition = &position
+	}
+
+	if !apiequality.Semantic.DeepEqual(oldState, job.Status.State) ||
+		!apiequality.Semantic.DeepEqual(oldPosition, job.Status.QueuePosition) {
+		if err := r.updateJobStatus(ctx, job); err != nil {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// queueItemBudget returns the num of items job can dispatch without exceeding its queue, -1 means not limited.
+func (r *JobReconciler) queueItemBudget(ctx context.Context, job *appsv1alpha1.Job) (int32, error) {
+	if job.Spec.Queue == "" {
+		return -1, nil
+	}
+
+	queue := &appsv1alpha1.Queue{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: job.Spec.Queue}, queue); err != nil {
+		if errors.IsNotFound(err) {
+			return -1, nil
+		}
+		return 0, fmt.Errorf("get queue %s err: %s", job.Spec.Queue, err.Error())
+	}
+
+	jobs, err := listQueueJobs(ctx, r.Client, queue.Name)
+	if err != nil {
+		return 0, err
+	}
+
+	return job_queue.ItemBudget(queue, job_queue.CalUsage(jobs)), nil
+}
+
+// queueHandler enqueues the queued jobs of a queue while the queue changed.
+func (r *JobReconciler) queueHandler(ctx context.Context, object client.Object) []reconcile.Request {
+
+	jobs, err := listQueueJobs(ctx, r.Client, object.GetName())
+	if err != nil {
+		klog.Errorf(err.Error())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, job := range jobs {
+		if !job_queue.IsJobQueued(job) {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      job.Name,
+				Namespace: job.Namespace,
+			},
+		})
+	}
+
+	return requests
+}
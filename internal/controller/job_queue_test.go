@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+	"time"
+)
+
+func newTestQueueJob(name, cpu string, created time.Time, phase appsv1alpha1.JobPhase) *appsv1alpha1.Job {
+	job := &appsv1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", CreationTimestamp: metav1.NewTime(created)},
+		Spec: appsv1alpha1.JobSpec{
+			Queue: "q",
+			Items: []appsv1alpha1.Item{{
+				Name: "a",
+				ItemJobs: appsv1alpha1.ItemJobResource{Jobs: []appsv1alpha1.ItemJobTemplate{{
+					TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "train"},
+					KubeJobSpec: &batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+						}}},
+					}}},
+				}}},
+			}},
+		},
+	}
+	job.Status.State.Phase = phase
+
+	return job
+}
+
+func TestAdmitJobHeadOfLine(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	queue := &appsv1alpha1.Queue{
+		ObjectMeta: metav1.ObjectMeta{Name: "q"},
+		Spec:       appsv1alpha1.QueueSpec{Quota: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}},
+	}
+	running := newTestQueueJob("running", "2", now, appsv1alpha1.Scheduled)
+	big := newTestQueueJob("big", "4", now.Add(time.Second), appsv1alpha1.Queued)
+	small := newTestQueueJob("small", "1", now.Add(2*time.Second), appsv1alpha1.Queued)
+
+	c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).
+		WithObjects(queue, running, big, small).WithStatusSubresource(&appsv1alpha1.Job{}).Build()
+	r := &JobReconciler{Client: c}
+
+	tests := []struct {
+		name     string
+		job      *appsv1alpha1.Job
+		admitted bool
+		position int32
+	}{
+		// the head of queue does not fit the quota left, and blocks the jobs after it
+		{name: "head not fit", job: big, position: 1},
+		{name: "job after head fits", job: small, position: 2},
+	}
+
+	for _, test := range tests {
+		job := &appsv1alpha1.Job{}
+		if err := c.Get(context.Background(), client.ObjectKeyFromObject(test.job), job); err != nil {
+			t.Fatalf("%s: get job err: %s", test.name, err.Error())
+		}
+
+		admitted, err := r.admitJob(context.Background(), job)
+		if err != nil {
+			t.Fatalf("%s: admit job err: %s", test.name, err.Error())
+		}
+		if admitted != test.admitted {
+			t.Errorf("%s: expected admitted %t, got %t", test.name, test.admitted, admitted)
+		}
+		if job.Status.QueuePosition == nil || *job.Status.QueuePosition != test.position {
+			t.Errorf("%s: expected position %d, got %v", test.name, test.position, job.Status.QueuePosition)
+		}
+	}
+
+	// once the running job finishes, the head is admitted
+	running.Status.State.Phase = appsv1alpha1.Completed
+	if err := c.Status().Update(context.Background(), running); err != nil {
+		t.Fatalf("update job status err: %s", err.Error())
+	}
+
+	job := &appsv1alpha1.Job{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(big), job); err != nil {
+		t.Fatalf("get job err: %s", err.Error())
+	}
+	if admitted, err := r.admitJob(context.Background(), job); err != nil || !admitted {
+		t.Errorf("expected head admitted, got %t %v", admitted, err)
+	}
+	if job.Status.State.Phase != appsv1alpha1.Scheduled || job.Status.QueuePosition != nil {
+		t.Errorf("admitted job should be scheduled without position, got %s %v", job.Status.State.Phase, job.Status.QueuePosition)
+	}
+}
@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"k8s.io/klog/v2"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+// renderItemNames substitutes the generated names referenced by items, jobs of loop items are named by their
+// current iterations. Items referencing names not resolved are failed, then ok is false.
+func (r *JobReconciler) renderItemNames(job *appsv1alpha1.Job, items []*appsv1alpha1.Item,
+	allStatus map[string]*appsv1alpha1.ItemStatus) ([]*appsv1alpha1.Item, bool, error) {
+
+	iterations := map[string]int32{}
+	for name, status := range allStatus {
+		iterations[name] = status.Iteration
+	}
+
+	var res []*appsv1alpha1.Item
+	ok := true
+
+	for _, item := range items {
+		rendered, err := appsv1alpha1.RenderItemNames(job, item, iterations)
+		if err == nil {
+			res = append(res, rendered)
+			continue
+		}
+		ok = false
+
+		klog.Errorf("job %s/%s item %s failed: %s", job.Namespace, job.Name, item.Name, err.Error())
+		if err := r.Cache.setJobItemFailed(job.Name, item.Name, "NameReferenceNotFound", err.Error()); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return res, ok, nil
+}
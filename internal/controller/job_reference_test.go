@@ -0,0 +1,119 @@
+package controller
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"strings"
+	"testing"
+)
+
+func TestRenderItemNames(t *testing.T) {
+	newItem := func(name, reference string) appsv1alpha1.Item {
+		return appsv1alpha1.Item{
+			Name:     name,
+			RunAfter: []string{"a"},
+			ItemJobs: appsv1alpha1.ItemJobResource{Jobs: []appsv1alpha1.ItemJobTemplate{{
+				TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "eval", Labels: map[string]string{"ref": reference}},
+				KubeJobSpec:      &batchv1.JobSpec{},
+			}}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		items    []appsv1alpha1.Item
+		rendered map[string]string
+		failed   map[string]string
+	}{
+		{
+			name:     "all resolved",
+			items:    []appsv1alpha1.Item{newItem("b", "$(items.a.jobs.train.name)"), newItem("c", "plain")},
+			rendered: map[string]string{"b": "job-a-train-1", "c": "plain"},
+		},
+		{
+			name:     "unknown item",
+			items:    []appsv1alpha1.Item{newItem("b", "$(items.x.jobs.train.name)"), newItem("c", "$(items.a.jobs.train.name)")},
+			rendered: map[string]string{"c": "job-a-train-1"},
+			failed:   map[string]string{"b": "item x not found"},
+		},
+		{
+			name:   "unknown name",
+			items:  []appsv1alpha1.Item{newItem("b", "$(modules.a.services.db.name)"), newItem("c", "$(items.a.jobs.db.name)")},
+			failed: map[string]string{"b": "services db of item a not found", "c": "jobs db of item a not found"},
+		},
+	}
+
+	for _, test := range tests {
+		now := metav1.Now()
+		job := &appsv1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns"},
+			Spec: appsv1alpha1.JobSpec{Items: append([]appsv1alpha1.Item{{
+				Name: "a",
+				ItemJobs: appsv1alpha1.ItemJobResource{Jobs: []appsv1alpha1.ItemJobTemplate{{
+					TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "train"},
+					KubeJobSpec:      &batchv1.JobSpec{},
+				}}},
+				Loop: &appsv1alpha1.ItemLoop{MaxIterations: 3},
+			}}, test.items...)},
+		}
+		job.Status.ItemStatus = map[string]appsv1alpha1.ItemStatus{
+			"a": {Name: "a", Phase: appsv1alpha1.ItemCompleted, CompletionTime: &now, Iteration: 1},
+		}
+		for _, item := range test.items {
+			job.Status.ItemStatus[item.Name] = appsv1alpha1.ItemStatus{Name: item.Name, Phase: appsv1alpha1.ItemPending}
+		}
+
+		r := &JobReconciler{Client: fake.NewClientBuilder().WithScheme(newTestScheme(t)).Build(), Cache: newJobCache()}
+		if err := r.Cache.syncGraphFromJob(job); err != nil {
+			t.Fatalf("%s: sync graph err: %s", test.name, err.Error())
+		}
+		allStatus, err := r.Cache.getAllJobItemStatus(job.Name)
+		if err != nil {
+			t.Fatalf("%s: get item status err: %s", test.name, err.Error())
+		}
+
+		var unit []*appsv1alpha1.Item
+		for i := range test.items {
+			unit = append(unit, &test.items[i])
+		}
+
+		res, ok, err := r.renderItemNames(job, unit, allStatus)
+		if err != nil {
+			t.Fatalf("%s: render item names err: %s", test.name, err.Error())
+		}
+		if ok != (len(test.failed) == 0) {
+			t.Errorf("%s: expected ok %t, got %t", test.name, len(test.failed) == 0, ok)
+		}
+
+		if len(res) != len(test.rendered) {
+			t.Errorf("%s: expected %d items rendered, got %d", test.name, len(test.rendered), len(res))
+		}
+		for _, item := range res {
+			if got := item.ItemJobs.Jobs[0].Labels["ref"]; got != test.rendered[item.Name] {
+				t.Errorf("%s: expected item %s rendered %s, got %s", test.name, item.Name, test.rendered[item.Name], got)
+			}
+		}
+
+		allStatus, err = r.Cache.getAllJobItemStatus(job.Name)
+		if err != nil {
+			t.Fatalf("%s: get item status err: %s", test.name, err.Error())
+		}
+		for _, item := range test.items {
+			status := allStatus[item.Name]
+			message, failed := test.failed[item.Name]
+			if !failed {
+				if status.Phase != appsv1alpha1.ItemPending {
+					t.Errorf("%s: item %s should not fail, got %s %s", test.name, item.Name, status.Phase, status.Message)
+				}
+				continue
+			}
+			if status.Phase != appsv1alpha1.ItemFailed || status.Reason != "NameReferenceNotFound" ||
+				!strings.Contains(status.Message, message) {
+				t.Errorf("%s: expected item %s failed by %s, got %s %s %s", test.name, item.Name, message,
+					status.Phase, status.Reason, status.Message)
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"songf.sh/songf/pkg/seal"
+)
+
+// hasEncryptedSecrets returns true if any secret template of item has encrypted data.
+func hasEncryptedSecrets(item *appsv1alpha1.Item) bool {
+	for _, secret := range item.ItemModules.Secrets {
+		if len(secret.EncryptedData) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// loadSealKey reads the private key decrypting the encrypted data of secrets from the Secret of operator.
+// It is read every time, so that a rotated key is used at once.
+func (r *JobReconciler) loadSealKey(ctx context.Context) (*rsa.PrivateKey, error) {
+	if r.SealKeySecret.Name == "" {
+		return nil, fmt.Errorf("no seal key secret set to decrypt encrypted data")
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.APIReader.Get(ctx, r.SealKeySecret, secret); err != nil {
+		return nil, fmt.Errorf("get seal key secret %s err: %s", r.SealKeySecret.String(), err.Error())
+	}
+
+	data, ok := secret.Data[seal.PrivateKeyKey]
+	if !ok {
+		return nil, fmt.Errorf("seal key secret %s has no %s", r.SealKeySecret.String(), seal.PrivateKeyKey)
+	}
+
+	return seal.ParsePrivateKey(data)
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"fmt"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"songf.sh/songf/internal/driver"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+// ensureUnstructuredWatches watches the kinds of unstructured jobs in job, kinds not allowed are not watched.
+func (r *JobReconciler) ensureUnstructuredWatches(job *appsv1alpha1.Job) error {
+	for _, gvk := range appsv1alpha1.GetUnstructuredJobGVKs(job) {
+		if flag, _ := appsv1alpha1.IsUnstructuredJobKindAllowed(gvk, r.UnstructuredJobKinds, r.RESTMapper()); !flag {
+			continue
+		}
+
+		if err := r.ensureUnstructuredWatch(gvk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureUnstructuredWatch registers a watch of kind once, kinds are only known after Jobs using them were submitted.
+func (r *JobReconciler) ensureUnstructuredWatch(gvk schema.GroupVersionKind) error {
+	r.watchLock.Lock()
+	defer r.watchLock.Unlock()
+
+	if r.watchedKinds[gvk] {
+		return nil
+	}
+
+	if r.controller == nil {
+		return fmt.Errorf("watch %s err: controller not set up", gvk.String())
+	}
+
+	d, err := driver.GetItemResourceDriver(driver.UnstructuredJobDriverName)
+	if err != nil {
+		return fmt.Errorf("watch %s err: %s", gvk.String(), err.Error())
+	}
+
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(gvk)
+
+	if err := r.controller.Watch(source.Kind(r.informerCache, object),
+		handler.EnqueueRequestsFromMapFunc(r.Cache.driverHandler(d)),
+		jobObjectFilter(), predicate.ResourceVersionChangedPredicate{}); err != nil {
+		return fmt.Errorf("watch %s err: %s", gvk.String(), err.Error())
+	}
+
+	r.watchedKinds[gvk] = true
+
+	return nil
+}
@@ -0,0 +1,256 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"net/http"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"sync"
+	"time"
+)
+
+// waitHTTPTimeout bounds a single request to the endpoint of wait item, including reading its response.
+const waitHTTPTimeout = 5 * time.Second
+
+// waitHTTPClient requests the endpoints of wait items, redirects are not followed, so that a wait item only
+// reaches the url it sets.
+var waitHTTPClient = &http.Client{
+	Timeout: waitHTTPTimeout,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// Objects waited by items are read with the permissions operator is granted, get on the kinds not created by
+// operator must be granted by config/rbac/wait_role.yaml. The webhook checks that the creator of Job can get them.
+
+// syncWaitItems checks the dispatched wait items, completes the ready ones and fails the ones timed out,
+// and returns the duration after which the waiting items should be checked again.
+func (r *JobReconciler) syncWaitItems(ctx context.Context, job *appsv1alpha1.Job) (time.Duration, error) {
+
+	allStatus, err := r.Cache.getAllJobItemStatus(job.Name)
+	if err != nil {
+		return 0, fmt.Errorf("sync wait items err: %s", err.Error())
+	}
+
+	var requeueAfter time.Duration
+	now := time.Now()
+
+	for _, item := range appsv1alpha1.CalJobItems(job) {
+		wait := item.Wait
+		if wait == nil {
+			continue
+		}
+
+		status, ok := allStatus[item.Name]
+		if !ok || (status.Phase != appsv1alpha1.ItemScheduling && status.Phase != appsv1alpha1.ItemScheduled) {
+			continue
+		}
+
+		ready, message, err := r.checkWait(ctx, job, &item, wait)
+		if err != nil {
+			klog.Errorf("job %s/%s wait item %s failed: %s", job.Namespace, job.Name, item.Name, err.Error())
+
+			if err := r.Cache.setJobItemFailed(job.Name, item.Name, "WaitInvalid", err.Error()); err != nil {
+				return 0, fmt.Errorf("sync wait items err: %s", err.Error())
+			}
+			continue
+		}
+
+		if ready {
+			klog.Infof("job %s/%s wait item %s ready", job.Namespace, job.Name, item.Name)
+
+			if err := r.Cache.setJobItemCompleted(job.Name, item.Name, "Ready", "waited for ready"); err != nil {
+				return 0, fmt.Errorf("sync wait items err: %s", err.Error())
+			}
+			continue
+		}
+
+		period := time.Duration(appsv1alpha1.DefaultWaitPeriodSeconds) * time.Second
+		if wait.PeriodSeconds != nil {
+			period = time.Duration(*wait.PeriodSeconds) * time.Second
+		}
+
+		if wait.TimeoutSeconds != nil && status.StartTime != nil {
+			deadline := status.StartTime.Add(time.Duration(*wait.TimeoutSeconds) * time.Second)
+			if !now.Before(deadline) {
+				message := fmt.Sprintf("not ready after %ds: %s", *wait.TimeoutSeconds, message)
+				klog.Infof("job %s/%s wait item %s %s", job.Namespace, job.Name, item.Name, message)
+
+				if err := r.Cache.setJobItemFailed(job.Name, item.Name, "WaitTimeout", message); err != nil {
+					return 0, fmt.Errorf("sync wait items err: %s", err.Error())
+				}
+				continue
+			}
+
+			if deadline.Sub(now) < period {
+				period = deadline.Sub(now)
+			}
+		}
+
+		if err := r.Cache.setJobItemReason(job.Name, item.Name, "Waiting", message); err != nil {
+			return 0, fmt.Errorf("sync wait items err: %s", err.Error())
+		}
+
+		requeueAfter = minRequeueAfter(requeueAfter, period)
+	}
+
+	return requeueAfter, nil
+}
+
+// checkWait checks whether the object or endpoint of wait is ready, the message describes why not.
+// Error is returned only if wait can never be ready.
+func (r *JobReconciler) checkWait(ctx context.Context, job *appsv1alpha1.Job, item *appsv1alpha1.Item,
+	wait *appsv1alpha1.WaitTemplate) (bool, string, error) {
+
+	if wait.HTTP != nil {
+		name, namespace, err := appsv1alpha1.ParseHTTPWaitService(wait.HTTP.URL)
+		if err != nil {
+			return false, "", err
+		}
+
+		// services in other namespaces are only requested for the namespaces they allow
+		if namespace != job.Namespace {
+			service := &corev1.Service{}
+			if err := r.APIReader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, service); err != nil {
+				if errors.IsNotFound(err) {
+					return false, fmt.Sprintf("service %s/%s not found", namespace, name), nil
+				}
+				return false, fmt.Sprintf("get service %s/%s err: %s", namespace, name, err.Error()), nil
+			}
+
+			if !appsv1alpha1.IsNamespaceAllowed(service, job.Namespace) {
+				return false, fmt.Sprintf("service %s/%s does not allow namespace %s", namespace, name, job.Namespace), nil
+			}
+		}
+
+		// endpoints are requested in background, so that a slow endpoint does not block reconciling
+		key := fmt.Sprintf("%s/%s/%s", job.Namespace, job.Name, item.Name)
+		ready, message := r.waitProber.probe(key, wait)
+		return ready, message, nil
+	}
+
+	resource := wait.Resource
+
+	namespace := resource.Namespace
+	if namespace == "" {
+		namespace = job.Namespace
+	}
+
+	gvk := schema.FromAPIVersionAndKind(resource.APIVersion, resource.Kind)
+	mapping, err := r.Client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, "", fmt.Errorf("kind %s of %s not found", resource.Kind, resource.APIVersion)
+		}
+		return false, fmt.Sprintf("get mapping of %s err: %s", gvk.String(), err.Error()), nil
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return false, "", fmt.Errorf("kind %s of %s is cluster scoped, which can not be waited for", resource.Kind, resource.APIVersion)
+	}
+
+	// objects of any kind are read from api server directly, so that no informer is started for them
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(gvk)
+	if err := r.APIReader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: resource.Name}, object); err != nil {
+		if errors.IsNotFound(err) {
+			return false, fmt.Sprintf("%s %s/%s not found", resource.Kind, namespace, resource.Name), nil
+		}
+
+		return false, fmt.Sprintf("get %s %s/%s err: %s", resource.Kind, namespace, resource.Name, err.Error()), nil
+	}
+
+	// the status of objects in other namespaces is only exposed to the namespaces they allow
+	if namespace != job.Namespace && !appsv1alpha1.IsNamespaceAllowed(object, job.Namespace) {
+		return false, fmt.Sprintf("%s %s/%s does not allow namespace %s", resource.Kind, namespace, resource.Name, job.Namespace), nil
+	}
+
+	return appsv1alpha1.CheckStatusRule(&resource.StatusRule, object)
+}
+
+// httpProbeResult is the result of requesting the endpoint of a wait item.
+type httpProbeResult struct {
+	url     string
+	ready   bool
+	message string
+	expire  time.Time
+}
+
+// httpProber requests the endpoints of wait items in background, at most one request runs for an item.
+type httpProber struct {
+	lock    sync.Mutex
+	running map[string]bool
+	results map[string]*httpProbeResult
+}
+
+// probe returns the result of the last request to the endpoint of key and starts a new one, the message
+// describes why endpoint is not ready. A result is returned once, and results not read within a period of
+// checks are dropped, like the ones of deleted Jobs.
+func (p *httpProber) probe(key string, wait *appsv1alpha1.WaitTemplate) (bool, string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.running == nil {
+		p.running = map[string]bool{}
+		p.results = map[string]*httpProbeResult{}
+	}
+
+	now := time.Now()
+	for k, result := range p.results {
+		if now.After(result.expire) {
+			delete(p.results, k)
+		}
+	}
+
+	url := wait.HTTP.URL
+	if result, ok := p.results[key]; ok {
+		delete(p.results, key)
+		if result.url == url {
+			return result.ready, result.message
+		}
+	}
+
+	if p.running[key] {
+		return false, fmt.Sprintf("requesting %s", url)
+	}
+	p.running[key] = true
+
+	period := time.Duration(appsv1alpha1.DefaultWaitPeriodSeconds) * time.Second
+	if wait.PeriodSeconds != nil {
+		period = time.Duration(*wait.PeriodSeconds) * time.Second
+	}
+
+	go func() {
+		ready, message := requestHTTPWait(wait.HTTP)
+
+		p.lock.Lock()
+		defer p.lock.Unlock()
+
+		delete(p.running, key)
+		p.results[key] = &httpProbeResult{url: url, ready: ready, message: message, expire: time.Now().Add(2 * period)}
+	}()
+
+	return false, fmt.Sprintf("requesting %s", url)
+}
+
+// requestHTTPWait requests the endpoint of wait, the message describes why it is not ready.
+func requestHTTPWait(wait *appsv1alpha1.HTTPWait) (bool, string) {
+	resp, err := waitHTTPClient.Get(wait.URL)
+	if err != nil {
+		return false, fmt.Sprintf("request %s err: %s", wait.URL, err.Error())
+	}
+	resp.Body.Close()
+
+	if !appsv1alpha1.IsHTTPWaitSucceeded(wait, resp.StatusCode) {
+		return false, fmt.Sprintf("%s returned %d", wait.URL, resp.StatusCode)
+	}
+
+	return true, ""
+}
@@ -0,0 +1,219 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
+	"net/http/httptest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"strings"
+	"testing"
+	"time"
+)
+
+// probeUntilDone probes key until a request finished, and returns its result.
+func probeUntilDone(t *testing.T, p *httpProber, key string, wait *appsv1alpha1.WaitTemplate) (bool, string) {
+	deadline := time.Now().Add(waitHTTPTimeout)
+	for time.Now().Before(deadline) {
+		p.lock.Lock()
+		_, done := p.results[key]
+		p.lock.Unlock()
+
+		if done {
+			return p.probe(key, wait)
+		}
+
+		p.probe(key, wait)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("probe %s not finished", key)
+	return false, ""
+}
+
+func TestHTTPProber(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/starting", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ready", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		path  string
+		codes []int32
+		ready bool
+	}{
+		{path: "/ready", ready: true},
+		{path: "/starting"},
+		// redirects are not followed
+		{path: "/redirect"},
+		{path: "/redirect", codes: []int32{http.StatusFound}, ready: true},
+	}
+
+	p := &httpProber{}
+	for i, test := range tests {
+		wait := &appsv1alpha1.WaitTemplate{HTTP: &appsv1alpha1.HTTPWait{URL: server.URL + test.path, SuccessCodes: test.codes}}
+		key := "ns/job/" + string(rune('a'+i))
+
+		// the first probe only starts the request
+		if ready, _ := p.probe(key, wait); ready {
+			t.Errorf("%s: first probe should not be ready", test.path)
+		}
+
+		if ready, message := probeUntilDone(t, p, key, wait); ready != test.ready {
+			t.Errorf("%s: expected ready %t, got %t: %s", test.path, test.ready, ready, message)
+		}
+	}
+}
+
+func TestHTTPProberDropsStaleResult(t *testing.T) {
+	p := &httpProber{
+		running: map[string]bool{},
+		results: map[string]*httpProbeResult{
+			"ns/job/expired": {url: "http://a", ready: true, expire: time.Now().Add(-time.Second)},
+			"ns/job/changed": {url: "http://a", ready: true, expire: time.Now().Add(time.Minute)},
+		},
+	}
+
+	// requests to the unreachable url fail in background, results of them are not checked here
+	if ready, _ := p.probe("ns/job/expired", &appsv1alpha1.WaitTemplate{HTTP: &appsv1alpha1.HTTPWait{URL: "http://a"}}); ready {
+		t.Errorf("expired result should be dropped")
+	}
+	if ready, _ := p.probe("ns/job/changed", &appsv1alpha1.WaitTemplate{HTTP: &appsv1alpha1.HTTPWait{URL: "http://b"}}); ready {
+		t.Errorf("result of another url should be dropped")
+	}
+}
+
+func TestCheckWaitResource(t *testing.T) {
+	available := appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{
+		{Type: appsv1.DeploymentAvailable, Status: "True"},
+	}}
+	db := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "ns"}, Status: available}
+	shared := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "shared",
+			Namespace:   "other",
+			Annotations: map[string]string{appsv1alpha1.AllowedNamespacesAnnotation: "a, ns"},
+		},
+		Status: available,
+	}
+	private := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "private", Namespace: "other"}, Status: available}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("ClusterRole"), meta.RESTScopeRoot)
+
+	c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithRESTMapper(mapper).
+		WithObjects(db, shared, private).Build()
+	r := &JobReconciler{Client: c, APIReader: c}
+
+	job := &appsv1alpha1.Job{ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns"}}
+	item := &appsv1alpha1.Item{Name: "wait"}
+
+	tests := []struct {
+		name     string
+		resource appsv1alpha1.ResourceWait
+		ready    bool
+		err      bool
+	}{
+		{
+			name:     "object in namespace of job",
+			resource: appsv1alpha1.ResourceWait{APIVersion: "apps/v1", Kind: "Deployment", Name: "db"},
+			ready:    true,
+		},
+		{
+			name:     "object not found",
+			resource: appsv1alpha1.ResourceWait{APIVersion: "apps/v1", Kind: "Deployment", Name: "cache"},
+		},
+		{
+			name:     "object in other namespace allowing job",
+			resource: appsv1alpha1.ResourceWait{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "other", Name: "shared"},
+			ready:    true,
+		},
+		{
+			name:     "object in other namespace not allowing job",
+			resource: appsv1alpha1.ResourceWait{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "other", Name: "private"},
+		},
+		{
+			name:     "cluster scoped kind",
+			resource: appsv1alpha1.ResourceWait{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Name: "admin"},
+			err:      true,
+		},
+		{
+			name:     "kind not served",
+			resource: appsv1alpha1.ResourceWait{APIVersion: "example.com/v1", Kind: "Database", Name: "db"},
+			err:      true,
+		},
+	}
+
+	for _, test := range tests {
+		resource := test.resource
+		resource.StatusRule = appsv1alpha1.StatusRule{Condition: &appsv1alpha1.WaitCondition{Type: "Available"}}
+
+		ready, message, err := r.checkWait(context.Background(), job, item, &appsv1alpha1.WaitTemplate{Resource: &resource})
+		if (err != nil) != test.err {
+			t.Errorf("%s: expected err %t, got %v", test.name, test.err, err)
+			continue
+		}
+		if ready != test.ready {
+			t.Errorf("%s: expected ready %t, got %t %s", test.name, test.ready, ready, message)
+		}
+	}
+}
+
+func TestCheckWaitHTTPService(t *testing.T) {
+	shared := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+		Name:        "shared",
+		Namespace:   "other",
+		Annotations: map[string]string{appsv1alpha1.AllowedNamespacesAnnotation: "ns"},
+	}}
+	private := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "private", Namespace: "other"}}
+
+	c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(shared, private).Build()
+	r := &JobReconciler{Client: c, APIReader: c}
+	job := &appsv1alpha1.Job{ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns"}}
+
+	tests := []struct {
+		name      string
+		url       string
+		requested bool
+		err       bool
+	}{
+		{name: "service in namespace of job", url: "http://db.ns.svc:8080/health", requested: true},
+		{name: "service in other namespace allowing job", url: "http://shared.other.svc/health", requested: true},
+		{name: "service in other namespace not allowing job", url: "http://private.other.svc/health"},
+		{name: "service not found", url: "http://cache.other.svc/health"},
+		{name: "metadata ip", url: "http://169.254.169.254/latest/meta-data", err: true},
+		{name: "external host", url: "https://example.com", err: true},
+	}
+
+	for i, test := range tests {
+		item := &appsv1alpha1.Item{Name: fmt.Sprintf("wait-%d", i)}
+		wait := &appsv1alpha1.WaitTemplate{HTTP: &appsv1alpha1.HTTPWait{URL: test.url}}
+
+		ready, message, err := r.checkWait(context.Background(), job, item, wait)
+		if (err != nil) != test.err {
+			t.Errorf("%s: expected err %t, got %v", test.name, test.err, err)
+			continue
+		}
+		if ready {
+			t.Errorf("%s: endpoint should not be ready before requested", test.name)
+		}
+		if requested := strings.HasPrefix(message, "requesting"); !test.err && requested != test.requested {
+			t.Errorf("%s: expected requested %t, got %s", test.name, test.requested, message)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2023 firewood.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"songf.sh/songf/pkg/job_queue"
+)
+
+// QueueReconciler reconciles a Queue object
+type QueueReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=apps.songf.sh,resources=queues,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.songf.sh,resources=queues/status,verbs=get;update;patch
+
+// Reconcile summarizes the Jobs submitted to a Queue into its status.
+// Admission itself is done by the JobReconciler while reconciling queued Jobs,
+// which is triggered by the status change of Queue.
+func (r *QueueReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+
+	queue := &appsv1alpha1.Queue{}
+	if err := r.Client.Get(ctx, req.NamespacedName, queue); err != nil {
+		if errors.IsNotFound(err) {
+			klog.Infof("Queue resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+
+		klog.Errorf("reconcile get queue err: %s", err.Error())
+		return ctrl.Result{}, fmt.Errorf("reconcile get queue err: %s", err.Error())
+	}
+
+	jobs, err := listQueueJobs(ctx, r.Client, queue.Name)
+	if err != nil {
+		klog.Errorf(err.Error())
+		return ctrl.Result{}, fmt.Errorf("reconcile queue err: %s", err.Error())
+	}
+
+	var pending int32
+	for _, job := range jobs {
+		if job_queue.IsJobQueued(job) {
+			pending++
+		}
+	}
+
+	status := job_queue.CalUsage(jobs).ToStatus(pending)
+	if apiequality.Semantic.DeepEqual(status, queue.Status) {
+		return ctrl.Result{}, nil
+	}
+
+	queue.Status = status
+	if err := r.Client.Status().Update(ctx, queue); err != nil {
+		klog.Errorf("update queue %s status err: %s", queue.Name, err.Error())
+		return ctrl.Result{}, fmt.Errorf("update queue %s status err: %s", queue.Name, err.Error())
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// jobHandler enqueues the queue a Job submitted to.
+func (r *QueueReconciler) jobHandler(ctx context.Context, object client.Object) []reconcile.Request {
+	job, ok := object.(*appsv1alpha1.Job)
+	if !ok || job.Spec.Queue == "" {
+		return nil
+	}
+
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name: job.Spec.Queue,
+			},
+		},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *QueueReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1alpha1.Queue{}).
+		Watches(&appsv1alpha1.Job{}, handler.EnqueueRequestsFromMapFunc(r.jobHandler)).
+		Complete(r)
+}
@@ -0,0 +1,337 @@
+/*
+Copyright 2023 firewood.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/robfig/cron/v3"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ref "k8s.io/client-go/tools/reference"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"sort"
+	"time"
+)
+
+const (
+	defaultSuccessfulJobsHistoryLimit = 3
+	defaultFailedJobsHistoryLimit     = 1
+
+	// maxMissedSchedules stops counting missed schedules, to avoid a long loop after the controller
+	// was down for a long time or the schedule is too frequent.
+	maxMissedSchedules = 100
+)
+
+// ScheduledJobReconciler reconciles a ScheduledJob object
+type ScheduledJobReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=apps.songf.sh,resources=scheduledjobs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=apps.songf.sh,resources=scheduledjobs/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps.songf.sh,resources=scheduledjobs/finalizers,verbs=update
+
+// Reconcile creates the Job of the latest missed schedule of a ScheduledJob, tracks the Jobs created
+// into its status, cleans up finished Jobs exceeding the history limits, and requeues at the next schedule.
+func (r *ScheduledJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+
+	scheduledJob := &appsv1alpha1.ScheduledJob{}
+	if err := r.Client.Get(ctx, req.NamespacedName, scheduledJob); err != nil {
+		if errors.IsNotFound(err) {
+			klog.Infof("ScheduledJob resource not found. Ignoring since object must be deleted.")
+			return reconcile.Result{}, nil
+		}
+
+		klog.Errorf("reconcile get scheduled job err: %s", err.Error())
+		return ctrl.Result{}, fmt.Errorf("reconcile get scheduled job err: %s", err.Error())
+	}
+
+	jobList := &appsv1alpha1.JobList{}
+	if err := r.Client.List(ctx, jobList, client.InNamespace(scheduledJob.Namespace)); err != nil {
+		klog.Errorf("list jobs of scheduled job %s err: %s", scheduledJob.Name, err.Error())
+		return ctrl.Result{}, fmt.Errorf("list jobs of scheduled job %s err: %s", scheduledJob.Name, err.Error())
+	}
+
+	var activeJobs, successfulJobs, failedJobs []*appsv1alpha1.Job
+	for i := range jobList.Items {
+		job := &jobList.Items[i]
+		if !metav1.IsControlledBy(job, scheduledJob) {
+			continue
+		}
+
+		switch job.Status.State.Phase {
+		case appsv1alpha1.Completed:
+			successfulJobs = append(successfulJobs, job)
+		case appsv1alpha1.Failed:
+			failedJobs = append(failedJobs, job)
+		case appsv1alpha1.Terminating, appsv1alpha1.Terminated:
+			// being deleted
+		default:
+			activeJobs = append(activeJobs, job)
+		}
+	}
+
+	// status
+	oldStatus := scheduledJob.Status.DeepCopy()
+
+	scheduledJob.Status.Active = nil
+	for _, job := range activeJobs {
+		jobRef, err := ref.GetReference(r.Scheme, job)
+		if err != nil {
+			klog.Errorf("get reference of job %s err: %s", job.Name, err.Error())
+			continue
+		}
+		scheduledJob.Status.Active = append(scheduledJob.Status.Active, *jobRef)
+	}
+
+	for _, job := range append(append([]*appsv1alpha1.Job{}, activeJobs...), successfulJobs...) {
+		scheduledTime, ok := getJobScheduledTime(job)
+		if !ok {
+			continue
+		}
+		if scheduledJob.Status.LastScheduleTime == nil || scheduledJob.Status.LastScheduleTime.Time.Before(scheduledTime) {
+			scheduledJob.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+		}
+	}
+
+	for _, job := range successfulJobs {
+		completedTime := job.Status.State.LastTransitionTime
+		if scheduledJob.Status.LastSuccessfulTime == nil || scheduledJob.Status.LastSuccessfulTime.Before(&completedTime) {
+			scheduledJob.Status.LastSuccessfulTime = completedTime.DeepCopy()
+		}
+	}
+
+	// schedules created before validation, or time zones removed from operator, are reported by condition
+	schedule, location, scheduleErr := appsv1alpha1.ParseScheduledJobSchedule(scheduledJob)
+	setScheduleValidCondition(scheduledJob, scheduleErr)
+
+	if !apiequality.Semantic.DeepEqual(oldStatus, &scheduledJob.Status) {
+		if err := r.Client.Status().Update(ctx, scheduledJob); err != nil {
+			klog.Errorf("update scheduled job %s status err: %s", scheduledJob.Name, err.Error())
+			return ctrl.Result{}, fmt.Errorf("update scheduled job %s status err: %s", scheduledJob.Name, err.Error())
+		}
+	}
+
+	// history
+	successfulLimit := int32(defaultSuccessfulJobsHistoryLimit)
+	if scheduledJob.Spec.SuccessfulJobsHistoryLimit != nil {
+		successfulLimit = *scheduledJob.Spec.SuccessfulJobsHistoryLimit
+	}
+	r.deleteJobsExceedingLimit(ctx, successfulJobs, successfulLimit)
+
+	failedLimit := int32(defaultFailedJobsHistoryLimit)
+	if scheduledJob.Spec.FailedJobsHistoryLimit != nil {
+		failedLimit = *scheduledJob.Spec.FailedJobsHistoryLimit
+	}
+	r.deleteJobsExceedingLimit(ctx, failedJobs, failedLimit)
+
+	if scheduledJob.Spec.Suspend != nil && *scheduledJob.Spec.Suspend {
+		return ctrl.Result{}, nil
+	}
+
+	// schedule
+	if scheduleErr != nil {
+		klog.Errorf("scheduled job %s err: %s", scheduledJob.Name, scheduleErr.Error())
+		return ctrl.Result{}, nil
+	}
+
+	now := time.Now().In(location)
+	missed, next, err := getNextSchedule(scheduledJob, schedule, now)
+	if err != nil {
+		klog.Errorf("scheduled job %s err: %s", scheduledJob.Name, err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	result := ctrl.Result{RequeueAfter: next.Sub(now)}
+	if missed.IsZero() {
+		return result, nil
+	}
+
+	if scheduledJob.Spec.StartingDeadlineSeconds != nil &&
+		missed.Add(time.Duration(*scheduledJob.Spec.StartingDeadlineSeconds)*time.Second).Before(now) {
+		klog.Infof("scheduled job %s missed starting deadline of schedule %s", scheduledJob.Name, missed.String())
+		return result, nil
+	}
+
+	switch scheduledJob.Spec.ConcurrencyPolicy {
+	case appsv1alpha1.ForbidConcurrent:
+		if len(activeJobs) > 0 {
+			klog.Infof("scheduled job %s skip schedule %s: previous job not finished", scheduledJob.Name, missed.String())
+			return result, nil
+		}
+	case appsv1alpha1.ReplaceConcurrent:
+		for _, job := range activeJobs {
+			if err := r.Client.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+				klog.Errorf("delete active job %s err: %s", job.Name, err.Error())
+				return ctrl.Result{}, fmt.Errorf("delete active job %s err: %s", job.Name, err.Error())
+			}
+		}
+	}
+
+	job, err := r.constructJob(scheduledJob, missed)
+	if err != nil {
+		klog.Errorf(err.Error())
+		return ctrl.Result{}, err
+	}
+
+	if err := r.Client.Create(ctx, job); err != nil && !errors.IsAlreadyExists(err) {
+		klog.Errorf("create job %s of scheduled job %s err: %s", job.Name, scheduledJob.Name, err.Error())
+		return ctrl.Result{}, fmt.Errorf("create job %s of scheduled job %s err: %s", job.Name, scheduledJob.Name, err.Error())
+	}
+
+	klog.Infof("scheduled job %s created job %s for schedule %s", scheduledJob.Name, job.Name, missed.String())
+
+	scheduledJob.Status.LastScheduleTime = &metav1.Time{Time: missed}
+	if err := r.Client.Status().Update(ctx, scheduledJob); err != nil {
+		klog.Errorf("update scheduled job %s status err: %s", scheduledJob.Name, err.Error())
+		return ctrl.Result{}, fmt.Errorf("update scheduled job %s status err: %s", scheduledJob.Name, err.Error())
+	}
+
+	return result, nil
+}
+
+// setScheduleValidCondition sets the ScheduleValid condition of scheduled job by the err of parsing its schedule.
+func setScheduleValidCondition(scheduledJob *appsv1alpha1.ScheduledJob, err error) {
+	condition := metav1.Condition{
+		Type:               appsv1alpha1.ScheduleValidCondition,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: scheduledJob.Generation,
+		Reason:             "Valid",
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidSchedule"
+		condition.Message = err.Error()
+	}
+
+	meta.SetStatusCondition(&scheduledJob.Status.Conditions, condition)
+}
+
+func getJobScheduledTime(job *appsv1alpha1.Job) (time.Time, bool) {
+	value, ok := job.Annotations[appsv1alpha1.ScheduledTimeAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	scheduledTime, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return scheduledTime, true
+}
+
+// getNextSchedule returns the latest schedule missed since the last one, zero if no schedule missed,
+// and the next schedule after now.
+func getNextSchedule(scheduledJob *appsv1alpha1.ScheduledJob, schedule cron.Schedule, now time.Time) (time.Time, time.Time, error) {
+
+	earliest := scheduledJob.CreationTimestamp.Time
+	if scheduledJob.Status.LastScheduleTime != nil {
+		earliest = scheduledJob.Status.LastScheduleTime.Time
+	}
+
+	// schedules earlier than the starting deadline can not be started anyway
+	if scheduledJob.Spec.StartingDeadlineSeconds != nil {
+		deadline := now.Add(-time.Duration(*scheduledJob.Spec.StartingDeadlineSeconds) * time.Second)
+		if deadline.After(earliest) {
+			earliest = deadline
+		}
+	}
+
+	earliest = earliest.In(now.Location())
+	if earliest.After(now) {
+		return time.Time{}, schedule.Next(now), nil
+	}
+
+	var missed time.Time
+	missedNum := 0
+	for t := schedule.Next(earliest); !t.After(now); t = schedule.Next(t) {
+		missed = t
+
+		missedNum++
+		if missedNum > maxMissedSchedules {
+			return time.Time{}, time.Time{}, fmt.Errorf("too many missed schedules (> %d), set or decrease startingDeadlineSeconds",
+				maxMissedSchedules)
+		}
+	}
+
+	return missed, schedule.Next(now), nil
+}
+
+func (r *ScheduledJobReconciler) constructJob(scheduledJob *appsv1alpha1.ScheduledJob, scheduledTime time.Time) (*appsv1alpha1.Job, error) {
+	job := &appsv1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-%d", scheduledJob.Name, scheduledTime.Unix()/60),
+			Namespace:   scheduledJob.Namespace,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Spec: *scheduledJob.Spec.JobTemplate.Spec.DeepCopy(),
+	}
+
+	for key, value := range scheduledJob.Spec.JobTemplate.Labels {
+		job.Labels[key] = value
+	}
+	for key, value := range scheduledJob.Spec.JobTemplate.Annotations {
+		job.Annotations[key] = value
+	}
+	job.Annotations[appsv1alpha1.ScheduledTimeAnnotation] = scheduledTime.Format(time.RFC3339)
+
+	if err := controllerutil.SetControllerReference(scheduledJob, job, r.Scheme); err != nil {
+		return nil, fmt.Errorf("set controller reference of job %s err: %s", job.Name, err.Error())
+	}
+
+	return job, nil
+}
+
+// deleteJobsExceedingLimit deletes the oldest finished jobs, keeping limit of them.
+func (r *ScheduledJobReconciler) deleteJobsExceedingLimit(ctx context.Context, jobs []*appsv1alpha1.Job, limit int32) {
+	if int32(len(jobs)) <= limit {
+		return
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreationTimestamp.Before(&jobs[j].CreationTimestamp)
+	})
+
+	for _, job := range jobs[:int32(len(jobs))-limit] {
+		if err := r.Client.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			klog.Errorf("delete history job %s err: %s", job.Name, err.Error())
+			continue
+		}
+
+		klog.Infof("history job %s deleted", job.Name)
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ScheduledJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&appsv1alpha1.ScheduledJob{}).
+		Owns(&appsv1alpha1.Job{}).
+		Complete(r)
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+	"time"
+)
+
+func TestSetScheduleValidCondition(t *testing.T) {
+	scheduledJob := &appsv1alpha1.ScheduledJob{ObjectMeta: metav1.ObjectMeta{Generation: 2}}
+
+	setScheduleValidCondition(scheduledJob, errors.New("bad schedule"))
+	condition := meta.FindStatusCondition(scheduledJob.Status.Conditions, appsv1alpha1.ScheduleValidCondition)
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "InvalidSchedule" ||
+		condition.Message != "bad schedule" || condition.ObservedGeneration != 2 {
+		t.Fatalf("unexpected condition %v", condition)
+	}
+
+	setScheduleValidCondition(scheduledJob, nil)
+	condition = meta.FindStatusCondition(scheduledJob.Status.Conditions, appsv1alpha1.ScheduleValidCondition)
+	if len(scheduledJob.Status.Conditions) != 1 || condition.Status != metav1.ConditionTrue || condition.Message != "" {
+		t.Errorf("condition should be set valid in place, got %v", scheduledJob.Status.Conditions)
+	}
+}
+
+func TestGetNextSchedule(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	deadline := int64(90)
+
+	tests := []struct {
+		name         string
+		lastSchedule *time.Time
+		deadline     *int64
+		now          time.Time
+		missed       time.Time
+		next         time.Time
+		err          bool
+	}{
+		{
+			name: "no schedule missed",
+			now:  created.Add(30 * time.Second),
+			next: created.Add(time.Minute),
+		},
+		{
+			name:   "latest missed schedule returned",
+			now:    created.Add(3*time.Minute + 30*time.Second),
+			missed: created.Add(3 * time.Minute),
+			next:   created.Add(4 * time.Minute),
+		},
+		{
+			name:         "counted from last schedule",
+			lastSchedule: func() *time.Time { last := created.Add(3 * time.Minute); return &last }(),
+			now:          created.Add(3*time.Minute + 30*time.Second),
+			next:         created.Add(4 * time.Minute),
+		},
+		{
+			name: "too many missed schedules",
+			now:  created.Add(200 * time.Minute),
+			err:  true,
+		},
+		{
+			name:     "schedules before starting deadline skipped",
+			deadline: &deadline,
+			now:      created.Add(200 * time.Minute),
+			missed:   created.Add(200 * time.Minute),
+			next:     created.Add(201 * time.Minute),
+		},
+	}
+
+	for _, test := range tests {
+		scheduledJob := &appsv1alpha1.ScheduledJob{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)},
+			Spec:       appsv1alpha1.ScheduledJobSpec{Schedule: "* * * * *", StartingDeadlineSeconds: test.deadline},
+		}
+		if test.lastSchedule != nil {
+			lastSchedule := metav1.NewTime(*test.lastSchedule)
+			scheduledJob.Status.LastScheduleTime = &lastSchedule
+		}
+
+		schedule, _, err := appsv1alpha1.ParseScheduledJobSchedule(scheduledJob)
+		if err != nil {
+			t.Fatalf("%s: parse schedule err: %s", test.name, err.Error())
+		}
+
+		missed, next, err := getNextSchedule(scheduledJob, schedule, test.now)
+		if (err != nil) != test.err {
+			t.Errorf("%s: expected err %t, got %v", test.name, test.err, err)
+			continue
+		}
+		if !missed.Equal(test.missed) || !next.Equal(test.next) {
+			t.Errorf("%s: expected missed %s next %s, got %s %s", test.name, test.missed, test.next, missed, next)
+		}
+	}
+}
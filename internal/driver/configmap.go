@@ -0,0 +1,137 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;delete
+
+// configMapDriver creates the configmap modules of item, which are ready once created.
+type configMapDriver struct{}
+
+func (d *configMapDriver) Name() string {
+	return "configmap"
+}
+
+func (d *configMapDriver) Object() client.Object {
+	return &corev1.ConfigMap{}
+}
+
+func (d *configMapDriver) IsModule() bool {
+	return true
+}
+
+func (d *configMapDriver) Render(rc *RenderContext) ([]client.Object, error) {
+	var res []client.Object
+
+	for _, cm := range rc.Item.ItemModules.ConfigMaps {
+		cmImpl := cm.ConfigMap.DeepCopy()
+
+		objectMeta := rc.ObjectMeta(rc.ModuleName(cm.Name), cm.TemplateBaseInfo)
+		cmImpl.Name = objectMeta.Name
+		cmImpl.Namespace = objectMeta.Namespace
+		cmImpl.Annotations = objectMeta.Annotations
+		cmImpl.Labels = objectMeta.Labels
+
+		if err := mergeConfigMapSources(rc, cm, cmImpl); err != nil {
+			return nil, fmt.Errorf("configmap %s err: %s", cm.Name, err.Error())
+		}
+
+		res = append(res, cmImpl)
+	}
+
+	return res, nil
+}
+
+// mergeConfigMapSources copies the data of the value sources of template into cm, the data of cm wins.
+func mergeConfigMapSources(rc *RenderContext, template appsv1alpha1.ConfigMapTemplate, cm *corev1.ConfigMap) error {
+	if rc.Reader == nil || len(template.ValueFrom) == 0 {
+		return nil
+	}
+
+	data, binaryData := map[string]string{}, map[string][]byte{}
+	for _, source := range template.ValueFrom {
+		sourceCM := &corev1.ConfigMap{}
+		found, err := readValueSource(rc, source, sourceCM)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+
+		keys := map[string]bool{}
+		for key := range sourceCM.Data {
+			keys[key] = true
+		}
+		for key := range sourceCM.BinaryData {
+			keys[key] = true
+		}
+
+		selected, err := sourceKeys(source, keys)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range selected {
+			if value, ok := sourceCM.Data[key]; ok {
+				data[key] = value
+				delete(binaryData, key)
+			} else {
+				binaryData[key] = sourceCM.BinaryData[key]
+				delete(data, key)
+			}
+		}
+	}
+
+	for key, value := range cm.Data {
+		data[key] = value
+		delete(binaryData, key)
+	}
+	for key, value := range cm.BinaryData {
+		binaryData[key] = value
+		delete(data, key)
+	}
+
+	cm.Data, cm.BinaryData = nil, nil
+	if len(data) > 0 {
+		cm.Data = data
+	}
+	if len(binaryData) > 0 {
+		cm.BinaryData = binaryData
+	}
+
+	return nil
+}
+
+func (d *configMapDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return createOwned(ctx, c, job, obj)
+}
+
+func (d *configMapDriver) Delete(ctx context.Context, c client.Client, obj client.Object) error {
+	return deleteObject(ctx, c, obj)
+}
+
+func (d *configMapDriver) SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus) {
+	if _, ok := obj.(*corev1.ConfigMap); !ok {
+		return
+	}
+
+	if status.ConfigMapStatus == nil {
+		status.ConfigMapStatus = map[string]appsv1alpha1.RegularModuleStatus{}
+	}
+
+	syncModuleStatus(status.ConfigMapStatus, obj, d.Readiness(item, obj))
+}
+
+func (d *configMapDriver) Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness {
+	if _, ok := obj.(*corev1.ConfigMap); !ok {
+		return ReadinessPending
+	}
+
+	return ReadinessReady
+}
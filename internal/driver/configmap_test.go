@@ -0,0 +1,53 @@
+package driver
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+)
+
+func TestConfigMapDriver(t *testing.T) {
+	d := &configMapDriver{}
+
+	item := &appsv1alpha1.Item{
+		Name: "item",
+		ItemModules: appsv1alpha1.ItemModuleResource{
+			ConfigMaps: []appsv1alpha1.ConfigMapTemplate{
+				{
+					TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "conf"},
+					ConfigMap: corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{Name: "ignored", Namespace: "ignored"},
+						Data:       map[string]string{"a": "b"},
+					},
+				},
+			},
+		},
+	}
+
+	objs, err := d.Render(newTestRenderContext(item))
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 configmap, got %d", len(objs))
+	}
+
+	cm := objs[0].(*corev1.ConfigMap)
+	if cm.Name != "job-item-conf" || cm.Namespace != "ns" || cm.Data["a"] != "b" {
+		t.Errorf("unexpected configmap %s/%s", cm.Namespace, cm.Name)
+	}
+	if cm.Annotations[appsv1alpha1.CreateByJobItem] != "item" {
+		t.Errorf("configmap should be annotated with its item, got %v", cm.Annotations)
+	}
+
+	status := &appsv1alpha1.ItemStatus{}
+	d.SyncStatus(nil, cm, status)
+
+	if status.ConfigMapStatus[cm.Name].Phase != appsv1alpha1.RegularModuleCreated {
+		t.Errorf("configmap should be created once exists, got %v", status.ConfigMapStatus)
+	}
+	if len(status.ServiceStatus) != 0 {
+		t.Errorf("configmap should not be recorded as service, got %v", status.ServiceStatus)
+	}
+}
@@ -0,0 +1,94 @@
+package driver
+
+import (
+	"context"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;delete
+
+// deploymentDriver runs the Deployment of long-running item, which is completed once all replicas are ready.
+type deploymentDriver struct{}
+
+func (d *deploymentDriver) Name() string {
+	return "deployment"
+}
+
+func (d *deploymentDriver) Object() client.Object {
+	return &appsv1.Deployment{}
+}
+
+func (d *deploymentDriver) IsModule() bool {
+	return false
+}
+
+func (d *deploymentDriver) Render(rc *RenderContext) ([]client.Object, error) {
+	longRunning := rc.Item.LongRunning
+	if longRunning == nil || longRunning.Deployment == nil {
+		return nil, nil
+	}
+
+	spec := longRunning.Deployment.DeepCopy()
+	if err := rc.DecoratePodTemplate(&spec.Template); err != nil {
+		return nil, err
+	}
+
+	return []client.Object{&appsv1.Deployment{
+		ObjectMeta: rc.ObjectMeta(rc.JobName(longRunning.Name), longRunning.TemplateBaseInfo),
+		Spec:       *spec,
+	}}, nil
+}
+
+func (d *deploymentDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return createOwned(ctx, c, job, obj)
+}
+
+// Delete deletes Deployment in background, so that its pods are deleted too.
+func (d *deploymentDriver) Delete(ctx context.Context, c client.Client, obj client.Object) error {
+	return deleteObject(ctx, c, obj, client.PropagationPolicy(metav1.DeletePropagationBackground))
+}
+
+func (d *deploymentDriver) SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus) {
+	deployment, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return
+	}
+
+	syncWorkloadStatus(obj, status, d.Readiness(item, obj), deployment.Status.ReadyReplicas, deploymentReplicas(deployment))
+}
+
+// Readiness returns ready once the latest spec is observed and all replicas are updated and ready,
+// failed once the rollout exceeds its progress deadline.
+func (d *deploymentDriver) Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness {
+	deployment, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return ReadinessPending
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse &&
+			condition.Reason == "ProgressDeadlineExceeded" {
+			return ReadinessFailed
+		}
+	}
+
+	replicas := deploymentReplicas(deployment)
+	if deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas >= replicas && deployment.Status.ReadyReplicas >= replicas {
+		return ReadinessReady
+	}
+
+	return ReadinessPending
+}
+
+func deploymentReplicas(deployment *appsv1.Deployment) int32 {
+	if deployment.Spec.Replicas == nil {
+		return 1
+	}
+
+	return *deployment.Spec.Replicas
+}
@@ -0,0 +1,105 @@
+package driver
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func TestDeploymentDriverRender(t *testing.T) {
+	d := &deploymentDriver{}
+
+	item := &appsv1alpha1.Item{Name: "item"}
+	if objs, err := d.Render(newTestRenderContext(item)); err != nil || len(objs) != 0 {
+		t.Fatalf("item not long-running should render nothing, got %d err %v", len(objs), err)
+	}
+
+	item.LongRunning = &appsv1alpha1.LongRunningTemplate{
+		TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "db"},
+		StatefulSet:      &appsv1.StatefulSetSpec{},
+	}
+	if objs, err := d.Render(newTestRenderContext(item)); err != nil || len(objs) != 0 {
+		t.Fatalf("item running statefulset should render no deployment, got %d err %v", len(objs), err)
+	}
+
+	replicas := int32(2)
+	item.LongRunning.StatefulSet = nil
+	item.LongRunning.Deployment = &appsv1.DeploymentSpec{Replicas: &replicas}
+
+	objs, err := d.Render(newTestRenderContext(item))
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 deployment, got %d", len(objs))
+	}
+
+	deployment := objs[0].(*appsv1.Deployment)
+	if deployment.Name != "job-item-db" || deployment.Namespace != "ns" {
+		t.Errorf("unexpected name %s/%s", deployment.Namespace, deployment.Name)
+	}
+	if deployment.Labels[appsv1alpha1.CreateByJobItem] != "item" {
+		t.Errorf("deployment should be labeled with its item")
+	}
+	if deployment.Spec.Replicas == item.LongRunning.Deployment.Replicas {
+		t.Errorf("spec of item should be copied")
+	}
+}
+
+func TestDeploymentDriverSyncStatus(t *testing.T) {
+	d := &deploymentDriver{}
+
+	replicas := int32(2)
+	tests := []struct {
+		name      string
+		status    appsv1.DeploymentStatus
+		deleting  bool
+		old       v1alpha1.JobPhase
+		phase     v1alpha1.JobPhase
+		readiness Readiness
+	}{
+		{name: "created", phase: v1alpha1.Pending, readiness: ReadinessPending},
+		{name: "partly ready", status: appsv1.DeploymentStatus{ObservedGeneration: 1, UpdatedReplicas: 2, ReadyReplicas: 1},
+			phase: v1alpha1.Running, readiness: ReadinessPending},
+		{name: "ready", status: appsv1.DeploymentStatus{ObservedGeneration: 1, UpdatedReplicas: 2, ReadyReplicas: 2},
+			phase: v1alpha1.Completed, readiness: ReadinessReady},
+		{name: "generation not observed", status: appsv1.DeploymentStatus{UpdatedReplicas: 2, ReadyReplicas: 2},
+			phase: v1alpha1.Running, readiness: ReadinessPending},
+		{name: "progress deadline exceeded", status: appsv1.DeploymentStatus{
+			Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentProgressing,
+				Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"}},
+		}, phase: v1alpha1.Failed, readiness: ReadinessFailed},
+		{name: "torn down after ready", deleting: true, old: v1alpha1.Completed,
+			phase: v1alpha1.Completed, readiness: ReadinessPending},
+		{name: "deleted before ready", deleting: true, old: v1alpha1.Running,
+			phase: v1alpha1.Terminated, readiness: ReadinessPending},
+	}
+
+	for _, test := range tests {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "job-item-db", Generation: 1},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     test.status,
+		}
+		if test.deleting {
+			now := metav1.Now()
+			deployment.DeletionTimestamp = &now
+		}
+
+		status := &appsv1alpha1.ItemStatus{}
+		if test.old != "" {
+			status.JobStatus = map[string]v1alpha1.JobState{deployment.Name: {Phase: test.old}}
+		}
+		d.SyncStatus(nil, deployment, status)
+
+		if phase := status.JobStatus[deployment.Name].Phase; phase != test.phase {
+			t.Errorf("%s: expected phase %s, got %s", test.name, test.phase, phase)
+		}
+		if readiness := d.Readiness(nil, deployment); readiness != test.readiness {
+			t.Errorf("%s: expected readiness %s, got %s", test.name, test.readiness, readiness)
+		}
+	}
+}
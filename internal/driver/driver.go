@@ -0,0 +1,231 @@
+package driver
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"sync"
+)
+
+// Readiness is the readiness of an object created for item.
+type Readiness string
+
+const (
+	// ReadinessPending means the object is not ready yet, like a pvc not bound or a running job.
+	ReadinessPending Readiness = "Pending"
+	// ReadinessReady means the object can be used, like a bound pvc or a completed job.
+	ReadinessReady Readiness = "Ready"
+	// ReadinessFailed means the object will never be ready.
+	ReadinessFailed Readiness = "Failed"
+)
+
+// ItemResourceDriver creates the objects of one kind for items, and records their status into the status of items.
+type ItemResourceDriver interface {
+	// Name is the name of driver in registry.
+	Name() string
+
+	// Object returns an empty object of the kind, which is watched by the job controller. Nil if the kinds of
+	// objects are set by items, which are watched once the Jobs using them are seen.
+	Object() client.Object
+
+	// IsModule returns true if the objects are modules of item, which are kept while item is retried or
+	// iterated, otherwise the objects are jobs of item, which are recreated for every attempt.
+	IsModule() bool
+
+	// Render returns the objects of item to create, nil if item has none of the kind.
+	Render(rc *RenderContext) ([]client.Object, error)
+
+	// Create creates a rendered object owned by job.
+	Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error
+
+	// Delete deletes an object, not found is not an error.
+	Delete(ctx context.Context, c client.Client, obj client.Object) error
+
+	// SyncStatus records the status of object into the status of item it belongs to. Item is nil if the
+	// graph of its Job is not synced yet, drivers needing the templates of item skip the object then.
+	SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus)
+
+	// Readiness returns the readiness of object of item.
+	Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness
+}
+
+// RenderContext carries what drivers need to render the objects of item.
+type RenderContext struct {
+	Job       *appsv1alpha1.Job
+	Item      *appsv1alpha1.Item
+	Iteration int32
+
+	// Attempt is the num of retries of item, injected into the pods of item.
+	Attempt int32
+
+	// Reader reads the objects templates refer to, like the value sources of configmaps and secrets.
+	// They are not read if nil, when only the names of objects are needed.
+	Reader client.Reader
+
+	// SealKey decrypts the encrypted data of secrets, which are not decrypted if nil.
+	SealKey *rsa.PrivateKey
+}
+
+// JobName returns the name of a job of item in the iteration.
+func (rc *RenderContext) JobName(name string) string {
+	return appsv1alpha1.CalItemJobName(rc.Job.Name, rc.Item, name, rc.Iteration)
+}
+
+// ModuleName returns the name of a module of item.
+func (rc *RenderContext) ModuleName(name string) string {
+	return appsv1alpha1.CalJobItemSubName(rc.Job.Name, rc.Item.Name, name)
+}
+
+// Annotations returns the annotations of job, extended by the ones recording the creator and then by extend.
+func (rc *RenderContext) Annotations(extend map[string]string) map[string]string {
+	res := map[string]string{}
+
+	for k, v := range rc.Job.Annotations {
+		res[k] = v
+	}
+	res[appsv1alpha1.CreateByJob] = rc.Job.Name
+	res[appsv1alpha1.CreateByJobItem] = rc.Item.Name
+
+	for k, v := range extend {
+		res[k] = v
+	}
+
+	return res
+}
+
+// Labels returns the labels of job, extended by the ones recording the creator and then by extend.
+func (rc *RenderContext) Labels(extend map[string]string) map[string]string {
+	res := map[string]string{}
+
+	for k, v := range rc.Job.Labels {
+		res[k] = v
+	}
+	res[appsv1alpha1.CreateByJob] = rc.Job.Name
+	res[appsv1alpha1.CreateByJobItem] = rc.Item.Name
+
+	for k, v := range extend {
+		res[k] = v
+	}
+
+	return res
+}
+
+// ObjectMeta returns the meta of an object named name in the namespace of job.
+func (rc *RenderContext) ObjectMeta(name string, base appsv1alpha1.TemplateBaseInfo) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        name,
+		Namespace:   rc.Job.Namespace,
+		Annotations: rc.Annotations(base.Annotations),
+		Labels:      rc.Labels(base.Labels),
+	}
+}
+
+// DecoratePodTemplate applies what job sets for all pods of item to the pod template of an object of item.
+// Pod defaults of job are merged first, and containers get the env of songf context, unless they set env
+// with the same names.
+// Pods of isolated job are labeled with job, so that they are selected by the NetworkPolicy of job.
+func (rc *RenderContext) DecoratePodTemplate(template *corev1.PodTemplateSpec) error {
+	if err := applyPodDefaults(rc.Job.Spec.PodDefaults, template); err != nil {
+		return err
+	}
+
+	env := appsv1alpha1.CalItemEnv(rc.Job, rc.Item.Name, rc.Attempt)
+	for i := range template.Spec.InitContainers {
+		injectEnv(&template.Spec.InitContainers[i], env)
+	}
+	for i := range template.Spec.Containers {
+		injectEnv(&template.Spec.Containers[i], env)
+	}
+
+	if rc.Job.Spec.NetworkIsolation == nil {
+		return nil
+	}
+
+	if template.Labels == nil {
+		template.Labels = map[string]string{}
+	}
+	template.Labels[appsv1alpha1.CreateByJob] = rc.Job.Name
+
+	return nil
+}
+
+// injectEnv appends env to container, skipping the ones container already sets.
+func injectEnv(container *corev1.Container, env []corev1.EnvVar) {
+	set := map[string]bool{}
+	for _, e := range container.Env {
+		set[e.Name] = true
+	}
+
+	for _, e := range env {
+		if !set[e.Name] {
+			container.Env = append(container.Env, e)
+		}
+	}
+}
+
+var (
+	driverLock sync.RWMutex
+	drivers    []ItemResourceDriver
+)
+
+func init() {
+	RegisterItemResourceDriver(&kubeJobDriver{})
+	RegisterItemResourceDriver(&volcanoJobDriver{})
+	RegisterItemResourceDriver(&jobSetDriver{})
+	RegisterItemResourceDriver(&deploymentDriver{})
+	RegisterItemResourceDriver(&statefulSetDriver{})
+	RegisterItemResourceDriver(&serviceDriver{})
+	RegisterItemResourceDriver(&configMapDriver{})
+	RegisterItemResourceDriver(&secretDriver{})
+	RegisterItemResourceDriver(&pvDriver{})
+	RegisterItemResourceDriver(&pvcDriver{})
+	RegisterItemResourceDriver(&serviceAccountDriver{})
+	RegisterItemResourceDriver(&roleDriver{})
+	RegisterItemResourceDriver(&roleBindingDriver{})
+	RegisterItemResourceDriver(&networkPolicyDriver{})
+	RegisterItemResourceDriver(&ingressDriver{})
+	RegisterItemResourceDriver(&podDisruptionBudgetDriver{})
+	RegisterItemResourceDriver(&unstructuredJobDriver{})
+	RegisterItemResourceDriver(&subJobDriver{})
+}
+
+// RegisterItemResourceDriver registers a driver, driver with the same name will be replaced.
+// Objects of item are created in the order drivers registered.
+func RegisterItemResourceDriver(driver ItemResourceDriver) {
+	driverLock.Lock()
+	defer driverLock.Unlock()
+
+	for i := range drivers {
+		if drivers[i].Name() == driver.Name() {
+			drivers[i] = driver
+			return
+		}
+	}
+
+	drivers = append(drivers, driver)
+}
+
+// GetItemResourceDrivers returns all registered drivers in the order registered.
+func GetItemResourceDrivers() []ItemResourceDriver {
+	driverLock.RLock()
+	defer driverLock.RUnlock()
+
+	return append([]ItemResourceDriver{}, drivers...)
+}
+
+func GetItemResourceDriver(name string) (ItemResourceDriver, error) {
+	driverLock.RLock()
+	defer driverLock.RUnlock()
+
+	for _, driver := range drivers {
+		if driver.Name() == name {
+			return driver, nil
+		}
+	}
+
+	return nil, fmt.Errorf("item resource driver %s not registered", name)
+}
@@ -0,0 +1,183 @@
+package driver
+
+import (
+	"context"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+)
+
+func newTestRenderContext(item *appsv1alpha1.Item) *RenderContext {
+	return &RenderContext{
+		Job: &appsv1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "job",
+				Namespace:   "ns",
+				Annotations: map[string]string{"from": "job", "job": "a"},
+				Labels:      map[string]string{"from": "job"},
+			},
+		},
+		Item: item,
+	}
+}
+
+func TestRenderContext(t *testing.T) {
+	rc := newTestRenderContext(&appsv1alpha1.Item{Name: "item"})
+
+	meta := rc.ObjectMeta(rc.ModuleName("svc"), appsv1alpha1.TemplateBaseInfo{
+		Annotations: map[string]string{"from": "template"},
+	})
+
+	if meta.Name != "job-item-svc" || meta.Namespace != "ns" {
+		t.Errorf("unexpected name %s/%s", meta.Namespace, meta.Name)
+	}
+	if meta.Annotations["from"] != "template" || meta.Annotations["job"] != "a" {
+		t.Errorf("template annotations should extend job annotations, got %v", meta.Annotations)
+	}
+	if meta.Annotations[appsv1alpha1.CreateByJob] != "job" || meta.Annotations[appsv1alpha1.CreateByJobItem] != "item" {
+		t.Errorf("creator annotations not set, got %v", meta.Annotations)
+	}
+	if meta.Labels[appsv1alpha1.CreateByJob] != "job" || meta.Labels["from"] != "job" {
+		t.Errorf("unexpected labels %v", meta.Labels)
+	}
+
+	// job annotations are not changed by rendering
+	if _, ok := rc.Job.Annotations[appsv1alpha1.CreateByJob]; ok {
+		t.Errorf("job annotations changed by rendering")
+	}
+}
+
+func TestDecoratePodTemplate(t *testing.T) {
+	rc := newTestRenderContext(&appsv1alpha1.Item{Name: "item"})
+
+	template := &corev1.PodTemplateSpec{}
+	if err := rc.DecoratePodTemplate(template); err != nil {
+		t.Fatalf("decorate pod template err: %s", err.Error())
+	}
+	if len(template.Labels) != 0 {
+		t.Errorf("pods of job not isolated should not be labeled, got %v", template.Labels)
+	}
+
+	rc.Job.Spec.NetworkIsolation = &appsv1alpha1.NetworkIsolation{}
+	template.Labels = map[string]string{"app": "x"}
+	if err := rc.DecoratePodTemplate(template); err != nil {
+		t.Fatalf("decorate pod template err: %s", err.Error())
+	}
+	if template.Labels[appsv1alpha1.CreateByJob] != "job" || template.Labels["app"] != "x" {
+		t.Errorf("pods of isolated job should be labeled with job, got %v", template.Labels)
+	}
+}
+
+func TestDecoratePodTemplateEnv(t *testing.T) {
+	rc := newTestRenderContext(&appsv1alpha1.Item{Name: "b"})
+	rc.Job.UID = "uid"
+	rc.Job.Spec.Items = []appsv1alpha1.Item{
+		{
+			Name: "a",
+			ItemModules: appsv1alpha1.ItemModuleResource{
+				Services: []appsv1alpha1.ServiceTemplate{{TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "db"}}},
+			},
+		},
+		{
+			Name:     "b",
+			RunAfter: []string{"a"},
+			ItemModules: appsv1alpha1.ItemModuleResource{
+				ConfigMaps: []appsv1alpha1.ConfigMapTemplate{{TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "conf-1"}}},
+			},
+		},
+	}
+	rc.Attempt = 2
+
+	template := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init"}},
+			Containers: []corev1.Container{{
+				Name: "main",
+				Env:  []corev1.EnvVar{{Name: appsv1alpha1.EnvItemName, Value: "mine"}},
+			}},
+		},
+	}
+	if err := rc.DecoratePodTemplate(template); err != nil {
+		t.Fatalf("decorate pod template err: %s", err.Error())
+	}
+
+	env := map[string]string{}
+	for _, e := range template.Spec.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+
+	expected := map[string]string{
+		appsv1alpha1.EnvJobName:        "job",
+		appsv1alpha1.EnvItemName:       "mine",
+		appsv1alpha1.EnvRunID:          "uid",
+		appsv1alpha1.EnvAttempt:        "2",
+		"SONGF_CONFIGMAP_CONF_1_NAME":  "job-b-conf-1",
+		"SONGF_ITEM_A_SERVICE_DB_HOST": "job-a-db.ns.svc",
+	}
+	for k, v := range expected {
+		if env[k] != v {
+			t.Errorf("env %s should be %q, got %q", k, v, env[k])
+		}
+	}
+	if len(env) != len(expected) || len(template.Spec.Containers[0].Env) != len(expected) {
+		t.Errorf("unexpected env %v", template.Spec.Containers[0].Env)
+	}
+
+	if len(template.Spec.InitContainers[0].Env) != len(expected) {
+		t.Errorf("env not injected into init containers, got %v", template.Spec.InitContainers[0].Env)
+	}
+}
+
+type testDriver struct {
+	serviceDriver
+	name string
+}
+
+func (d *testDriver) Name() string {
+	return d.name
+}
+
+func (d *testDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return nil
+}
+
+func TestRegisterItemResourceDriver(t *testing.T) {
+	builtin := GetItemResourceDrivers()
+	defer func() {
+		driverLock.Lock()
+		drivers = builtin
+		driverLock.Unlock()
+	}()
+
+	names := []string{"kube-job", "volcano-job", "jobset", "deployment", "statefulset", "service", "configmap", "secret", "pv", "pvc",
+		"service-account", "role", "role-binding", "network-policy", "ingress", "pod-disruption-budget", "unstructured-job", "sub-job"}
+	if len(builtin) != len(names) {
+		t.Fatalf("expected %d builtin drivers, got %d", len(names), len(builtin))
+	}
+	for i, name := range names {
+		if builtin[i].Name() != name {
+			t.Errorf("expected driver %d to be %s, got %s", i, name, builtin[i].Name())
+		}
+	}
+
+	RegisterItemResourceDriver(&testDriver{name: "test"})
+	if all := GetItemResourceDrivers(); all[len(all)-1].Name() != "test" {
+		t.Errorf("new driver should be registered at last")
+	}
+
+	replace := &testDriver{name: "service"}
+	RegisterItemResourceDriver(replace)
+	all := GetItemResourceDrivers()
+	if len(all) != len(names)+1 || all[5] != ItemResourceDriver(replace) {
+		t.Errorf("driver with the same name should be replaced in place")
+	}
+
+	if d, err := GetItemResourceDriver("service"); err != nil || d != ItemResourceDriver(replace) {
+		t.Errorf("get replaced driver err: %v", err)
+	}
+	if _, err := GetItemResourceDriver("not-exist"); err == nil {
+		t.Errorf("get not registered driver should fail")
+	}
+}
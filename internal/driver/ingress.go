@@ -0,0 +1,93 @@
+package driver
+
+import (
+	"context"
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;delete
+
+// ingressDriver creates the ingress modules of item, which are ready once created.
+// Services of item referred by their template names in backends are replaced by the created ones.
+type ingressDriver struct{}
+
+func (d *ingressDriver) Name() string {
+	return "ingress"
+}
+
+func (d *ingressDriver) Object() client.Object {
+	return &networkingv1.Ingress{}
+}
+
+func (d *ingressDriver) IsModule() bool {
+	return true
+}
+
+func (d *ingressDriver) Render(rc *RenderContext) ([]client.Object, error) {
+	var res []client.Object
+
+	services := map[string]bool{}
+	for _, service := range rc.Item.ItemModules.Services {
+		services[service.Name] = true
+	}
+
+	for _, ing := range rc.Item.ItemModules.Ingresses {
+		spec := ing.Spec.DeepCopy()
+
+		renameIngressBackend(rc, services, spec.DefaultBackend)
+		for i := range spec.Rules {
+			if spec.Rules[i].HTTP == nil {
+				continue
+			}
+			for j := range spec.Rules[i].HTTP.Paths {
+				renameIngressBackend(rc, services, &spec.Rules[i].HTTP.Paths[j].Backend)
+			}
+		}
+
+		res = append(res, &networkingv1.Ingress{
+			ObjectMeta: rc.ObjectMeta(rc.ModuleName(ing.Name), ing.TemplateBaseInfo),
+			Spec:       *spec,
+		})
+	}
+
+	return res, nil
+}
+
+func (d *ingressDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return createOwned(ctx, c, job, obj)
+}
+
+func (d *ingressDriver) Delete(ctx context.Context, c client.Client, obj client.Object) error {
+	return deleteObject(ctx, c, obj)
+}
+
+func (d *ingressDriver) SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus) {
+	if _, ok := obj.(*networkingv1.Ingress); !ok {
+		return
+	}
+
+	if status.IngressStatus == nil {
+		status.IngressStatus = map[string]appsv1alpha1.RegularModuleStatus{}
+	}
+
+	syncModuleStatus(status.IngressStatus, obj, d.Readiness(item, obj))
+}
+
+func (d *ingressDriver) Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness {
+	if _, ok := obj.(*networkingv1.Ingress); !ok {
+		return ReadinessPending
+	}
+
+	return ReadinessReady
+}
+
+// renameIngressBackend replaces the service of backend by the created one if it is a service of item.
+func renameIngressBackend(rc *RenderContext, services map[string]bool, backend *networkingv1.IngressBackend) {
+	if backend == nil || backend.Service == nil || !services[backend.Service.Name] {
+		return
+	}
+
+	backend.Service.Name = rc.ModuleName(backend.Service.Name)
+}
@@ -0,0 +1,70 @@
+package driver
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+)
+
+func TestIngressDriver(t *testing.T) {
+	d := &ingressDriver{}
+
+	backend := func(name string) networkingv1.IngressBackend {
+		return networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: name}}
+	}
+
+	defaultBackend := backend("api")
+	item := &appsv1alpha1.Item{
+		Name: "item",
+		ItemModules: appsv1alpha1.ItemModuleResource{
+			Services: []appsv1alpha1.ServiceTemplate{
+				{TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "api"}},
+			},
+			Ingresses: []appsv1alpha1.IngressTemplate{
+				{
+					TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "web"},
+					Spec: networkingv1.IngressSpec{
+						DefaultBackend: &defaultBackend,
+						Rules: []networkingv1.IngressRule{
+							{IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{{Path: "/", Backend: backend("api")}, {Path: "/x", Backend: backend("external")}},
+							}}},
+							{Host: "no-http"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	objs, err := d.Render(newTestRenderContext(item))
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 ingress, got %d", len(objs))
+	}
+
+	ing := objs[0].(*networkingv1.Ingress)
+	if ing.Name != "job-item-web" || ing.Namespace != "ns" {
+		t.Errorf("unexpected ingress %s/%s", ing.Namespace, ing.Name)
+	}
+
+	paths := ing.Spec.Rules[0].HTTP.Paths
+	if ing.Spec.DefaultBackend.Service.Name != "job-item-api" || paths[0].Backend.Service.Name != "job-item-api" {
+		t.Errorf("service of item should be referred by its created name")
+	}
+	if paths[1].Backend.Service.Name != "external" {
+		t.Errorf("other services should be kept, got %s", paths[1].Backend.Service.Name)
+	}
+	if defaultBackend.Service.Name != "api" {
+		t.Errorf("template of item changed by rendering")
+	}
+
+	status := &appsv1alpha1.ItemStatus{}
+	d.SyncStatus(nil, ing, status)
+
+	if status.IngressStatus[ing.Name].Phase != appsv1alpha1.RegularModuleCreated {
+		t.Errorf("ingress should be created once exists, got %v", status.IngressStatus)
+	}
+}
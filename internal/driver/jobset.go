@@ -0,0 +1,247 @@
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"strings"
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=jobset.x-k8s.io,resources=jobsets,verbs=get;list;watch;create;delete
+
+// jobSetDriver runs the JobSets of item. JobSet is handled as unstructured object,
+// the state of JobSet comes from its conditions and the status of its replicated jobs.
+type jobSetDriver struct{}
+
+func (d *jobSetDriver) Name() string {
+	return "jobset"
+}
+
+func (d *jobSetDriver) Object() client.Object {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(appsv1alpha1.JobSetGroupVersionKind)
+	return obj
+}
+
+func (d *jobSetDriver) IsModule() bool {
+	return false
+}
+
+func (d *jobSetDriver) Render(rc *RenderContext) ([]client.Object, error) {
+	var res []client.Object
+
+	for _, itemJob := range rc.Item.ItemJobs.Jobs {
+		if itemJob.JobSetSpec == nil {
+			continue
+		}
+
+		spec := map[string]interface{}{}
+		if err := json.Unmarshal(itemJob.JobSetSpec.Raw, &spec); err != nil {
+			return nil, fmt.Errorf("unmarshal jobset spec of %s err: %s", itemJob.Name, err.Error())
+		}
+
+		if err := decorateReplicatedJobs(rc, spec); err != nil {
+			return nil, fmt.Errorf("decorate jobset %s err: %s", itemJob.Name, err.Error())
+		}
+
+		objectMeta := rc.ObjectMeta(rc.JobName(itemJob.Name), itemJob.TemplateBaseInfo)
+
+		// JobSet submitted to Kueue is created suspended, and resumed by Kueue once admitted
+		if queueName := appsv1alpha1.CalItemKueueQueueName(rc.Job, rc.Item); queueName != "" {
+			objectMeta.Labels[appsv1alpha1.KueueQueueNameLabel] = queueName
+			spec["suspend"] = true
+		}
+
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		obj.SetGroupVersionKind(appsv1alpha1.JobSetGroupVersionKind)
+
+		obj.SetName(objectMeta.Name)
+		obj.SetNamespace(objectMeta.Namespace)
+		obj.SetAnnotations(objectMeta.Annotations)
+		obj.SetLabels(objectMeta.Labels)
+
+		res = append(res, obj)
+	}
+
+	return res, nil
+}
+
+// decorateReplicatedJobs decorates the pod templates of the replicated jobs in spec of JobSet.
+func decorateReplicatedJobs(rc *RenderContext, spec map[string]interface{}) error {
+	replicatedJobs, _, err := unstructured.NestedSlice(spec, "replicatedJobs")
+	if err != nil {
+		return err
+	}
+
+	for i := range replicatedJobs {
+		replicatedJob, ok := replicatedJobs[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		obj, found, err := unstructured.NestedMap(replicatedJob, "template", "spec", "template")
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+
+		template := &corev1.PodTemplateSpec{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, template); err != nil {
+			return err
+		}
+
+		if err := rc.DecoratePodTemplate(template); err != nil {
+			return err
+		}
+
+		if obj, err = runtime.DefaultUnstructuredConverter.ToUnstructured(template); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedMap(replicatedJob, obj, "template", "spec", "template"); err != nil {
+			return err
+		}
+	}
+
+	return unstructured.SetNestedSlice(spec, replicatedJobs, "replicatedJobs")
+}
+
+func (d *jobSetDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return createOwned(ctx, c, job, obj)
+}
+
+// Delete deletes JobSet in background, so that its jobs and pods are deleted too.
+func (d *jobSetDriver) Delete(ctx context.Context, c client.Client, obj client.Object) error {
+	return deleteObject(ctx, c, obj, client.PropagationPolicy(metav1.DeletePropagationBackground))
+}
+
+// jobSetCondition returns the condition of JobSet with type if it is true.
+func jobSetCondition(obj *unstructured.Unstructured, conditionType string) (metav1.Condition, bool) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != conditionType || condition["status"] != string(corev1.ConditionTrue) {
+			continue
+		}
+
+		res := metav1.Condition{Type: conditionType, Status: metav1.ConditionTrue}
+		res.Reason, _, _ = unstructured.NestedString(condition, "reason")
+		res.Message, _, _ = unstructured.NestedString(condition, "message")
+		if t, _, _ := unstructured.NestedString(condition, "lastTransitionTime"); t != "" {
+			_ = res.LastTransitionTime.UnmarshalQueryParameter(t)
+		}
+
+		return res, true
+	}
+
+	return metav1.Condition{}, false
+}
+
+// replicatedJobsSummary returns the status of replicated jobs in one line, and whether any of them is active.
+func replicatedJobsSummary(obj *unstructured.Unstructured) (string, bool) {
+	statuses, _, _ := unstructured.NestedSlice(obj.Object, "status", "replicatedJobsStatus")
+
+	var summary []string
+	active := false
+	for _, s := range statuses {
+		status, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _, _ := unstructured.NestedString(status, "name")
+		num := map[string]int64{}
+		for _, field := range []string{"active", "ready", "succeeded", "failed", "suspended"} {
+			num[field], _, _ = unstructured.NestedInt64(status, field)
+		}
+
+		if num["active"] > 0 || num["ready"] > 0 {
+			active = true
+		}
+
+		summary = append(summary, fmt.Sprintf("%s: %d active, %d ready, %d succeeded, %d failed, %d suspended",
+			name, num["active"], num["ready"], num["succeeded"], num["failed"], num["suspended"]))
+	}
+
+	return strings.Join(summary, "; "), active
+}
+
+func (d *jobSetDriver) SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus) {
+	jobSet, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	if status.JobStatus == nil {
+		status.JobStatus = map[string]v1alpha1.JobState{}
+	}
+
+	if isDeleting(jobSet) {
+		status.JobStatus[jobSet.GetName()] = v1alpha1.JobState{
+			Phase:              v1alpha1.Terminated,
+			LastTransitionTime: *jobSet.GetDeletionTimestamp(),
+		}
+		return
+	}
+
+	summary, active := replicatedJobsSummary(jobSet)
+	jobState := v1alpha1.JobState{Phase: v1alpha1.Pending, Message: summary}
+
+	if condition, ok := jobSetCondition(jobSet, "Failed"); ok {
+		jobState = v1alpha1.JobState{Phase: v1alpha1.Failed, Reason: condition.Reason, Message: condition.Message,
+			LastTransitionTime: condition.LastTransitionTime}
+	} else if condition, ok := jobSetCondition(jobSet, "Completed"); ok {
+		jobState = v1alpha1.JobState{Phase: v1alpha1.Completed, Reason: condition.Reason, Message: condition.Message,
+			LastTransitionTime: condition.LastTransitionTime}
+	} else if suspended, _, _ := unstructured.NestedBool(jobSet.Object, "spec", "suspend"); suspended &&
+		jobSet.GetLabels()[appsv1alpha1.KueueQueueNameLabel] != "" {
+		// the message from Workload is kept until JobSet admitted
+		jobState.Reason = appsv1alpha1.WaitingAdmissionReason
+		if oldState := status.JobStatus[jobSet.GetName()]; oldState.Reason == appsv1alpha1.WaitingAdmissionReason {
+			jobState.Message = oldState.Message
+		}
+	} else if condition, ok := jobSetCondition(jobSet, "Suspended"); ok {
+		jobState.Reason = condition.Reason
+	} else if active {
+		jobState.Phase = v1alpha1.Running
+	}
+
+	if jobState.LastTransitionTime.IsZero() {
+		oldState, ok := status.JobStatus[jobSet.GetName()]
+		switch {
+		case !ok:
+			jobState.LastTransitionTime = jobSet.GetCreationTimestamp()
+		case oldState.Phase == jobState.Phase:
+			jobState.LastTransitionTime = oldState.LastTransitionTime
+		default:
+			jobState.LastTransitionTime = metav1.Now()
+		}
+	}
+
+	status.JobStatus[jobSet.GetName()] = jobState
+}
+
+func (d *jobSetDriver) Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness {
+	jobSet, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return ReadinessPending
+	}
+
+	if _, ok := jobSetCondition(jobSet, "Failed"); ok {
+		return ReadinessFailed
+	}
+	if _, ok := jobSetCondition(jobSet, "Completed"); ok {
+		return ReadinessReady
+	}
+
+	return ReadinessPending
+}
@@ -0,0 +1,196 @@
+package driver
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func TestJobSetDriverRender(t *testing.T) {
+	item := &appsv1alpha1.Item{
+		Name: "item",
+		ItemJobs: appsv1alpha1.ItemJobResource{
+			Jobs: []appsv1alpha1.ItemJobTemplate{
+				{
+					TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "train"},
+					JobSetSpec:       &runtime.RawExtension{Raw: []byte(`{"replicatedJobs":[{"name":"workers","replicas":2}]}`)},
+				},
+			},
+		},
+	}
+
+	objs, err := (&jobSetDriver{}).Render(newTestRenderContext(item))
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 jobset, got %d", len(objs))
+	}
+
+	jobSet := objs[0].(*unstructured.Unstructured)
+	if jobSet.GroupVersionKind() != appsv1alpha1.JobSetGroupVersionKind {
+		t.Errorf("unexpected kind %s", jobSet.GroupVersionKind().String())
+	}
+	if jobSet.GetName() != "job-item-train" || jobSet.GetNamespace() != "ns" {
+		t.Errorf("unexpected name %s/%s", jobSet.GetNamespace(), jobSet.GetName())
+	}
+	if jobs, _, _ := unstructured.NestedSlice(jobSet.Object, "spec", "replicatedJobs"); len(jobs) != 1 {
+		t.Errorf("spec of jobset not rendered, got %v", jobSet.Object["spec"])
+	}
+}
+
+func TestJobSetDriverRenderIsolated(t *testing.T) {
+	item := &appsv1alpha1.Item{
+		Name: "item",
+		ItemJobs: appsv1alpha1.ItemJobResource{
+			Jobs: []appsv1alpha1.ItemJobTemplate{
+				{
+					TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "train"},
+					JobSetSpec: &runtime.RawExtension{Raw: []byte(`{"replicatedJobs":[` +
+						`{"name":"workers","template":{"spec":{"template":{"metadata":{"labels":{"app":"x"}}}}}},` +
+						`{"name":"empty"}]}`)},
+				},
+			},
+		},
+	}
+
+	rc := newTestRenderContext(item)
+	rc.Job.Spec.NetworkIsolation = &appsv1alpha1.NetworkIsolation{}
+
+	objs, err := (&jobSetDriver{}).Render(rc)
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+
+	jobs, _, _ := unstructured.NestedSlice(objs[0].(*unstructured.Unstructured).Object, "spec", "replicatedJobs")
+	labels, _, _ := unstructured.NestedStringMap(jobs[0].(map[string]interface{}), "template", "spec", "template", "metadata", "labels")
+	if labels[appsv1alpha1.CreateByJob] != "job" || labels["app"] != "x" {
+		t.Errorf("pods of isolated job should be labeled with job, got %v", labels)
+	}
+	if _, found, _ := unstructured.NestedMap(jobs[1].(map[string]interface{}), "template"); found {
+		t.Errorf("replicated job without template should be kept")
+	}
+}
+
+func TestJobSetDriverSyncStatus(t *testing.T) {
+	d := &jobSetDriver{}
+
+	condition := func(conditionType string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":               conditionType,
+			"status":             "True",
+			"reason":             conditionType + "Reason",
+			"lastTransitionTime": "2023-10-01T00:00:00Z",
+		}
+	}
+
+	tests := []struct {
+		name      string
+		status    map[string]interface{}
+		phase     v1alpha1.JobPhase
+		readiness Readiness
+	}{
+		{name: "created", status: nil, phase: v1alpha1.Pending, readiness: ReadinessPending},
+		{name: "running", status: map[string]interface{}{
+			"replicatedJobsStatus": []interface{}{
+				map[string]interface{}{"name": "workers", "active": int64(2), "ready": int64(1)},
+			},
+		}, phase: v1alpha1.Running, readiness: ReadinessPending},
+		{name: "suspended", status: map[string]interface{}{
+			"conditions": []interface{}{condition("Suspended")},
+		}, phase: v1alpha1.Pending, readiness: ReadinessPending},
+		{name: "completed", status: map[string]interface{}{
+			"conditions": []interface{}{condition("Completed")},
+		}, phase: v1alpha1.Completed, readiness: ReadinessReady},
+		{name: "failed", status: map[string]interface{}{
+			"conditions": []interface{}{condition("Completed"), condition("Failed")},
+		}, phase: v1alpha1.Failed, readiness: ReadinessFailed},
+	}
+
+	for _, test := range tests {
+		jobSet := d.Object().(*unstructured.Unstructured)
+		jobSet.SetName("job-item-train")
+		if test.status != nil {
+			jobSet.Object["status"] = test.status
+		}
+
+		status := &appsv1alpha1.ItemStatus{}
+		d.SyncStatus(nil, jobSet, status)
+
+		state := status.JobStatus[jobSet.GetName()]
+		if state.Phase != test.phase {
+			t.Errorf("%s: expected phase %s, got %s", test.name, test.phase, state.Phase)
+		}
+		if readiness := d.Readiness(nil, jobSet); readiness != test.readiness {
+			t.Errorf("%s: expected readiness %s, got %s", test.name, test.readiness, readiness)
+		}
+		if test.name == "running" && state.Message != "workers: 2 active, 1 ready, 0 succeeded, 0 failed, 0 suspended" {
+			t.Errorf("replicated jobs not summarized, got %s", state.Message)
+		}
+		if test.name == "completed" && (state.Reason != "CompletedReason" || state.LastTransitionTime.IsZero()) {
+			t.Errorf("condition of jobset not recorded, got %v", state)
+		}
+	}
+}
+
+func TestJobSetDriverKueue(t *testing.T) {
+	d := &jobSetDriver{}
+
+	waiting := v1alpha1.JobState{Phase: v1alpha1.Pending, Reason: appsv1alpha1.WaitingAdmissionReason,
+		Message: "insufficient quota"}
+	suspended := map[string]interface{}{"type": "Suspended", "status": "True", "reason": "SuspendedReason"}
+	completed := map[string]interface{}{"type": "Completed", "status": "True", "reason": "AllJobsCompleted"}
+	active := []interface{}{map[string]interface{}{"name": "workers", "active": int64(2)}}
+
+	tests := []struct {
+		name      string
+		queueName string
+		suspend   bool
+		status    map[string]interface{}
+		oldState  *v1alpha1.JobState
+		phase     v1alpha1.JobPhase
+		reason    string
+		message   string
+	}{
+		{name: "created in queue", queueName: "team-a", suspend: true,
+			phase: v1alpha1.Pending, reason: appsv1alpha1.WaitingAdmissionReason},
+		{name: "workload message kept", queueName: "team-a", suspend: true,
+			status: map[string]interface{}{"conditions": []interface{}{suspended}}, oldState: &waiting,
+			phase: v1alpha1.Pending, reason: appsv1alpha1.WaitingAdmissionReason, message: "insufficient quota"},
+		{name: "suspended without queue", suspend: true,
+			status: map[string]interface{}{"conditions": []interface{}{suspended}},
+			phase:  v1alpha1.Pending, reason: "SuspendedReason"},
+		{name: "admitted", queueName: "team-a", status: map[string]interface{}{"replicatedJobsStatus": active},
+			oldState: &waiting, phase: v1alpha1.Running,
+			message: "workers: 2 active, 0 ready, 0 succeeded, 0 failed, 0 suspended"},
+		{name: "completed after admission", queueName: "team-a",
+			status:   map[string]interface{}{"conditions": []interface{}{completed}},
+			oldState: &waiting, phase: v1alpha1.Completed, reason: "AllJobsCompleted"},
+	}
+
+	for _, test := range tests {
+		jobSet := d.Object().(*unstructured.Unstructured)
+		jobSet.SetName("job-item-train")
+		if test.queueName != "" {
+			jobSet.SetLabels(map[string]string{appsv1alpha1.KueueQueueNameLabel: test.queueName})
+		}
+		jobSet.Object["spec"] = map[string]interface{}{"suspend": test.suspend}
+		if test.status != nil {
+			jobSet.Object["status"] = test.status
+		}
+
+		status := &appsv1alpha1.ItemStatus{JobStatus: map[string]v1alpha1.JobState{}}
+		if test.oldState != nil {
+			status.JobStatus[jobSet.GetName()] = *test.oldState
+		}
+		d.SyncStatus(nil, jobSet, status)
+
+		state := status.JobStatus[jobSet.GetName()]
+		if state.Phase != test.phase || state.Reason != test.reason || state.Message != test.message {
+			t.Errorf("%s: expected %s/%s/%s, got %s/%s/%s", test.name, test.phase, test.reason, test.message,
+				state.Phase, state.Reason, state.Message)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package driver
+
+import (
+	"context"
+	v1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+)
+
+const volcanoSchedulerName = "volcano"
+
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+
+// kubeJobDriver runs the kube jobs of item, script jobs are expanded into kube jobs before rendered.
+type kubeJobDriver struct{}
+
+func (d *kubeJobDriver) Name() string {
+	return "kube-job"
+}
+
+func (d *kubeJobDriver) Object() client.Object {
+	return &v1.Job{}
+}
+
+func (d *kubeJobDriver) IsModule() bool {
+	return false
+}
+
+func (d *kubeJobDriver) Render(rc *RenderContext) ([]client.Object, error) {
+	var res []client.Object
+
+	for _, itemJob := range rc.Item.ItemJobs.Jobs {
+		if itemJob.KubeJobSpec == nil {
+			continue
+		}
+
+		spec := itemJob.KubeJobSpec.DeepCopy()
+		if rc.Item.CoScheduleGroup != "" {
+			if spec.Template.Annotations == nil {
+				spec.Template.Annotations = map[string]string{}
+			}
+			spec.Template.Annotations[schedulingv1beta1.KubeGroupNameAnnotationKey] =
+				appsv1alpha1.CalJobCoScheduleGroupName(rc.Job.Name, rc.Item.CoScheduleGroup)
+			spec.Template.Spec.SchedulerName = volcanoSchedulerName
+		}
+		if err := rc.DecoratePodTemplate(&spec.Template); err != nil {
+			return nil, err
+		}
+
+		objectMeta := rc.ObjectMeta(rc.JobName(itemJob.Name), itemJob.TemplateBaseInfo)
+
+		// job submitted to Kueue is created suspended, and resumed by Kueue once admitted
+		if queueName := appsv1alpha1.CalItemKueueQueueName(rc.Job, rc.Item); queueName != "" {
+			objectMeta.Labels[appsv1alpha1.KueueQueueNameLabel] = queueName
+			suspend := true
+			spec.Suspend = &suspend
+		}
+
+		res = append(res, &v1.Job{
+			ObjectMeta: objectMeta,
+			Spec:       *spec,
+		})
+	}
+
+	return res, nil
+}
+
+func (d *kubeJobDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return createOwned(ctx, c, job, obj)
+}
+
+// Delete deletes job in background, so that its pods are deleted too.
+func (d *kubeJobDriver) Delete(ctx context.Context, c client.Client, obj client.Object) error {
+	return deleteObject(ctx, c, obj, client.PropagationPolicy(metav1.DeletePropagationBackground))
+}
+
+func (d *kubeJobDriver) SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus) {
+	job, ok := obj.(*v1.Job)
+	if !ok {
+		return
+	}
+
+	if status.JobStatus == nil {
+		status.JobStatus = map[string]v1alpha1.JobState{}
+	}
+
+	jobState, ok := status.JobStatus[job.Name]
+	if !ok {
+		jobState = v1alpha1.JobState{
+			Phase:              v1alpha1.Pending,
+			LastTransitionTime: job.CreationTimestamp,
+		}
+	}
+
+	if isDeleting(job) {
+		status.JobStatus[job.Name] = v1alpha1.JobState{
+			Phase:              v1alpha1.Terminated,
+			LastTransitionTime: *job.DeletionTimestamp,
+		}
+		return
+	}
+
+	conditionsLength := len(job.Status.Conditions)
+	if conditionsLength > 0 {
+		condition := job.Status.Conditions[conditionsLength-1]
+
+		jobState.LastTransitionTime = condition.LastTransitionTime
+		jobState.Message = condition.Message
+		jobState.Reason = condition.Reason
+
+		switch condition.Type {
+		case v1.JobSuspended:
+			// a resumed job is running again
+			if condition.Status == corev1.ConditionTrue {
+				jobState.Phase = v1alpha1.Aborted
+			} else {
+				jobState.Phase = v1alpha1.Running
+			}
+
+		case v1.JobComplete:
+			jobState.Phase = v1alpha1.Completed
+
+		case v1.JobFailureTarget, v1.JobFailed:
+			jobState.Phase = v1alpha1.Failed
+
+		default:
+			klog.Errorf("can not recognize kube job %s/%s condition type: %s", job.Namespace, job.Name, condition.Type)
+		}
+	}
+
+	// job submitted to Kueue stays suspended until admitted, the message from Workload is kept until then
+	if job.Labels[appsv1alpha1.KueueQueueNameLabel] != "" && job.Spec.Suspend != nil && *job.Spec.Suspend &&
+		jobState.Phase != v1alpha1.Completed && jobState.Phase != v1alpha1.Failed {
+		if oldState := status.JobStatus[job.Name]; oldState.Reason == appsv1alpha1.WaitingAdmissionReason {
+			jobState.Message = oldState.Message
+		}
+		jobState.Phase = v1alpha1.Pending
+		jobState.Reason = appsv1alpha1.WaitingAdmissionReason
+	} else if jobState.Reason == appsv1alpha1.WaitingAdmissionReason {
+		jobState.Phase = v1alpha1.Running
+		jobState.Reason = ""
+		jobState.Message = ""
+	}
+
+	status.JobStatus[job.Name] = jobState
+}
+
+func (d *kubeJobDriver) Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness {
+	job, ok := obj.(*v1.Job)
+	if !ok {
+		return ReadinessPending
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case v1.JobComplete:
+			return ReadinessReady
+		case v1.JobFailed:
+			return ReadinessFailed
+		}
+	}
+
+	return ReadinessPending
+}
@@ -0,0 +1,206 @@
+package driver
+
+import (
+	v1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	schedulingv1beta1 "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
+)
+
+func TestKubeJobDriverRender(t *testing.T) {
+	item := &appsv1alpha1.Item{
+		Name:            "item",
+		CoScheduleGroup: "group",
+		ItemJobs: appsv1alpha1.ItemJobResource{
+			Jobs: []appsv1alpha1.ItemJobTemplate{
+				{TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "train"}, KubeJobSpec: &v1.JobSpec{}},
+				{TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "vc"}, VolcanoJobSpec: &v1alpha1.JobSpec{}},
+			},
+		},
+	}
+
+	objs, err := (&kubeJobDriver{}).Render(newTestRenderContext(item))
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 kube job, got %d", len(objs))
+	}
+
+	job := objs[0].(*v1.Job)
+	if job.Name != "job-item-train" || job.Namespace != "ns" {
+		t.Errorf("unexpected name %s/%s", job.Namespace, job.Name)
+	}
+	if job.Spec.Template.Annotations[schedulingv1beta1.KubeGroupNameAnnotationKey] != "job-group" ||
+		job.Spec.Template.Spec.SchedulerName != volcanoSchedulerName {
+		t.Errorf("co-scheduled job should be scheduled by volcano in its group")
+	}
+	if item.ItemJobs.Jobs[0].KubeJobSpec.Template.Annotations != nil {
+		t.Errorf("template of item changed by rendering")
+	}
+}
+
+func TestKubeJobDriverSyncStatus(t *testing.T) {
+	d := &kubeJobDriver{}
+
+	tests := []struct {
+		name      string
+		condition *v1.JobCondition
+		deleting  bool
+		phase     v1alpha1.JobPhase
+		readiness Readiness
+	}{
+		{name: "no condition", phase: v1alpha1.Pending, readiness: ReadinessPending},
+		{name: "complete", condition: &v1.JobCondition{Type: v1.JobComplete, Status: corev1.ConditionTrue},
+			phase: v1alpha1.Completed, readiness: ReadinessReady},
+		{name: "failed", condition: &v1.JobCondition{Type: v1.JobFailed, Status: corev1.ConditionTrue},
+			phase: v1alpha1.Failed, readiness: ReadinessFailed},
+		{name: "suspended", condition: &v1.JobCondition{Type: v1.JobSuspended, Status: corev1.ConditionTrue},
+			phase: v1alpha1.Aborted, readiness: ReadinessPending},
+		{name: "deleting", deleting: true, phase: v1alpha1.Terminated, readiness: ReadinessPending},
+	}
+
+	for _, test := range tests {
+		job := &v1.Job{ObjectMeta: metav1.ObjectMeta{Name: "job-item-train"}}
+		if test.condition != nil {
+			job.Status.Conditions = []v1.JobCondition{*test.condition}
+		}
+		if test.deleting {
+			now := metav1.Now()
+			job.DeletionTimestamp = &now
+		}
+
+		status := &appsv1alpha1.ItemStatus{}
+		d.SyncStatus(nil, job, status)
+
+		if state := status.JobStatus[job.Name]; state.Phase != test.phase {
+			t.Errorf("%s: expected phase %s, got %s", test.name, test.phase, state.Phase)
+		}
+		if readiness := d.Readiness(nil, job); readiness != test.readiness {
+			t.Errorf("%s: expected readiness %s, got %s", test.name, test.readiness, readiness)
+		}
+	}
+}
+
+func TestKubeJobDriverKueue(t *testing.T) {
+	d := &kubeJobDriver{}
+
+	item := &appsv1alpha1.Item{
+		Name: "item",
+		ItemJobs: appsv1alpha1.ItemJobResource{
+			Jobs: []appsv1alpha1.ItemJobTemplate{
+				{TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "train"}, KubeJobSpec: &v1.JobSpec{}},
+			},
+		},
+	}
+
+	rc := newTestRenderContext(item)
+	objs, err := d.Render(rc)
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if job := objs[0].(*v1.Job); job.Labels[appsv1alpha1.KueueQueueNameLabel] != "" || job.Spec.Suspend != nil {
+		t.Errorf("job not submitted to kueue should not be labeled or suspended")
+	}
+
+	rc.Job.Spec.KueueQueueName = "team-a"
+	objs, err = d.Render(rc)
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if job := objs[0].(*v1.Job); job.Labels[appsv1alpha1.KueueQueueNameLabel] != "team-a" ||
+		job.Spec.Suspend == nil || !*job.Spec.Suspend {
+		t.Errorf("job submitted to kueue should be labeled and suspended")
+	}
+
+	waiting := v1alpha1.JobState{Phase: v1alpha1.Pending, Reason: appsv1alpha1.WaitingAdmissionReason,
+		Message: "insufficient quota"}
+	suspended := v1.JobCondition{Type: v1.JobSuspended, Status: corev1.ConditionTrue, Reason: "JobSuspended",
+		Message: "Job suspended"}
+	resumed := v1.JobCondition{Type: v1.JobSuspended, Status: corev1.ConditionFalse, Reason: "JobResumed",
+		Message: "Job resumed"}
+	complete := v1.JobCondition{Type: v1.JobComplete, Status: corev1.ConditionTrue}
+	failed := v1.JobCondition{Type: v1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"}
+
+	tests := []struct {
+		name       string
+		queueName  string
+		suspend    bool
+		conditions []v1.JobCondition
+		oldState   *v1alpha1.JobState
+		phase      v1alpha1.JobPhase
+		reason     string
+		message    string
+	}{
+		{name: "created in queue", queueName: "team-a", suspend: true,
+			phase: v1alpha1.Pending, reason: appsv1alpha1.WaitingAdmissionReason},
+		{name: "suspended in queue", queueName: "team-a", suspend: true, conditions: []v1.JobCondition{suspended},
+			phase: v1alpha1.Pending, reason: appsv1alpha1.WaitingAdmissionReason, message: "Job suspended"},
+		{name: "workload message kept", queueName: "team-a", suspend: true, conditions: []v1.JobCondition{suspended},
+			oldState: &waiting, phase: v1alpha1.Pending, reason: appsv1alpha1.WaitingAdmissionReason,
+			message: "insufficient quota"},
+		{name: "suspended without queue", suspend: true, conditions: []v1.JobCondition{suspended},
+			phase: v1alpha1.Aborted, reason: "JobSuspended", message: "Job suspended"},
+		{name: "admitted without condition", queueName: "team-a", oldState: &waiting,
+			phase: v1alpha1.Running},
+		{name: "admitted", queueName: "team-a", conditions: []v1.JobCondition{suspended, resumed},
+			oldState: &waiting, phase: v1alpha1.Running, reason: "JobResumed", message: "Job resumed"},
+		{name: "completed after admission", queueName: "team-a", conditions: []v1.JobCondition{suspended, resumed, complete},
+			oldState: &waiting, phase: v1alpha1.Completed},
+		{name: "failed while suspended", queueName: "team-a", suspend: true, conditions: []v1.JobCondition{suspended, failed},
+			oldState: &waiting, phase: v1alpha1.Failed, reason: "BackoffLimitExceeded"},
+	}
+
+	for _, test := range tests {
+		job := &v1.Job{ObjectMeta: metav1.ObjectMeta{Name: "job-item-train", Labels: map[string]string{}}}
+		if test.queueName != "" {
+			job.Labels[appsv1alpha1.KueueQueueNameLabel] = test.queueName
+		}
+		suspend := test.suspend
+		job.Spec.Suspend = &suspend
+		job.Status.Conditions = test.conditions
+
+		status := &appsv1alpha1.ItemStatus{JobStatus: map[string]v1alpha1.JobState{}}
+		if test.oldState != nil {
+			status.JobStatus[job.Name] = *test.oldState
+		}
+		d.SyncStatus(nil, job, status)
+
+		state := status.JobStatus[job.Name]
+		if state.Phase != test.phase || state.Reason != test.reason || state.Message != test.message {
+			t.Errorf("%s: expected %s/%s/%s, got %s/%s/%s", test.name, test.phase, test.reason, test.message,
+				state.Phase, state.Reason, state.Message)
+		}
+	}
+}
+
+func TestKubeJobDriverRenderScript(t *testing.T) {
+	item := appsv1alpha1.ExpandScriptJobs("job", &appsv1alpha1.Item{
+		Name: "item",
+		ItemJobs: appsv1alpha1.ItemJobResource{Jobs: []appsv1alpha1.ItemJobTemplate{{
+			TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "fetch"},
+			Script:           &appsv1alpha1.ScriptJobSpec{Image: "busybox", Source: "echo fetch"},
+		}}},
+	})
+	rc := newTestRenderContext(item)
+
+	jobs, err := (&kubeJobDriver{}).Render(rc)
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("expected 1 kube job, got %d %v", len(jobs), err)
+	}
+	configMaps, err := (&configMapDriver{}).Render(rc)
+	if err != nil || len(configMaps) != 1 {
+		t.Fatalf("expected 1 configmap, got %d %v", len(configMaps), err)
+	}
+
+	volume := jobs[0].(*v1.Job).Spec.Template.Spec.Volumes[0]
+	if volume.ConfigMap == nil || volume.ConfigMap.Name != configMaps[0].GetName() {
+		t.Errorf("script job should mount configmap %s, got %v", configMaps[0].GetName(), volume)
+	}
+	if data := configMaps[0].(*corev1.ConfigMap).Data[appsv1alpha1.ScriptFileName]; data != "echo fetch" {
+		t.Errorf("unexpected script %s", data)
+	}
+}
@@ -0,0 +1,49 @@
+package driver
+
+import (
+	"fmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+// syncWorkloadStatus records the state of the workload of long-running item, which is completed once ready
+// and keeps completed while it is torn down.
+func syncWorkloadStatus(obj client.Object, status *appsv1alpha1.ItemStatus, readiness Readiness, readyReplicas, replicas int32) {
+	if status.JobStatus == nil {
+		status.JobStatus = map[string]v1alpha1.JobState{}
+	}
+
+	jobState, ok := status.JobStatus[obj.GetName()]
+	if !ok {
+		jobState = v1alpha1.JobState{
+			Phase:              v1alpha1.Pending,
+			LastTransitionTime: obj.GetCreationTimestamp(),
+		}
+	}
+
+	phase := v1alpha1.Running
+	switch {
+	case isDeleting(obj):
+		if jobState.Phase != v1alpha1.Completed {
+			phase = v1alpha1.Terminated
+		} else {
+			phase = v1alpha1.Completed
+		}
+	case readiness == ReadinessReady:
+		phase = v1alpha1.Completed
+	case readiness == ReadinessFailed:
+		phase = v1alpha1.Failed
+	case readyReplicas == 0:
+		phase = v1alpha1.Pending
+	}
+
+	if ok && jobState.Phase != phase {
+		jobState.LastTransitionTime = metav1.Now()
+	}
+	jobState.Phase = phase
+	jobState.Message = fmt.Sprintf("%d/%d replicas ready", readyReplicas, replicas)
+
+	status.JobStatus[obj.GetName()] = jobState
+}
@@ -0,0 +1,65 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+// createOwned creates obj with job as its controller, so that obj is garbage collected with job.
+func createOwned(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	if err := controllerutil.SetControllerReference(job, obj, c.Scheme()); err != nil {
+		return fmt.Errorf("set owner of %s err: %s", obj.GetName(), err.Error())
+	}
+
+	if err := c.Create(ctx, obj); err != nil {
+		return fmt.Errorf("create %s err: %s", obj.GetName(), err.Error())
+	}
+
+	return nil
+}
+
+func deleteObject(ctx context.Context, c client.Client, obj client.Object, opts ...client.DeleteOption) error {
+	if err := c.Delete(ctx, obj, opts...); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("delete %s err: %s", obj.GetName(), err.Error())
+	}
+
+	return nil
+}
+
+func isDeleting(obj client.Object) bool {
+	return obj.GetDeletionTimestamp() != nil && !obj.GetDeletionTimestamp().IsZero()
+}
+
+// syncModuleStatus records the status of module into statuses by its readiness, a deleting module is failed.
+func syncModuleStatus(statuses map[string]appsv1alpha1.RegularModuleStatus, obj client.Object, readiness Readiness) {
+
+	moduleStatus, ok := statuses[obj.GetName()]
+	if !ok {
+		moduleStatus = appsv1alpha1.RegularModuleStatus{
+			Phase:              appsv1alpha1.RegularModuleUnknown,
+			LastTransitionTime: obj.GetCreationTimestamp(),
+		}
+	}
+
+	phase := appsv1alpha1.RegularModuleCreating
+	switch {
+	case isDeleting(obj):
+		phase = appsv1alpha1.RegularModuleFailed
+		moduleStatus.LastTransitionTime = *obj.GetDeletionTimestamp()
+	case readiness == ReadinessReady:
+		phase = appsv1alpha1.RegularModuleCreated
+	case readiness == ReadinessFailed:
+		phase = appsv1alpha1.RegularModuleFailed
+	}
+
+	if ok && moduleStatus.Phase != phase && !isDeleting(obj) {
+		moduleStatus.LastTransitionTime = metav1.Now()
+	}
+	moduleStatus.Phase = phase
+
+	statuses[obj.GetName()] = moduleStatus
+}
@@ -0,0 +1,40 @@
+package driver
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+	"time"
+)
+
+func TestSyncModuleStatus(t *testing.T) {
+	created := metav1.NewTime(time.Now().Add(-time.Minute))
+	obj := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cm", CreationTimestamp: created},
+	}
+
+	statuses := map[string]appsv1alpha1.RegularModuleStatus{}
+
+	syncModuleStatus(statuses, obj, ReadinessPending)
+	if status := statuses["cm"]; status.Phase != appsv1alpha1.RegularModuleCreating || !status.LastTransitionTime.Equal(&created) {
+		t.Errorf("new pending module should be creating since created, got %v", status)
+	}
+
+	syncModuleStatus(statuses, obj, ReadinessReady)
+	if status := statuses["cm"]; status.Phase != appsv1alpha1.RegularModuleCreated || status.LastTransitionTime.Equal(&created) {
+		t.Errorf("ready module should be created with new transition time, got %v", status)
+	}
+
+	syncModuleStatus(statuses, obj, ReadinessFailed)
+	if status := statuses["cm"]; status.Phase != appsv1alpha1.RegularModuleFailed {
+		t.Errorf("failed module should be failed, got %v", status)
+	}
+
+	deleted := metav1.Now()
+	obj.DeletionTimestamp = &deleted
+	syncModuleStatus(statuses, obj, ReadinessReady)
+	if status := statuses["cm"]; status.Phase != appsv1alpha1.RegularModuleFailed || !status.LastTransitionTime.Equal(&deleted) {
+		t.Errorf("deleting module should be failed since deleted, got %v", status)
+	}
+}
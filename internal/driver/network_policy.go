@@ -0,0 +1,66 @@
+package driver
+
+import (
+	"context"
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;delete
+
+// networkPolicyDriver creates the network policy modules of item, which are ready once created.
+type networkPolicyDriver struct{}
+
+func (d *networkPolicyDriver) Name() string {
+	return "network-policy"
+}
+
+func (d *networkPolicyDriver) Object() client.Object {
+	return &networkingv1.NetworkPolicy{}
+}
+
+func (d *networkPolicyDriver) IsModule() bool {
+	return true
+}
+
+func (d *networkPolicyDriver) Render(rc *RenderContext) ([]client.Object, error) {
+	var res []client.Object
+
+	for _, np := range rc.Item.ItemModules.NetworkPolicies {
+		res = append(res, &networkingv1.NetworkPolicy{
+			ObjectMeta: rc.ObjectMeta(rc.ModuleName(np.Name), np.TemplateBaseInfo),
+			Spec:       *np.Spec.DeepCopy(),
+		})
+	}
+
+	return res, nil
+}
+
+func (d *networkPolicyDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return createOwned(ctx, c, job, obj)
+}
+
+func (d *networkPolicyDriver) Delete(ctx context.Context, c client.Client, obj client.Object) error {
+	return deleteObject(ctx, c, obj)
+}
+
+func (d *networkPolicyDriver) SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus) {
+	if _, ok := obj.(*networkingv1.NetworkPolicy); !ok {
+		return
+	}
+
+	if status.NetworkPolicyStatus == nil {
+		status.NetworkPolicyStatus = map[string]appsv1alpha1.RegularModuleStatus{}
+	}
+
+	syncModuleStatus(status.NetworkPolicyStatus, obj, d.Readiness(item, obj))
+}
+
+func (d *networkPolicyDriver) Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness {
+	if _, ok := obj.(*networkingv1.NetworkPolicy); !ok {
+		return ReadinessPending
+	}
+
+	return ReadinessReady
+}
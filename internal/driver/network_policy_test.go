@@ -0,0 +1,43 @@
+package driver
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+)
+
+func TestNetworkPolicyDriver(t *testing.T) {
+	d := &networkPolicyDriver{}
+
+	item := &appsv1alpha1.Item{
+		Name: "item",
+		ItemModules: appsv1alpha1.ItemModuleResource{
+			NetworkPolicies: []appsv1alpha1.NetworkPolicyTemplate{
+				{
+					TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "deny"},
+					Spec:             networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}},
+				},
+			},
+		},
+	}
+
+	objs, err := d.Render(newTestRenderContext(item))
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 network policy, got %d", len(objs))
+	}
+
+	np := objs[0].(*networkingv1.NetworkPolicy)
+	if np.Name != "job-item-deny" || np.Namespace != "ns" || len(np.Spec.PolicyTypes) != 1 {
+		t.Errorf("unexpected network policy %s/%s", np.Namespace, np.Name)
+	}
+
+	status := &appsv1alpha1.ItemStatus{}
+	d.SyncStatus(nil, np, status)
+
+	if status.NetworkPolicyStatus[np.Name].Phase != appsv1alpha1.RegularModuleCreated {
+		t.Errorf("network policy should be created once exists, got %v", status.NetworkPolicyStatus)
+	}
+}
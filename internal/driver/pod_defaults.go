@@ -0,0 +1,101 @@
+package driver
+
+import (
+	"encoding/json"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+// applyPodDefaults merges template into the pod defaults of job by strategic merge, so that the values of
+// template win, like env of the same name. Tolerations have no merge key, they are merged by mergeTolerations.
+func applyPodDefaults(defaults *appsv1alpha1.PodDefaults, template *corev1.PodTemplateSpec) error {
+	if defaults == nil {
+		return nil
+	}
+
+	base := &corev1.PodTemplateSpec{}
+	base.Labels = defaults.Labels
+	base.Annotations = defaults.Annotations
+	base.Spec.NodeSelector = defaults.NodeSelector
+	base.Spec.ImagePullSecrets = defaults.ImagePullSecrets
+	base.Spec.PriorityClassName = defaults.PriorityClassName
+	base.Spec.ServiceAccountName = defaults.ServiceAccountName
+
+	// containers are merged by name, env of defaults goes to the containers of the same names
+	for i := range template.Spec.InitContainers {
+		base.Spec.InitContainers = append(base.Spec.InitContainers, defaultContainer(defaults, &template.Spec.InitContainers[i]))
+	}
+	for i := range template.Spec.Containers {
+		base.Spec.Containers = append(base.Spec.Containers, defaultContainer(defaults, &template.Spec.Containers[i]))
+	}
+
+	original, err := json.Marshal(base)
+	if err != nil {
+		return fmt.Errorf("apply pod defaults err: %s", err.Error())
+	}
+
+	patch, err := json.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("apply pod defaults err: %s", err.Error())
+	}
+
+	merged, err := strategicpatch.StrategicMergePatch(original, patch, corev1.PodTemplateSpec{})
+	if err != nil {
+		return fmt.Errorf("apply pod defaults err: %s", err.Error())
+	}
+
+	res := corev1.PodTemplateSpec{}
+	if err := json.Unmarshal(merged, &res); err != nil {
+		return fmt.Errorf("apply pod defaults err: %s", err.Error())
+	}
+	res.Spec.Tolerations = mergeTolerations(defaults.Tolerations, res.Spec.Tolerations)
+	*template = res
+
+	return nil
+}
+
+// defaultContainer returns the defaults of container. Env set by container is left out, otherwise a value of
+// defaults would be merged with a valueFrom of container.
+func defaultContainer(defaults *appsv1alpha1.PodDefaults, container *corev1.Container) corev1.Container {
+	set := map[string]bool{}
+	for _, e := range container.Env {
+		set[e.Name] = true
+	}
+
+	res := corev1.Container{Name: container.Name}
+	for _, e := range defaults.Env {
+		if !set[e.Name] {
+			res.Env = append(res.Env, e)
+		}
+	}
+
+	return res
+}
+
+// mergeTolerations returns tolerations followed by the defaults whose key and effect are not tolerated by them,
+// so that a toleration of template overrides the defaults of the same key and effect only.
+func mergeTolerations(defaults, tolerations []corev1.Toleration) []corev1.Toleration {
+	res := append([]corev1.Toleration{}, tolerations...)
+
+	for _, d := range defaults {
+		overridden := false
+		for _, toleration := range tolerations {
+			if toleration.Key == d.Key && toleration.Effect == d.Effect {
+				overridden = true
+				break
+			}
+		}
+
+		if !overridden {
+			res = append(res, d)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil
+	}
+
+	return res
+}
@@ -0,0 +1,146 @@
+package driver
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"reflect"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func TestApplyPodDefaults(t *testing.T) {
+	defaults := &appsv1alpha1.PodDefaults{
+		Labels:             map[string]string{"team": "a", "app": "default"},
+		NodeSelector:       map[string]string{"pool": "gpu"},
+		Tolerations:        []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists}},
+		ImagePullSecrets:   []corev1.LocalObjectReference{{Name: "registry"}},
+		PriorityClassName:  "high",
+		ServiceAccountName: "runner",
+		Env: []corev1.EnvVar{
+			{Name: "A", Value: "default"},
+			{Name: "B", Value: "default"},
+		},
+	}
+
+	template := &corev1.PodTemplateSpec{}
+	template.Labels = map[string]string{"app": "item"}
+	template.Spec.Tolerations = []corev1.Toleration{{Key: "cpu", Operator: corev1.TolerationOpExists}}
+	template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "mirror"}}
+	template.Spec.ServiceAccountName = "item"
+	template.Spec.Containers = []corev1.Container{
+		{
+			Name:  "main",
+			Image: "busybox",
+			Env: []corev1.EnvVar{
+				{Name: "B", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+			},
+		},
+		{Name: "sidecar", Image: "busybox"},
+	}
+
+	if err := applyPodDefaults(defaults, template); err != nil {
+		t.Fatalf("apply pod defaults err: %s", err.Error())
+	}
+
+	if template.Labels["team"] != "a" || template.Labels["app"] != "item" {
+		t.Errorf("labels of item should win over defaults, got %v", template.Labels)
+	}
+	if template.Spec.NodeSelector["pool"] != "gpu" || template.Spec.PriorityClassName != "high" {
+		t.Errorf("defaults not applied, got %v %s", template.Spec.NodeSelector, template.Spec.PriorityClassName)
+	}
+	if template.Spec.ServiceAccountName != "item" {
+		t.Errorf("service account of item should win, got %s", template.Spec.ServiceAccountName)
+	}
+	if len(template.Spec.Tolerations) != 2 || template.Spec.Tolerations[0].Key != "cpu" || template.Spec.Tolerations[1].Key != "gpu" {
+		t.Errorf("tolerations of defaults should be added to item, got %v", template.Spec.Tolerations)
+	}
+	if len(template.Spec.ImagePullSecrets) != 2 {
+		t.Errorf("image pull secrets should be merged, got %v", template.Spec.ImagePullSecrets)
+	}
+
+	if len(template.Spec.Containers) != 2 || template.Spec.Containers[0].Image != "busybox" {
+		t.Fatalf("containers changed, got %v", template.Spec.Containers)
+	}
+	for _, e := range template.Spec.Containers[0].Env {
+		if e.Name == "B" && (e.Value != "" || e.ValueFrom == nil) {
+			t.Errorf("env of item should win, got %v", e)
+		}
+	}
+	if len(template.Spec.Containers[0].Env) != 2 || len(template.Spec.Containers[1].Env) != 2 {
+		t.Errorf("env of defaults not applied to all containers, got %v", template.Spec.Containers)
+	}
+}
+
+func TestMergeTolerations(t *testing.T) {
+	gpu := corev1.Toleration{Key: "gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	spot := corev1.Toleration{Key: "spot", Operator: corev1.TolerationOpEqual, Value: "true", Effect: corev1.TaintEffectNoSchedule}
+
+	tests := []struct {
+		name        string
+		defaults    []corev1.Toleration
+		tolerations []corev1.Toleration
+		expected    []corev1.Toleration
+	}{
+		{name: "no tolerations"},
+		{name: "defaults only", defaults: []corev1.Toleration{gpu, spot}, expected: []corev1.Toleration{gpu, spot}},
+		{name: "item only", tolerations: []corev1.Toleration{gpu}, expected: []corev1.Toleration{gpu}},
+		{
+			name:        "different keys",
+			defaults:    []corev1.Toleration{gpu},
+			tolerations: []corev1.Toleration{spot},
+			expected:    []corev1.Toleration{spot, gpu},
+		},
+		{
+			name:        "same key and effect overridden",
+			defaults:    []corev1.Toleration{gpu, spot},
+			tolerations: []corev1.Toleration{{Key: "spot", Operator: corev1.TolerationOpEqual, Value: "false", Effect: corev1.TaintEffectNoSchedule}},
+			expected: []corev1.Toleration{
+				{Key: "spot", Operator: corev1.TolerationOpEqual, Value: "false", Effect: corev1.TaintEffectNoSchedule}, gpu,
+			},
+		},
+		{
+			name:        "same key of other effect kept",
+			defaults:    []corev1.Toleration{gpu},
+			tolerations: []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute}},
+			expected: []corev1.Toleration{
+				{Key: "gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute}, gpu,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		if res := mergeTolerations(test.defaults, test.tolerations); !reflect.DeepEqual(res, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, res)
+		}
+	}
+}
+
+func TestVolcanoJobDriverRenderPodDefaults(t *testing.T) {
+	item := &appsv1alpha1.Item{
+		Name: "item",
+		ItemJobs: appsv1alpha1.ItemJobResource{
+			Jobs: []appsv1alpha1.ItemJobTemplate{
+				{
+					TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "train"},
+					VolcanoJobSpec: &v1alpha1.JobSpec{
+						Tasks: []v1alpha1.TaskSpec{{Name: "master"}, {Name: "worker"}},
+					},
+				},
+			},
+		},
+	}
+
+	rc := newTestRenderContext(item)
+	rc.Job.Spec.PodDefaults = &appsv1alpha1.PodDefaults{NodeSelector: map[string]string{"pool": "gpu"}}
+
+	objs, err := (&volcanoJobDriver{}).Render(rc)
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+
+	for _, task := range objs[0].(*v1alpha1.Job).Spec.Tasks {
+		if task.Template.Spec.NodeSelector["pool"] != "gpu" {
+			t.Errorf("pod defaults not applied to task %s", task.Name)
+		}
+	}
+}
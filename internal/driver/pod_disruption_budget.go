@@ -0,0 +1,71 @@
+package driver
+
+import (
+	"context"
+	policyv1 "k8s.io/api/policy/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;delete
+
+// podDisruptionBudgetDriver creates the pod disruption budget modules of item, which are ready once observed by the disruption controller.
+type podDisruptionBudgetDriver struct{}
+
+func (d *podDisruptionBudgetDriver) Name() string {
+	return "pod-disruption-budget"
+}
+
+func (d *podDisruptionBudgetDriver) Object() client.Object {
+	return &policyv1.PodDisruptionBudget{}
+}
+
+func (d *podDisruptionBudgetDriver) IsModule() bool {
+	return true
+}
+
+func (d *podDisruptionBudgetDriver) Render(rc *RenderContext) ([]client.Object, error) {
+	var res []client.Object
+
+	for _, pdb := range rc.Item.ItemModules.PodDisruptionBudgets {
+		res = append(res, &policyv1.PodDisruptionBudget{
+			ObjectMeta: rc.ObjectMeta(rc.ModuleName(pdb.Name), pdb.TemplateBaseInfo),
+			Spec:       *pdb.Spec.DeepCopy(),
+		})
+	}
+
+	return res, nil
+}
+
+func (d *podDisruptionBudgetDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return createOwned(ctx, c, job, obj)
+}
+
+func (d *podDisruptionBudgetDriver) Delete(ctx context.Context, c client.Client, obj client.Object) error {
+	return deleteObject(ctx, c, obj)
+}
+
+func (d *podDisruptionBudgetDriver) SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus) {
+	if _, ok := obj.(*policyv1.PodDisruptionBudget); !ok {
+		return
+	}
+
+	if status.PodDisruptionBudgetStatus == nil {
+		status.PodDisruptionBudgetStatus = map[string]appsv1alpha1.RegularModuleStatus{}
+	}
+
+	syncModuleStatus(status.PodDisruptionBudgetStatus, obj, d.Readiness(item, obj))
+}
+
+func (d *podDisruptionBudgetDriver) Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness {
+	pdb, ok := obj.(*policyv1.PodDisruptionBudget)
+	if !ok {
+		return ReadinessPending
+	}
+
+	if pdb.Status.ObservedGeneration < pdb.Generation {
+		return ReadinessPending
+	}
+
+	return ReadinessReady
+}
@@ -0,0 +1,53 @@
+package driver
+
+import (
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+)
+
+func TestPodDisruptionBudgetDriver(t *testing.T) {
+	d := &podDisruptionBudgetDriver{}
+
+	minAvailable := intstr.FromInt(1)
+	item := &appsv1alpha1.Item{
+		Name: "item",
+		ItemModules: appsv1alpha1.ItemModuleResource{
+			PodDisruptionBudgets: []appsv1alpha1.PodDisruptionBudgetTemplate{
+				{
+					TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "workers"},
+					Spec:             policyv1.PodDisruptionBudgetSpec{MinAvailable: &minAvailable},
+				},
+			},
+		},
+	}
+
+	objs, err := d.Render(newTestRenderContext(item))
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 pod disruption budget, got %d", len(objs))
+	}
+
+	pdb := objs[0].(*policyv1.PodDisruptionBudget)
+	if pdb.Name != "job-item-workers" || pdb.Namespace != "ns" || pdb.Spec.MinAvailable.IntValue() != 1 {
+		t.Errorf("unexpected pod disruption budget %s/%s", pdb.Namespace, pdb.Name)
+	}
+
+	pdb.Generation = 1
+	status := &appsv1alpha1.ItemStatus{}
+	d.SyncStatus(nil, pdb, status)
+
+	if status.PodDisruptionBudgetStatus[pdb.Name].Phase != appsv1alpha1.RegularModuleCreating {
+		t.Errorf("pod disruption budget not observed should be creating, got %v", status.PodDisruptionBudgetStatus)
+	}
+
+	pdb.Status.ObservedGeneration = 1
+	d.SyncStatus(nil, pdb, status)
+
+	if status.PodDisruptionBudgetStatus[pdb.Name].Phase != appsv1alpha1.RegularModuleCreated {
+		t.Errorf("observed pod disruption budget should be created, got %v", status.PodDisruptionBudgetStatus)
+	}
+}
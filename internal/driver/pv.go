@@ -0,0 +1,78 @@
+package driver
+
+import (
+	"context"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups="",resources=persistentvolumes,verbs=get;list;watch;create;delete
+
+// pvDriver creates the pv modules of item, which are ready once available to claims.
+// Pv is cluster scoped and can not be owned by job, so that it is deleted explicitly while job deleted.
+type pvDriver struct{}
+
+func (d *pvDriver) Name() string {
+	return "pv"
+}
+
+func (d *pvDriver) Object() client.Object {
+	return &corev1.PersistentVolume{}
+}
+
+func (d *pvDriver) IsModule() bool {
+	return true
+}
+
+func (d *pvDriver) Render(rc *RenderContext) ([]client.Object, error) {
+	var res []client.Object
+
+	for _, pv := range rc.Item.ItemModules.Pvs {
+		objectMeta := rc.ObjectMeta(rc.ModuleName(pv.Name), pv.TemplateBaseInfo)
+		objectMeta.Namespace = ""
+
+		res = append(res, &corev1.PersistentVolume{
+			ObjectMeta: objectMeta,
+			Spec:       *pv.Pv.DeepCopy(),
+		})
+	}
+
+	return res, nil
+}
+
+func (d *pvDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return c.Create(ctx, obj)
+}
+
+func (d *pvDriver) Delete(ctx context.Context, c client.Client, obj client.Object) error {
+	return deleteObject(ctx, c, obj)
+}
+
+func (d *pvDriver) SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus) {
+	if _, ok := obj.(*corev1.PersistentVolume); !ok {
+		return
+	}
+
+	if status.PvStatus == nil {
+		status.PvStatus = map[string]appsv1alpha1.RegularModuleStatus{}
+	}
+
+	syncModuleStatus(status.PvStatus, obj, d.Readiness(item, obj))
+}
+
+func (d *pvDriver) Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness {
+	pv, ok := obj.(*corev1.PersistentVolume)
+	if !ok {
+		return ReadinessPending
+	}
+
+	switch pv.Status.Phase {
+	case corev1.VolumeAvailable, corev1.VolumeBound:
+		return ReadinessReady
+	case corev1.VolumeFailed, corev1.VolumeReleased:
+		return ReadinessFailed
+	}
+
+	return ReadinessPending
+}
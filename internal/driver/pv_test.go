@@ -0,0 +1,60 @@
+package driver
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+)
+
+func TestPvDriver(t *testing.T) {
+	d := &pvDriver{}
+
+	item := &appsv1alpha1.Item{
+		Name: "item",
+		ItemModules: appsv1alpha1.ItemModuleResource{
+			Pvs: []appsv1alpha1.PvTemplate{
+				{TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "data"}},
+			},
+		},
+	}
+
+	objs, err := d.Render(newTestRenderContext(item))
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 pv, got %d", len(objs))
+	}
+
+	pv := objs[0].(*corev1.PersistentVolume)
+	if pv.Name != "job-item-data" || pv.Namespace != "" {
+		t.Errorf("pv should be cluster scoped, got %s/%s", pv.Namespace, pv.Name)
+	}
+	if pv.Labels[appsv1alpha1.CreateByJob] != "job" {
+		t.Errorf("pv should be labeled with its job, got %v", pv.Labels)
+	}
+
+	tests := []struct {
+		phase       corev1.PersistentVolumePhase
+		modulePhase appsv1alpha1.RegularModulePhase
+	}{
+		{phase: corev1.VolumePending, modulePhase: appsv1alpha1.RegularModuleCreating},
+		{phase: corev1.VolumeAvailable, modulePhase: appsv1alpha1.RegularModuleCreated},
+		{phase: corev1.VolumeBound, modulePhase: appsv1alpha1.RegularModuleCreated},
+		{phase: corev1.VolumeReleased, modulePhase: appsv1alpha1.RegularModuleFailed},
+	}
+
+	status := &appsv1alpha1.ItemStatus{}
+	for _, test := range tests {
+		pv.Status.Phase = test.phase
+		d.SyncStatus(nil, pv, status)
+
+		if status.PvStatus[pv.Name].Phase != test.modulePhase {
+			t.Errorf("%s pv should be %s, got %s", test.phase, test.modulePhase, status.PvStatus[pv.Name].Phase)
+		}
+	}
+
+	if len(status.SecretStatus) != 0 {
+		t.Errorf("pv should not be recorded as secret, got %v", status.SecretStatus)
+	}
+}
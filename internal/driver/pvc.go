@@ -0,0 +1,74 @@
+package driver
+
+import (
+	"context"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;delete
+
+// pvcDriver creates the pvc modules of item, which are ready once bound.
+type pvcDriver struct{}
+
+func (d *pvcDriver) Name() string {
+	return "pvc"
+}
+
+func (d *pvcDriver) Object() client.Object {
+	return &corev1.PersistentVolumeClaim{}
+}
+
+func (d *pvcDriver) IsModule() bool {
+	return true
+}
+
+func (d *pvcDriver) Render(rc *RenderContext) ([]client.Object, error) {
+	var res []client.Object
+
+	for _, pvc := range rc.Item.ItemModules.Pvcs {
+		res = append(res, &corev1.PersistentVolumeClaim{
+			ObjectMeta: rc.ObjectMeta(rc.ModuleName(pvc.Name), pvc.TemplateBaseInfo),
+			Spec:       *pvc.Pvc.DeepCopy(),
+		})
+	}
+
+	return res, nil
+}
+
+func (d *pvcDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return createOwned(ctx, c, job, obj)
+}
+
+func (d *pvcDriver) Delete(ctx context.Context, c client.Client, obj client.Object) error {
+	return deleteObject(ctx, c, obj)
+}
+
+func (d *pvcDriver) SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus) {
+	if _, ok := obj.(*corev1.PersistentVolumeClaim); !ok {
+		return
+	}
+
+	if status.PvcStatus == nil {
+		status.PvcStatus = map[string]appsv1alpha1.RegularModuleStatus{}
+	}
+
+	syncModuleStatus(status.PvcStatus, obj, d.Readiness(item, obj))
+}
+
+func (d *pvcDriver) Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness {
+	pvc, ok := obj.(*corev1.PersistentVolumeClaim)
+	if !ok {
+		return ReadinessPending
+	}
+
+	switch pvc.Status.Phase {
+	case corev1.ClaimBound:
+		return ReadinessReady
+	case corev1.ClaimLost:
+		return ReadinessFailed
+	}
+
+	return ReadinessPending
+}
@@ -0,0 +1,56 @@
+package driver
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+)
+
+func TestPvcDriver(t *testing.T) {
+	d := &pvcDriver{}
+
+	item := &appsv1alpha1.Item{
+		Name: "item",
+		ItemModules: appsv1alpha1.ItemModuleResource{
+			Pvcs: []appsv1alpha1.PvcTemplate{
+				{TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "data"}},
+			},
+		},
+	}
+
+	objs, err := d.Render(newTestRenderContext(item))
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 pvc, got %d", len(objs))
+	}
+
+	pvc := objs[0].(*corev1.PersistentVolumeClaim)
+	if pvc.Name != "job-item-data" || pvc.Namespace != "ns" {
+		t.Errorf("unexpected pvc %s/%s", pvc.Namespace, pvc.Name)
+	}
+
+	tests := []struct {
+		phase       corev1.PersistentVolumeClaimPhase
+		modulePhase appsv1alpha1.RegularModulePhase
+	}{
+		{phase: corev1.ClaimPending, modulePhase: appsv1alpha1.RegularModuleCreating},
+		{phase: corev1.ClaimBound, modulePhase: appsv1alpha1.RegularModuleCreated},
+		{phase: corev1.ClaimLost, modulePhase: appsv1alpha1.RegularModuleFailed},
+	}
+
+	status := &appsv1alpha1.ItemStatus{}
+	for _, test := range tests {
+		pvc.Status.Phase = test.phase
+		d.SyncStatus(nil, pvc, status)
+
+		if status.PvcStatus[pvc.Name].Phase != test.modulePhase {
+			t.Errorf("%s pvc should be %s, got %s", test.phase, test.modulePhase, status.PvcStatus[pvc.Name].Phase)
+		}
+	}
+
+	if len(status.SecretStatus) != 0 {
+		t.Errorf("pvc should not be recorded as secret, got %v", status.SecretStatus)
+	}
+}
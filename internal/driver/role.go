@@ -0,0 +1,66 @@
+package driver
+
+import (
+	"context"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=get;list;watch;create;delete
+
+// roleDriver creates the role modules of item, which are ready once created.
+type roleDriver struct{}
+
+func (d *roleDriver) Name() string {
+	return "role"
+}
+
+func (d *roleDriver) Object() client.Object {
+	return &rbacv1.Role{}
+}
+
+func (d *roleDriver) IsModule() bool {
+	return true
+}
+
+func (d *roleDriver) Render(rc *RenderContext) ([]client.Object, error) {
+	var res []client.Object
+
+	for _, role := range rc.Item.ItemModules.Roles {
+		res = append(res, &rbacv1.Role{
+			ObjectMeta: rc.ObjectMeta(rc.ModuleName(role.Name), role.TemplateBaseInfo),
+			Rules:      append([]rbacv1.PolicyRule{}, role.Rules...),
+		})
+	}
+
+	return res, nil
+}
+
+func (d *roleDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return createOwned(ctx, c, job, obj)
+}
+
+func (d *roleDriver) Delete(ctx context.Context, c client.Client, obj client.Object) error {
+	return deleteObject(ctx, c, obj)
+}
+
+func (d *roleDriver) SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus) {
+	if _, ok := obj.(*rbacv1.Role); !ok {
+		return
+	}
+
+	if status.RoleStatus == nil {
+		status.RoleStatus = map[string]appsv1alpha1.RegularModuleStatus{}
+	}
+
+	syncModuleStatus(status.RoleStatus, obj, d.Readiness(item, obj))
+}
+
+func (d *roleDriver) Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness {
+	if _, ok := obj.(*rbacv1.Role); !ok {
+		return ReadinessPending
+	}
+
+	return ReadinessReady
+}
@@ -0,0 +1,94 @@
+package driver
+
+import (
+	"context"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;delete
+
+// roleBindingDriver creates the role binding modules of item, which are ready once created.
+// Roles and service accounts of item referred by their template names are replaced by the created ones.
+// Role bindings only refer to the roles of item or the allowed cluster roles, which is checked before rendered.
+type roleBindingDriver struct{}
+
+func (d *roleBindingDriver) Name() string {
+	return "role-binding"
+}
+
+func (d *roleBindingDriver) Object() client.Object {
+	return &rbacv1.RoleBinding{}
+}
+
+func (d *roleBindingDriver) IsModule() bool {
+	return true
+}
+
+func (d *roleBindingDriver) Render(rc *RenderContext) ([]client.Object, error) {
+	var res []client.Object
+
+	roles := map[string]bool{}
+	for _, role := range rc.Item.ItemModules.Roles {
+		roles[role.Name] = true
+	}
+
+	serviceAccounts := map[string]bool{}
+	for _, sa := range rc.Item.ItemModules.ServiceAccounts {
+		serviceAccounts[sa.Name] = true
+	}
+
+	for _, rb := range rc.Item.ItemModules.RoleBindings {
+		roleRef := rb.RoleRef
+		roleRef.APIGroup = rbacv1.GroupName
+		if roleRef.Kind == "Role" && roles[roleRef.Name] {
+			roleRef.Name = rc.ModuleName(roleRef.Name)
+		}
+
+		subjects := make([]rbacv1.Subject, 0, len(rb.Subjects))
+		for _, subject := range rb.Subjects {
+			if subject.Kind == rbacv1.ServiceAccountKind && subject.Namespace == "" && serviceAccounts[subject.Name] {
+				subject.Name = rc.ModuleName(subject.Name)
+				subject.Namespace = rc.Job.Namespace
+			}
+			subjects = append(subjects, subject)
+		}
+
+		res = append(res, &rbacv1.RoleBinding{
+			ObjectMeta: rc.ObjectMeta(rc.ModuleName(rb.Name), rb.TemplateBaseInfo),
+			RoleRef:    roleRef,
+			Subjects:   subjects,
+		})
+	}
+
+	return res, nil
+}
+
+func (d *roleBindingDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return createOwned(ctx, c, job, obj)
+}
+
+func (d *roleBindingDriver) Delete(ctx context.Context, c client.Client, obj client.Object) error {
+	return deleteObject(ctx, c, obj)
+}
+
+func (d *roleBindingDriver) SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus) {
+	if _, ok := obj.(*rbacv1.RoleBinding); !ok {
+		return
+	}
+
+	if status.RoleBindingStatus == nil {
+		status.RoleBindingStatus = map[string]appsv1alpha1.RegularModuleStatus{}
+	}
+
+	syncModuleStatus(status.RoleBindingStatus, obj, d.Readiness(item, obj))
+}
+
+func (d *roleBindingDriver) Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness {
+	if _, ok := obj.(*rbacv1.RoleBinding); !ok {
+		return ReadinessPending
+	}
+
+	return ReadinessReady
+}
@@ -0,0 +1,106 @@
+package driver
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	"reflect"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+)
+
+func TestRoleBindingDriverRender(t *testing.T) {
+	modules := appsv1alpha1.ItemModuleResource{
+		ServiceAccounts: []appsv1alpha1.ServiceAccountTemplate{
+			{TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "runner"}},
+		},
+		Roles: []appsv1alpha1.RoleTemplate{
+			{TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "reader"}},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		roleRef        rbacv1.RoleRef
+		subjects       []rbacv1.Subject
+		expectRef      rbacv1.RoleRef
+		expectSubjects []rbacv1.Subject
+	}{
+		{
+			name:      "role of item",
+			roleRef:   rbacv1.RoleRef{Kind: "Role", Name: "reader"},
+			expectRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "job-item-reader"},
+		},
+		{
+			name:      "role not of item",
+			roleRef:   rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "writer"},
+			expectRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "writer"},
+		},
+		{
+			// cluster roles are never replaced by the roles of item, even with the same name
+			name:      "cluster role named as role of item",
+			roleRef:   rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "reader"},
+			expectRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "reader"},
+		},
+		{
+			name:    "service account of item",
+			roleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+			subjects: []rbacv1.Subject{
+				{Kind: rbacv1.ServiceAccountKind, Name: "runner"},
+			},
+			expectRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "view"},
+			expectSubjects: []rbacv1.Subject{
+				{Kind: rbacv1.ServiceAccountKind, Name: "job-item-runner", Namespace: "ns"},
+			},
+		},
+		{
+			name:    "other subjects kept",
+			roleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+			subjects: []rbacv1.Subject{
+				{Kind: rbacv1.ServiceAccountKind, Name: "runner", Namespace: "other"},
+				{Kind: rbacv1.ServiceAccountKind, Name: "default"},
+				{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "runner"},
+			},
+			expectRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "view"},
+			expectSubjects: []rbacv1.Subject{
+				{Kind: rbacv1.ServiceAccountKind, Name: "runner", Namespace: "other"},
+				{Kind: rbacv1.ServiceAccountKind, Name: "default"},
+				{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "runner"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		item := &appsv1alpha1.Item{Name: "item", ItemModules: *modules.DeepCopy()}
+		item.ItemModules.RoleBindings = []appsv1alpha1.RoleBindingTemplate{{
+			TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "binding"},
+			RoleRef:          test.roleRef,
+			Subjects:         test.subjects,
+		}}
+		template := item.DeepCopy()
+
+		objs, err := (&roleBindingDriver{}).Render(newTestRenderContext(item))
+		if err != nil || len(objs) != 1 {
+			t.Fatalf("%s: expected 1 role binding, got %d %v", test.name, len(objs), err)
+		}
+
+		rb := objs[0].(*rbacv1.RoleBinding)
+		if rb.Name != "job-item-binding" || rb.Namespace != "ns" {
+			t.Errorf("%s: unexpected role binding %s/%s", test.name, rb.Namespace, rb.Name)
+		}
+		if rb.RoleRef != test.expectRef {
+			t.Errorf("%s: expected role ref %v, got %v", test.name, test.expectRef, rb.RoleRef)
+		}
+		if len(rb.Subjects) != len(test.expectSubjects) {
+			t.Errorf("%s: expected subjects %v, got %v", test.name, test.expectSubjects, rb.Subjects)
+			continue
+		}
+		for i := range rb.Subjects {
+			if rb.Subjects[i] != test.expectSubjects[i] {
+				t.Errorf("%s: expected subjects %v, got %v", test.name, test.expectSubjects, rb.Subjects)
+			}
+		}
+
+		if !reflect.DeepEqual(item, template) {
+			t.Errorf("%s: template of item changed by rendering", test.name)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package driver
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+)
+
+func TestRoleDriver(t *testing.T) {
+	d := &roleDriver{}
+
+	item := &appsv1alpha1.Item{
+		Name: "item",
+		ItemModules: appsv1alpha1.ItemModuleResource{
+			Roles: []appsv1alpha1.RoleTemplate{
+				{
+					TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "reader"},
+					Rules:            []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}},
+				},
+			},
+		},
+	}
+
+	objs, err := d.Render(newTestRenderContext(item))
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 role, got %d", len(objs))
+	}
+
+	role := objs[0].(*rbacv1.Role)
+	if role.Name != "job-item-reader" || role.Namespace != "ns" || len(role.Rules) != 1 {
+		t.Errorf("unexpected role %s/%s", role.Namespace, role.Name)
+	}
+
+	status := &appsv1alpha1.ItemStatus{}
+	d.SyncStatus(nil, role, status)
+
+	if status.RoleStatus[role.Name].Phase != appsv1alpha1.RegularModuleCreated {
+		t.Errorf("role should be created once exists, got %v", status.RoleStatus)
+	}
+}
@@ -0,0 +1,80 @@
+package driver
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	corev1 "k8s.io/api/core/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"songf.sh/songf/pkg/seal"
+	"testing"
+)
+
+func TestSecretEncryptedData(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key err: %s", err.Error())
+	}
+
+	sealed := func(namespace, name, value string) string {
+		res, err := seal.Seal(&key.PublicKey, namespace, name, []byte(value))
+		if err != nil {
+			t.Fatalf("seal err: %s", err.Error())
+		}
+		return res
+	}
+
+	tests := []struct {
+		name   string
+		sealed string
+		fail   bool
+	}{
+		{name: "sealed for template", sealed: sealed("ns", "creds", "secret")},
+		{name: "sealed for another namespace", sealed: sealed("other", "creds", "secret"), fail: true},
+		{name: "sealed for another template", sealed: sealed("ns", "other", "secret"), fail: true},
+		{name: "not sealed", sealed: "c2VjcmV0", fail: true},
+	}
+
+	for _, test := range tests {
+		item := &appsv1alpha1.Item{
+			Name: "item",
+			ItemModules: appsv1alpha1.ItemModuleResource{
+				Secrets: []appsv1alpha1.SecretTemplate{
+					{
+						TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "creds"},
+						Secret:           corev1.Secret{StringData: map[string]string{"token": "plain", "user": "u"}},
+						EncryptedData:    map[string]string{"token": test.sealed},
+					},
+				},
+			},
+		}
+
+		rc := newTestRenderContext(item)
+
+		// encrypted data is kept encrypted without key
+		objs, err := (&secretDriver{}).Render(rc)
+		if err != nil || len(objs[0].(*corev1.Secret).Data) != 0 {
+			t.Errorf("%s: encrypted data should not be opened without key, err %v", test.name, err)
+		}
+
+		rc.SealKey = key
+		objs, err = (&secretDriver{}).Render(rc)
+		if test.fail {
+			if err == nil {
+				t.Errorf("%s: render should fail", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: render err: %s", test.name, err.Error())
+			continue
+		}
+
+		secret := objs[0].(*corev1.Secret)
+		if string(secret.Data["token"]) != "secret" {
+			t.Errorf("%s: expected opened token, got %s", test.name, secret.Data["token"])
+		}
+		if _, ok := secret.StringData["token"]; ok || secret.StringData["user"] != "u" {
+			t.Errorf("%s: encrypted data should win over string data, got %v", test.name, secret.StringData)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"songf.sh/songf/pkg/seal"
+)
+
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;delete
+
+// secretDriver creates the secret modules of item, which are ready once created.
+type secretDriver struct{}
+
+func (d *secretDriver) Name() string {
+	return "secret"
+}
+
+func (d *secretDriver) Object() client.Object {
+	return &corev1.Secret{}
+}
+
+func (d *secretDriver) IsModule() bool {
+	return true
+}
+
+func (d *secretDriver) Render(rc *RenderContext) ([]client.Object, error) {
+	var res []client.Object
+
+	for _, secret := range rc.Item.ItemModules.Secrets {
+		secretImpl := secret.Secret.DeepCopy()
+
+		objectMeta := rc.ObjectMeta(rc.ModuleName(secret.Name), secret.TemplateBaseInfo)
+		secretImpl.Name = objectMeta.Name
+		secretImpl.Namespace = objectMeta.Namespace
+		secretImpl.Annotations = objectMeta.Annotations
+		secretImpl.Labels = objectMeta.Labels
+
+		if err := mergeSecretSources(rc, secret, secretImpl); err != nil {
+			return nil, fmt.Errorf("secret %s err: %s", secret.Name, err.Error())
+		}
+
+		if err := openEncryptedData(rc, secret, secretImpl); err != nil {
+			return nil, fmt.Errorf("secret %s err: %s", secret.Name, err.Error())
+		}
+
+		res = append(res, secretImpl)
+	}
+
+	return res, nil
+}
+
+// mergeSecretSources copies the data of the value sources of template into secret, the data of secret wins.
+// Secret takes the type of the first source if its type is not set.
+func mergeSecretSources(rc *RenderContext, template appsv1alpha1.SecretTemplate, secret *corev1.Secret) error {
+	if rc.Reader == nil || len(template.ValueFrom) == 0 {
+		return nil
+	}
+
+	data := map[string][]byte{}
+	for _, source := range template.ValueFrom {
+		sourceSecret := &corev1.Secret{}
+		found, err := readValueSource(rc, source, sourceSecret)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+
+		if secret.Type == "" {
+			secret.Type = sourceSecret.Type
+		}
+
+		keys := map[string]bool{}
+		for key := range sourceSecret.Data {
+			keys[key] = true
+		}
+
+		selected, err := sourceKeys(source, keys)
+		if err != nil {
+			return err
+		}
+
+		for _, key := range selected {
+			data[key] = sourceSecret.Data[key]
+		}
+	}
+
+	// stringData of secret is merged over data by api server
+	for key, value := range secret.Data {
+		data[key] = value
+	}
+
+	secret.Data = nil
+	if len(data) > 0 {
+		secret.Data = data
+	}
+
+	return nil
+}
+
+// openEncryptedData decrypts the encrypted data of template into secret, which wins over the other data.
+func openEncryptedData(rc *RenderContext, template appsv1alpha1.SecretTemplate, secret *corev1.Secret) error {
+	if rc.SealKey == nil || len(template.EncryptedData) == 0 {
+		return nil
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	for key, sealed := range template.EncryptedData {
+		data, err := seal.Open(rc.SealKey, rc.Job.Namespace, template.Name, sealed)
+		if err != nil {
+			return fmt.Errorf("open encrypted %s err: %s", key, err.Error())
+		}
+
+		secret.Data[key] = data
+		delete(secret.StringData, key)
+	}
+
+	return nil
+}
+
+func (d *secretDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return createOwned(ctx, c, job, obj)
+}
+
+func (d *secretDriver) Delete(ctx context.Context, c client.Client, obj client.Object) error {
+	return deleteObject(ctx, c, obj)
+}
+
+func (d *secretDriver) SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus) {
+	if _, ok := obj.(*corev1.Secret); !ok {
+		return
+	}
+
+	if status.SecretStatus == nil {
+		status.SecretStatus = map[string]appsv1alpha1.RegularModuleStatus{}
+	}
+
+	syncModuleStatus(status.SecretStatus, obj, d.Readiness(item, obj))
+}
+
+func (d *secretDriver) Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness {
+	if _, ok := obj.(*corev1.Secret); !ok {
+		return ReadinessPending
+	}
+
+	return ReadinessReady
+}
@@ -0,0 +1,43 @@
+package driver
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+)
+
+func TestSecretDriver(t *testing.T) {
+	d := &secretDriver{}
+
+	item := &appsv1alpha1.Item{
+		Name: "item",
+		ItemModules: appsv1alpha1.ItemModuleResource{
+			Secrets: []appsv1alpha1.SecretTemplate{
+				{
+					TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "token"},
+					Secret:           corev1.Secret{StringData: map[string]string{"token": "x"}},
+				},
+			},
+		},
+	}
+
+	objs, err := d.Render(newTestRenderContext(item))
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 secret, got %d", len(objs))
+	}
+
+	secret := objs[0].(*corev1.Secret)
+	if secret.Name != "job-item-token" || secret.Namespace != "ns" || secret.StringData["token"] != "x" {
+		t.Errorf("unexpected secret %s/%s", secret.Namespace, secret.Name)
+	}
+
+	status := &appsv1alpha1.ItemStatus{}
+	d.SyncStatus(nil, secret, status)
+
+	if status.SecretStatus[secret.Name].Phase != appsv1alpha1.RegularModuleCreated {
+		t.Errorf("secret should be created once exists, got %v", status.SecretStatus)
+	}
+}
@@ -0,0 +1,66 @@
+package driver
+
+import (
+	"context"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;delete
+
+// serviceDriver creates the service modules of item, which are ready once created.
+type serviceDriver struct{}
+
+func (d *serviceDriver) Name() string {
+	return "service"
+}
+
+func (d *serviceDriver) Object() client.Object {
+	return &corev1.Service{}
+}
+
+func (d *serviceDriver) IsModule() bool {
+	return true
+}
+
+func (d *serviceDriver) Render(rc *RenderContext) ([]client.Object, error) {
+	var res []client.Object
+
+	for _, service := range rc.Item.ItemModules.Services {
+		res = append(res, &corev1.Service{
+			ObjectMeta: rc.ObjectMeta(rc.ModuleName(service.Name), service.TemplateBaseInfo),
+			Spec:       *service.Spec.DeepCopy(),
+		})
+	}
+
+	return res, nil
+}
+
+func (d *serviceDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return createOwned(ctx, c, job, obj)
+}
+
+func (d *serviceDriver) Delete(ctx context.Context, c client.Client, obj client.Object) error {
+	return deleteObject(ctx, c, obj)
+}
+
+func (d *serviceDriver) SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus) {
+	if _, ok := obj.(*corev1.Service); !ok {
+		return
+	}
+
+	if status.ServiceStatus == nil {
+		status.ServiceStatus = map[string]appsv1alpha1.RegularModuleStatus{}
+	}
+
+	syncModuleStatus(status.ServiceStatus, obj, d.Readiness(item, obj))
+}
+
+func (d *serviceDriver) Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness {
+	if _, ok := obj.(*corev1.Service); !ok {
+		return ReadinessPending
+	}
+
+	return ReadinessReady
+}
@@ -0,0 +1,71 @@
+package driver
+
+import (
+	"context"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;delete
+
+// serviceAccountDriver creates the service account modules of item, which are ready once created.
+type serviceAccountDriver struct{}
+
+func (d *serviceAccountDriver) Name() string {
+	return "service-account"
+}
+
+func (d *serviceAccountDriver) Object() client.Object {
+	return &corev1.ServiceAccount{}
+}
+
+func (d *serviceAccountDriver) IsModule() bool {
+	return true
+}
+
+func (d *serviceAccountDriver) Render(rc *RenderContext) ([]client.Object, error) {
+	var res []client.Object
+
+	for _, sa := range rc.Item.ItemModules.ServiceAccounts {
+		saImpl := sa.ServiceAccount.DeepCopy()
+
+		objectMeta := rc.ObjectMeta(rc.ModuleName(sa.Name), sa.TemplateBaseInfo)
+		saImpl.Name = objectMeta.Name
+		saImpl.Namespace = objectMeta.Namespace
+		saImpl.Annotations = objectMeta.Annotations
+		saImpl.Labels = objectMeta.Labels
+
+		res = append(res, saImpl)
+	}
+
+	return res, nil
+}
+
+func (d *serviceAccountDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return createOwned(ctx, c, job, obj)
+}
+
+func (d *serviceAccountDriver) Delete(ctx context.Context, c client.Client, obj client.Object) error {
+	return deleteObject(ctx, c, obj)
+}
+
+func (d *serviceAccountDriver) SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus) {
+	if _, ok := obj.(*corev1.ServiceAccount); !ok {
+		return
+	}
+
+	if status.ServiceAccountStatus == nil {
+		status.ServiceAccountStatus = map[string]appsv1alpha1.RegularModuleStatus{}
+	}
+
+	syncModuleStatus(status.ServiceAccountStatus, obj, d.Readiness(item, obj))
+}
+
+func (d *serviceAccountDriver) Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness {
+	if _, ok := obj.(*corev1.ServiceAccount); !ok {
+		return ReadinessPending
+	}
+
+	return ReadinessReady
+}
@@ -0,0 +1,44 @@
+package driver
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+)
+
+func TestServiceAccountDriver(t *testing.T) {
+	d := &serviceAccountDriver{}
+
+	automount := false
+	item := &appsv1alpha1.Item{
+		Name: "item",
+		ItemModules: appsv1alpha1.ItemModuleResource{
+			ServiceAccounts: []appsv1alpha1.ServiceAccountTemplate{
+				{
+					TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "runner"},
+					ServiceAccount:   corev1.ServiceAccount{AutomountServiceAccountToken: &automount},
+				},
+			},
+		},
+	}
+
+	objs, err := d.Render(newTestRenderContext(item))
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 service account, got %d", len(objs))
+	}
+
+	sa := objs[0].(*corev1.ServiceAccount)
+	if sa.Name != "job-item-runner" || sa.Namespace != "ns" || sa.AutomountServiceAccountToken == nil || *sa.AutomountServiceAccountToken {
+		t.Errorf("unexpected service account %s/%s", sa.Namespace, sa.Name)
+	}
+
+	status := &appsv1alpha1.ItemStatus{}
+	d.SyncStatus(nil, sa, status)
+
+	if status.ServiceAccountStatus[sa.Name].Phase != appsv1alpha1.RegularModuleCreated {
+		t.Errorf("service account should be created once exists, got %v", status.ServiceAccountStatus)
+	}
+}
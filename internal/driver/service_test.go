@@ -0,0 +1,49 @@
+package driver
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+)
+
+func TestServiceDriver(t *testing.T) {
+	d := &serviceDriver{}
+
+	item := &appsv1alpha1.Item{
+		Name: "item",
+		ItemModules: appsv1alpha1.ItemModuleResource{
+			Services: []appsv1alpha1.ServiceTemplate{
+				{TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "db"}, Spec: corev1.ServiceSpec{ClusterIP: "None"}},
+			},
+		},
+	}
+
+	objs, err := d.Render(newTestRenderContext(item))
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 service, got %d", len(objs))
+	}
+
+	service := objs[0].(*corev1.Service)
+	if service.Name != "job-item-db" || service.Namespace != "ns" || service.Spec.ClusterIP != "None" {
+		t.Errorf("unexpected service %s/%s", service.Namespace, service.Name)
+	}
+
+	status := &appsv1alpha1.ItemStatus{}
+	d.SyncStatus(nil, service, status)
+
+	if status.ServiceStatus[service.Name].Phase != appsv1alpha1.RegularModuleCreated {
+		t.Errorf("service should be created once exists, got %v", status.ServiceStatus)
+	}
+
+	now := metav1.Now()
+	service.DeletionTimestamp = &now
+	d.SyncStatus(nil, service, status)
+
+	if status.ServiceStatus[service.Name].Phase != appsv1alpha1.RegularModuleFailed {
+		t.Errorf("deleting service should be failed, got %v", status.ServiceStatus)
+	}
+}
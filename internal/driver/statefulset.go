@@ -0,0 +1,85 @@
+package driver
+
+import (
+	"context"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;delete
+
+// statefulSetDriver runs the StatefulSet of long-running item, which is completed once all replicas are ready.
+type statefulSetDriver struct{}
+
+func (d *statefulSetDriver) Name() string {
+	return "statefulset"
+}
+
+func (d *statefulSetDriver) Object() client.Object {
+	return &appsv1.StatefulSet{}
+}
+
+func (d *statefulSetDriver) IsModule() bool {
+	return false
+}
+
+func (d *statefulSetDriver) Render(rc *RenderContext) ([]client.Object, error) {
+	longRunning := rc.Item.LongRunning
+	if longRunning == nil || longRunning.StatefulSet == nil {
+		return nil, nil
+	}
+
+	spec := longRunning.StatefulSet.DeepCopy()
+	if err := rc.DecoratePodTemplate(&spec.Template); err != nil {
+		return nil, err
+	}
+
+	return []client.Object{&appsv1.StatefulSet{
+		ObjectMeta: rc.ObjectMeta(rc.JobName(longRunning.Name), longRunning.TemplateBaseInfo),
+		Spec:       *spec,
+	}}, nil
+}
+
+func (d *statefulSetDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return createOwned(ctx, c, job, obj)
+}
+
+// Delete deletes StatefulSet in background, so that its pods are deleted too.
+func (d *statefulSetDriver) Delete(ctx context.Context, c client.Client, obj client.Object) error {
+	return deleteObject(ctx, c, obj, client.PropagationPolicy(metav1.DeletePropagationBackground))
+}
+
+func (d *statefulSetDriver) SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus) {
+	statefulSet, ok := obj.(*appsv1.StatefulSet)
+	if !ok {
+		return
+	}
+
+	syncWorkloadStatus(obj, status, d.Readiness(item, obj), statefulSet.Status.ReadyReplicas, statefulSetReplicas(statefulSet))
+}
+
+// Readiness returns ready once the latest spec is observed and all replicas are updated and ready.
+func (d *statefulSetDriver) Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness {
+	statefulSet, ok := obj.(*appsv1.StatefulSet)
+	if !ok {
+		return ReadinessPending
+	}
+
+	replicas := statefulSetReplicas(statefulSet)
+	if statefulSet.Status.ObservedGeneration >= statefulSet.Generation &&
+		statefulSet.Status.UpdatedReplicas >= replicas && statefulSet.Status.ReadyReplicas >= replicas {
+		return ReadinessReady
+	}
+
+	return ReadinessPending
+}
+
+func statefulSetReplicas(statefulSet *appsv1.StatefulSet) int32 {
+	if statefulSet.Spec.Replicas == nil {
+		return 1
+	}
+
+	return *statefulSet.Spec.Replicas
+}
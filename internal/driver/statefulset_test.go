@@ -0,0 +1,70 @@
+package driver
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func TestStatefulSetDriverRender(t *testing.T) {
+	item := &appsv1alpha1.Item{
+		Name: "item",
+		LongRunning: &appsv1alpha1.LongRunningTemplate{
+			TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "db"},
+			StatefulSet:      &appsv1.StatefulSetSpec{ServiceName: "db"},
+		},
+	}
+
+	objs, err := (&statefulSetDriver{}).Render(newTestRenderContext(item))
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 statefulset, got %d", len(objs))
+	}
+
+	statefulSet := objs[0].(*appsv1.StatefulSet)
+	if statefulSet.Name != "job-item-db" || statefulSet.Namespace != "ns" || statefulSet.Spec.ServiceName != "db" {
+		t.Errorf("unexpected statefulset %s/%s", statefulSet.Namespace, statefulSet.Name)
+	}
+}
+
+func TestStatefulSetDriverSyncStatus(t *testing.T) {
+	d := &statefulSetDriver{}
+
+	tests := []struct {
+		name      string
+		status    appsv1.StatefulSetStatus
+		phase     v1alpha1.JobPhase
+		readiness Readiness
+	}{
+		{name: "created", phase: v1alpha1.Pending, readiness: ReadinessPending},
+		{name: "starting", status: appsv1.StatefulSetStatus{ObservedGeneration: 1, Replicas: 1},
+			phase: v1alpha1.Pending, readiness: ReadinessPending},
+		{name: "ready", status: appsv1.StatefulSetStatus{ObservedGeneration: 1, UpdatedReplicas: 1, ReadyReplicas: 1},
+			phase: v1alpha1.Completed, readiness: ReadinessReady},
+	}
+
+	for _, test := range tests {
+		statefulSet := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "job-item-db", Generation: 1},
+			Status:     test.status,
+		}
+
+		status := &appsv1alpha1.ItemStatus{}
+		d.SyncStatus(nil, statefulSet, status)
+
+		state := status.JobStatus[statefulSet.Name]
+		if state.Phase != test.phase {
+			t.Errorf("%s: expected phase %s, got %s", test.name, test.phase, state.Phase)
+		}
+		if readiness := d.Readiness(nil, statefulSet); readiness != test.readiness {
+			t.Errorf("%s: expected readiness %s, got %s", test.name, test.readiness, readiness)
+		}
+		if test.name == "ready" && state.Message != "1/1 replicas ready" {
+			t.Errorf("replicas not summarized, got %s", state.Message)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package driver
+
+import (
+	"context"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+// subJobDriver runs the sub job of item, which is a songf Job owned by the Job of item.
+type subJobDriver struct{}
+
+func (d *subJobDriver) Name() string {
+	return "sub-job"
+}
+
+func (d *subJobDriver) Object() client.Object {
+	return &appsv1alpha1.Job{}
+}
+
+func (d *subJobDriver) IsModule() bool {
+	return false
+}
+
+func (d *subJobDriver) Render(rc *RenderContext) ([]client.Object, error) {
+	if rc.Item.SubJob == nil {
+		return nil, nil
+	}
+
+	spec, err := appsv1alpha1.GetSubJobSpec(rc.Item)
+	if err != nil {
+		return nil, err
+	}
+
+	objectMeta := rc.ObjectMeta(rc.JobName(rc.Item.SubJob.Name), rc.Item.SubJob.TemplateBaseInfo)
+
+	// the annotations of parent job about its own creation are not for sub job
+	delete(objectMeta.Annotations, appsv1alpha1.TemplateGenerationAnnotation)
+	delete(objectMeta.Annotations, appsv1alpha1.ScheduledTimeAnnotation)
+
+	return []client.Object{
+		&appsv1alpha1.Job{
+			ObjectMeta: objectMeta,
+			Spec:       *spec,
+		},
+	}, nil
+}
+
+func (d *subJobDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return createOwned(ctx, c, job, obj)
+}
+
+// Delete deletes sub job in background, so that the objects of its items are deleted too.
+func (d *subJobDriver) Delete(ctx context.Context, c client.Client, obj client.Object) error {
+	return deleteObject(ctx, c, obj, client.PropagationPolicy(metav1.DeletePropagationBackground))
+}
+
+func (d *subJobDriver) SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus) {
+	subJob, ok := obj.(*appsv1alpha1.Job)
+	if !ok {
+		return
+	}
+
+	subJobStatus := &appsv1alpha1.SubJobStatus{
+		Name:    subJob.Name,
+		State:   subJob.Status.State,
+		ItemNum: int32(len(appsv1alpha1.CalJobItems(subJob))),
+	}
+
+	for _, itemStatus := range subJob.Status.ItemStatus {
+		switch itemStatus.Phase {
+		case appsv1alpha1.ItemScheduling, appsv1alpha1.ItemScheduled:
+			subJobStatus.RunningItemNum++
+		case appsv1alpha1.ItemCompleted:
+			subJobStatus.CompletedItemNum++
+		case appsv1alpha1.ItemFailed:
+			subJobStatus.FailedItemNum++
+		}
+	}
+
+	status.SubJobStatus = subJobStatus
+}
+
+func (d *subJobDriver) Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness {
+	subJob, ok := obj.(*appsv1alpha1.Job)
+	if !ok {
+		return ReadinessPending
+	}
+
+	switch subJob.Status.State.Phase {
+	case appsv1alpha1.Completed:
+		return ReadinessReady
+	case appsv1alpha1.Failed, appsv1alpha1.Terminated:
+		return ReadinessFailed
+	}
+
+	return ReadinessPending
+}
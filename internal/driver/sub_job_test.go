@@ -0,0 +1,83 @@
+package driver
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+)
+
+func TestSubJobDriverRender(t *testing.T) {
+	item := &appsv1alpha1.Item{
+		Name: "item",
+		SubJob: &appsv1alpha1.SubJobTemplate{
+			TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "child"},
+			Spec:             runtime.RawExtension{Raw: []byte(`{"items":[{"name":"a"}]}`)},
+		},
+	}
+	rc := newTestRenderContext(item)
+	rc.Job.Annotations[appsv1alpha1.TemplateGenerationAnnotation] = "1"
+	rc.Job.Annotations[appsv1alpha1.ScheduledTimeAnnotation] = "2024-01-01T00:00:00Z"
+
+	objs, err := (&subJobDriver{}).Render(rc)
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 sub job, got %d", len(objs))
+	}
+
+	subJob := objs[0].(*appsv1alpha1.Job)
+	if subJob.Name != "job-item-child" || subJob.Namespace != "ns" || len(subJob.Spec.Items) != 1 {
+		t.Errorf("unexpected sub job %s/%s with %d items", subJob.Namespace, subJob.Name, len(subJob.Spec.Items))
+	}
+	if _, ok := subJob.Annotations[appsv1alpha1.TemplateGenerationAnnotation]; ok {
+		t.Errorf("template generation of parent job should not be copied to sub job")
+	}
+	if _, ok := subJob.Annotations[appsv1alpha1.ScheduledTimeAnnotation]; ok {
+		t.Errorf("scheduled time of parent job should not be copied to sub job")
+	}
+
+	if objs, err := (&subJobDriver{}).Render(newTestRenderContext(&appsv1alpha1.Item{Name: "item"})); err != nil || len(objs) != 0 {
+		t.Errorf("item without sub job should render nothing, got %d %v", len(objs), err)
+	}
+}
+
+func TestSubJobDriverSyncStatus(t *testing.T) {
+	d := &subJobDriver{}
+
+	subJob := &appsv1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job-item-child"},
+		Spec:       appsv1alpha1.JobSpec{Items: []appsv1alpha1.Item{{Name: "a"}, {Name: "b"}, {Name: "c"}}},
+		Status: appsv1alpha1.JobStatus{ItemStatus: map[string]appsv1alpha1.ItemStatus{
+			"a": {Phase: appsv1alpha1.ItemCompleted},
+			"b": {Phase: appsv1alpha1.ItemScheduled},
+			"c": {Phase: appsv1alpha1.ItemFailed},
+		}},
+	}
+	subJob.Status.State.Phase = appsv1alpha1.Scheduled
+
+	status := &appsv1alpha1.ItemStatus{}
+	d.SyncStatus(nil, subJob, status)
+
+	if s := status.SubJobStatus; s == nil || s.ItemNum != 3 || s.RunningItemNum != 1 || s.CompletedItemNum != 1 || s.FailedItemNum != 1 {
+		t.Errorf("unexpected sub job status %v", s)
+	}
+
+	tests := []struct {
+		phase     appsv1alpha1.JobPhase
+		readiness Readiness
+	}{
+		{phase: appsv1alpha1.Scheduled, readiness: ReadinessPending},
+		{phase: appsv1alpha1.Completed, readiness: ReadinessReady},
+		{phase: appsv1alpha1.Failed, readiness: ReadinessFailed},
+		{phase: appsv1alpha1.Terminated, readiness: ReadinessFailed},
+	}
+
+	for _, test := range tests {
+		subJob.Status.State.Phase = test.phase
+		if readiness := d.Readiness(nil, subJob); readiness != test.readiness {
+			t.Errorf("%s: expected readiness %s, got %s", test.phase, test.readiness, readiness)
+		}
+	}
+}
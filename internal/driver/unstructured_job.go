@@ -0,0 +1,162 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+// UnstructuredJobDriverName is the name of the driver of unstructured jobs, whose kinds are watched by the job
+// controller once the Jobs using them are seen.
+const UnstructuredJobDriverName = "unstructured-job"
+
+// Kinds of unstructured jobs are not granted by the role of operator, installer grants get, list, watch, create and
+// delete of the kinds set by --unstructured-job-kinds, see config/rbac/unstructured_job_role.yaml.
+
+// unstructuredJobDriver runs the unstructured jobs of item, the status of object is mapped to the state of job
+// by the rules of its unstructured job in item.
+type unstructuredJobDriver struct{}
+
+func (d *unstructuredJobDriver) Name() string {
+	return UnstructuredJobDriverName
+}
+
+func (d *unstructuredJobDriver) Object() client.Object {
+	return nil
+}
+
+func (d *unstructuredJobDriver) IsModule() bool {
+	return false
+}
+
+func (d *unstructuredJobDriver) Render(rc *RenderContext) ([]client.Object, error) {
+	var res []client.Object
+
+	for _, itemJob := range rc.Item.ItemJobs.Jobs {
+		if itemJob.Unstructured == nil {
+			continue
+		}
+
+		object, err := appsv1alpha1.GetUnstructuredJobObject(itemJob.Unstructured)
+		if err != nil {
+			return nil, fmt.Errorf("unstructured job %s err: %s", itemJob.Name, err.Error())
+		}
+
+		// annotations and labels of manifest are extended by the ones of job and item job
+		objectMeta := rc.ObjectMeta(rc.JobName(itemJob.Name), itemJob.TemplateBaseInfo)
+
+		annotations := object.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		for k, v := range objectMeta.Annotations {
+			annotations[k] = v
+		}
+		annotations[appsv1alpha1.ItemJobAnnotation] = itemJob.Name
+
+		labels := object.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		for k, v := range objectMeta.Labels {
+			labels[k] = v
+		}
+
+		object.SetName(objectMeta.Name)
+		object.SetNamespace(objectMeta.Namespace)
+		object.SetAnnotations(annotations)
+		object.SetLabels(labels)
+
+		res = append(res, object)
+	}
+
+	return res, nil
+}
+
+func (d *unstructuredJobDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return createOwned(ctx, c, job, obj)
+}
+
+// Delete deletes object in background, so that the objects it created are deleted too.
+func (d *unstructuredJobDriver) Delete(ctx context.Context, c client.Client, obj client.Object) error {
+	return deleteObject(ctx, c, obj, client.PropagationPolicy(metav1.DeletePropagationBackground))
+}
+
+// unstructuredJobSpec returns the unstructured job in item the object is created for.
+func unstructuredJobSpec(item *appsv1alpha1.Item, obj client.Object) *appsv1alpha1.UnstructuredJobSpec {
+	if item == nil {
+		return nil
+	}
+
+	for _, itemJob := range item.ItemJobs.Jobs {
+		if itemJob.Name == obj.GetAnnotations()[appsv1alpha1.ItemJobAnnotation] && itemJob.Unstructured != nil {
+			return itemJob.Unstructured
+		}
+	}
+
+	return nil
+}
+
+func (d *unstructuredJobDriver) SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus) {
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	// rules are unknown before graph synced, the job syncs graph and the watch resyncs the object later
+	spec := unstructuredJobSpec(item, object)
+	if spec == nil {
+		return
+	}
+
+	if status.JobStatus == nil {
+		status.JobStatus = map[string]v1alpha1.JobState{}
+	}
+
+	if isDeleting(object) {
+		status.JobStatus[object.GetName()] = v1alpha1.JobState{
+			Phase:              v1alpha1.Terminated,
+			LastTransitionTime: *object.GetDeletionTimestamp(),
+		}
+		return
+	}
+
+	jobState := appsv1alpha1.CalUnstructuredJobState(spec, object)
+
+	oldState, ok := status.JobStatus[object.GetName()]
+	switch {
+	case ok && oldState.Phase == jobState.Phase:
+		jobState.LastTransitionTime = oldState.LastTransitionTime
+	case ok:
+		jobState.LastTransitionTime = metav1.Now()
+	default:
+		jobState.LastTransitionTime = object.GetCreationTimestamp()
+	}
+
+	status.JobStatus[object.GetName()] = jobState
+}
+
+func (d *unstructuredJobDriver) Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness {
+	object, ok := obj.(*unstructured.Unstructured)
+	if !ok || isDeleting(object) {
+		return ReadinessPending
+	}
+
+	spec := unstructuredJobSpec(item, object)
+	if spec == nil {
+		return ReadinessPending
+	}
+
+	switch appsv1alpha1.CalUnstructuredJobState(spec, object).Phase {
+	case v1alpha1.Completed:
+		return ReadinessReady
+	case v1alpha1.Failed:
+		return ReadinessFailed
+	}
+
+	return ReadinessPending
+}
@@ -0,0 +1,93 @@
+package driver
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func newTestUnstructuredItem() *appsv1alpha1.Item {
+	return &appsv1alpha1.Item{
+		Name: "item",
+		ItemJobs: appsv1alpha1.ItemJobResource{Jobs: []appsv1alpha1.ItemJobTemplate{{
+			TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "train", Labels: map[string]string{"from": "item"}},
+			Unstructured: &appsv1alpha1.UnstructuredJobSpec{
+				Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"kubeflow.org/v1","kind":"PyTorchJob",` +
+					`"metadata":{"name":"ignored","labels":{"from":"manifest","manifest":"a"}},"spec":{}}`)},
+				SuccessRule: appsv1alpha1.StatusRule{Condition: &appsv1alpha1.WaitCondition{Type: "Succeeded"}},
+				FailureRule: &appsv1alpha1.StatusRule{Condition: &appsv1alpha1.WaitCondition{Type: "Failed"}},
+			},
+		}}},
+	}
+}
+
+func TestUnstructuredJobDriverRender(t *testing.T) {
+	objs, err := (&unstructuredJobDriver{}).Render(newTestRenderContext(newTestUnstructuredItem()))
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 unstructured job, got %d", len(objs))
+	}
+
+	obj := objs[0].(*unstructured.Unstructured)
+	if obj.GetName() != "job-item-train" || obj.GetNamespace() != "ns" || obj.GetKind() != "PyTorchJob" {
+		t.Errorf("unexpected %s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	}
+	if obj.GetAnnotations()[appsv1alpha1.ItemJobAnnotation] != "train" || obj.GetAnnotations()[appsv1alpha1.CreateByJob] != "job" {
+		t.Errorf("creator annotations not set, got %v", obj.GetAnnotations())
+	}
+	if obj.GetLabels()["from"] != "item" || obj.GetLabels()["manifest"] != "a" {
+		t.Errorf("labels of manifest should be extended by item job, got %v", obj.GetLabels())
+	}
+}
+
+func TestUnstructuredJobDriverSyncStatus(t *testing.T) {
+	d := &unstructuredJobDriver{}
+	item := newTestUnstructuredItem()
+
+	tests := []struct {
+		name      string
+		item      *appsv1alpha1.Item
+		condition string
+		deleting  bool
+		phase     v1alpha1.JobPhase
+		readiness Readiness
+	}{
+		{name: "running", item: item, phase: v1alpha1.Running, readiness: ReadinessPending},
+		{name: "succeeded", item: item, condition: "Succeeded", phase: v1alpha1.Completed, readiness: ReadinessReady},
+		{name: "failed", item: item, condition: "Failed", phase: v1alpha1.Failed, readiness: ReadinessFailed},
+		{name: "deleting", item: item, deleting: true, phase: v1alpha1.Terminated, readiness: ReadinessPending},
+		{name: "item unknown", condition: "Succeeded", readiness: ReadinessPending},
+	}
+
+	for _, test := range tests {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("kubeflow.org/v1")
+		obj.SetKind("PyTorchJob")
+		obj.SetName("job-item-train")
+		obj.SetAnnotations(map[string]string{appsv1alpha1.ItemJobAnnotation: "train"})
+		if test.condition != "" {
+			_ = unstructured.SetNestedSlice(obj.Object, []interface{}{
+				map[string]interface{}{"type": test.condition, "status": "True"},
+			}, "status", "conditions")
+		}
+		if test.deleting {
+			now := metav1.Now()
+			obj.SetDeletionTimestamp(&now)
+		}
+
+		status := &appsv1alpha1.ItemStatus{}
+		d.SyncStatus(test.item, obj, status)
+
+		if state := status.JobStatus[obj.GetName()]; state.Phase != test.phase {
+			t.Errorf("%s: expected phase %s, got %s", test.name, test.phase, state.Phase)
+		}
+		if readiness := d.Readiness(test.item, obj); readiness != test.readiness {
+			t.Errorf("%s: expected readiness %s, got %s", test.name, test.readiness, readiness)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package driver
+
+import (
+	"context"
+	"fmt"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+// readValueSource reads the source of module into obj, false is returned if an optional source is not found.
+// A source in another namespace must allow the namespace of job.
+func readValueSource(rc *RenderContext, source appsv1alpha1.ModuleValueSource, obj client.Object) (bool, error) {
+	namespace := source.Namespace
+	if namespace == "" {
+		namespace = rc.Job.Namespace
+	}
+
+	if err := rc.Reader.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: source.Name}, obj); err != nil {
+		if errors.IsNotFound(err) && source.Optional {
+			return false, nil
+		}
+		return false, fmt.Errorf("read value source %s/%s err: %s", namespace, source.Name, err.Error())
+	}
+
+	if namespace != rc.Job.Namespace && !appsv1alpha1.IsNamespaceAllowed(obj, rc.Job.Namespace) {
+		return false, fmt.Errorf("value source %s/%s does not allow namespace %s", namespace, source.Name, rc.Job.Namespace)
+	}
+
+	return true, nil
+}
+
+// sourceKeys returns the keys to copy from source, which has keys. All keys are copied if source selects none,
+// and a key selected but not found is an error unless source is optional.
+func sourceKeys(source appsv1alpha1.ModuleValueSource, keys map[string]bool) ([]string, error) {
+	if len(source.Keys) == 0 {
+		res := make([]string, 0, len(keys))
+		for key := range keys {
+			res = append(res, key)
+		}
+		return res, nil
+	}
+
+	res := make([]string, 0, len(source.Keys))
+	for _, key := range source.Keys {
+		if !keys[key] {
+			if source.Optional {
+				continue
+			}
+			return nil, fmt.Errorf("key %s not found in value source %s", key, source.Name)
+		}
+		res = append(res, key)
+	}
+
+	return res, nil
+}
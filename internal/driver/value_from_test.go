@@ -0,0 +1,170 @@
+package driver
+
+import (
+	"context"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+)
+
+// testReader reads the configmaps and secrets it holds.
+type testReader struct {
+	objs []client.Object
+}
+
+func (r *testReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	for _, o := range r.objs {
+		if o.GetNamespace() != key.Namespace || o.GetName() != key.Name {
+			continue
+		}
+
+		switch src := o.(type) {
+		case *corev1.ConfigMap:
+			if dst, ok := obj.(*corev1.ConfigMap); ok {
+				*dst = *src.DeepCopy()
+				return nil
+			}
+		case *corev1.Secret:
+			if dst, ok := obj.(*corev1.Secret); ok {
+				*dst = *src.DeepCopy()
+				return nil
+			}
+		}
+	}
+
+	return errors.NewNotFound(schema.GroupResource{}, key.Name)
+}
+
+func (r *testReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return nil
+}
+
+func TestConfigMapValueFrom(t *testing.T) {
+	item := &appsv1alpha1.Item{
+		Name: "item",
+		ItemModules: appsv1alpha1.ItemModuleResource{
+			ConfigMaps: []appsv1alpha1.ConfigMapTemplate{
+				{
+					TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "conf"},
+					ConfigMap:        corev1.ConfigMap{Data: map[string]string{"b": "inline"}},
+					ValueFrom: []appsv1alpha1.ModuleValueSource{
+						{Name: "base"},
+						{Name: "shared", Namespace: "other", Keys: []string{"b", "c"}},
+						{Name: "missing", Optional: true},
+					},
+				},
+			},
+		},
+	}
+
+	rc := newTestRenderContext(item)
+	rc.Reader = &testReader{objs: []client.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: "ns"},
+			Data:       map[string]string{"a": "base", "c": "base"},
+			BinaryData: map[string][]byte{"bin": []byte("x")},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "other",
+				Annotations: map[string]string{appsv1alpha1.AllowedNamespacesAnnotation: "dev, ns"}},
+			Data: map[string]string{"b": "shared", "c": "shared", "d": "shared"},
+		},
+	}}
+
+	objs, err := (&configMapDriver{}).Render(rc)
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+
+	cm := objs[0].(*corev1.ConfigMap)
+	expected := map[string]string{"a": "base", "b": "inline", "c": "shared"}
+	if len(cm.Data) != len(expected) {
+		t.Errorf("expected data %v, got %v", expected, cm.Data)
+	}
+	for key, value := range expected {
+		if cm.Data[key] != value {
+			t.Errorf("expected %s to be %s, got %s", key, value, cm.Data[key])
+		}
+	}
+	if string(cm.BinaryData["bin"]) != "x" {
+		t.Errorf("binary data of source not copied, got %v", cm.BinaryData)
+	}
+	if item.ItemModules.ConfigMaps[0].ConfigMap.Data["a"] != "" {
+		t.Errorf("template of item changed by rendering")
+	}
+
+	// sources are not read without reader
+	rc.Reader = nil
+	if objs, _ := (&configMapDriver{}).Render(rc); len(objs[0].(*corev1.ConfigMap).Data) != 1 {
+		t.Errorf("sources should not be read without reader")
+	}
+}
+
+func TestSecretValueFrom(t *testing.T) {
+	secret := func(name, namespace string, annotations map[string]string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Annotations: annotations},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data:       map[string][]byte{"token": []byte(name)},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		source appsv1alpha1.ModuleValueSource
+		token  string
+		fail   bool
+	}{
+		{name: "same namespace", source: appsv1alpha1.ModuleValueSource{Name: "local"}, token: "local"},
+		{name: "allowed", source: appsv1alpha1.ModuleValueSource{Name: "all", Namespace: "other"}, token: "all"},
+		{name: "not allowed", source: appsv1alpha1.ModuleValueSource{Name: "private", Namespace: "other"}, fail: true},
+		{name: "not found", source: appsv1alpha1.ModuleValueSource{Name: "missing"}, fail: true},
+		{name: "key not found", source: appsv1alpha1.ModuleValueSource{Name: "local", Keys: []string{"password"}}, fail: true},
+		{name: "optional key not found", source: appsv1alpha1.ModuleValueSource{Name: "local", Keys: []string{"password"}, Optional: true}},
+	}
+
+	for _, test := range tests {
+		item := &appsv1alpha1.Item{
+			Name: "item",
+			ItemModules: appsv1alpha1.ItemModuleResource{
+				Secrets: []appsv1alpha1.SecretTemplate{
+					{
+						TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "creds"},
+						ValueFrom:        []appsv1alpha1.ModuleValueSource{test.source},
+					},
+				},
+			},
+		}
+
+		rc := newTestRenderContext(item)
+		rc.Reader = &testReader{objs: []client.Object{
+			secret("local", "ns", nil),
+			secret("all", "other", map[string]string{appsv1alpha1.AllowedNamespacesAnnotation: "*"}),
+			secret("private", "other", map[string]string{appsv1alpha1.AllowedNamespacesAnnotation: "dev"}),
+		}}
+
+		objs, err := (&secretDriver{}).Render(rc)
+		if test.fail {
+			if err == nil {
+				t.Errorf("%s: render should fail", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: render err: %s", test.name, err.Error())
+			continue
+		}
+
+		s := objs[0].(*corev1.Secret)
+		if string(s.Data["token"]) != test.token {
+			t.Errorf("%s: expected token %s, got %s", test.name, test.token, s.Data["token"])
+		}
+		if s.Type != corev1.SecretTypeDockerConfigJson {
+			t.Errorf("%s: secret should take the type of source, got %s", test.name, s.Type)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package driver
+
+import (
+	"context"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=batch.volcano.sh,resources=jobs,verbs=get;list;watch;create;delete
+
+// volcanoJobDriver runs the volcano jobs of item, the state of volcano job is used as is.
+type volcanoJobDriver struct{}
+
+func (d *volcanoJobDriver) Name() string {
+	return "volcano-job"
+}
+
+func (d *volcanoJobDriver) Object() client.Object {
+	return &v1alpha1.Job{}
+}
+
+func (d *volcanoJobDriver) IsModule() bool {
+	return false
+}
+
+func (d *volcanoJobDriver) Render(rc *RenderContext) ([]client.Object, error) {
+	var res []client.Object
+
+	for _, itemJob := range rc.Item.ItemJobs.Jobs {
+		if itemJob.VolcanoJobSpec == nil {
+			continue
+		}
+
+		spec := itemJob.VolcanoJobSpec.DeepCopy()
+		for i := range spec.Tasks {
+			if err := rc.DecoratePodTemplate(&spec.Tasks[i].Template); err != nil {
+				return nil, err
+			}
+		}
+
+		res = append(res, &v1alpha1.Job{
+			ObjectMeta: rc.ObjectMeta(rc.JobName(itemJob.Name), itemJob.TemplateBaseInfo),
+			Spec:       *spec,
+		})
+	}
+
+	return res, nil
+}
+
+func (d *volcanoJobDriver) Create(ctx context.Context, c client.Client, job *appsv1alpha1.Job, obj client.Object) error {
+	return createOwned(ctx, c, job, obj)
+}
+
+// Delete deletes job in background, so that its pods are deleted too.
+func (d *volcanoJobDriver) Delete(ctx context.Context, c client.Client, obj client.Object) error {
+	return deleteObject(ctx, c, obj, client.PropagationPolicy(metav1.DeletePropagationBackground))
+}
+
+func (d *volcanoJobDriver) SyncStatus(item *appsv1alpha1.Item, obj client.Object, status *appsv1alpha1.ItemStatus) {
+	job, ok := obj.(*v1alpha1.Job)
+	if !ok {
+		return
+	}
+
+	if status.JobStatus == nil {
+		status.JobStatus = map[string]v1alpha1.JobState{}
+	}
+
+	status.JobStatus[job.Name] = job.Status.State
+}
+
+func (d *volcanoJobDriver) Readiness(item *appsv1alpha1.Item, obj client.Object) Readiness {
+	job, ok := obj.(*v1alpha1.Job)
+	if !ok {
+		return ReadinessPending
+	}
+
+	switch job.Status.State.Phase {
+	case v1alpha1.Completed:
+		return ReadinessReady
+	case v1alpha1.Failed, v1alpha1.Aborted, v1alpha1.Terminated:
+		return ReadinessFailed
+	}
+
+	return ReadinessPending
+}
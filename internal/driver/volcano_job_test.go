@@ -0,0 +1,62 @@
+package driver
+
+import (
+	v1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func TestVolcanoJobDriverRender(t *testing.T) {
+	item := &appsv1alpha1.Item{
+		Name: "item",
+		ItemJobs: appsv1alpha1.ItemJobResource{
+			Jobs: []appsv1alpha1.ItemJobTemplate{
+				{TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "kube"}, KubeJobSpec: &v1.JobSpec{}},
+				{TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "train"}, VolcanoJobSpec: &v1alpha1.JobSpec{Queue: "q"}},
+			},
+		},
+	}
+
+	objs, err := (&volcanoJobDriver{}).Render(newTestRenderContext(item))
+	if err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+	if len(objs) != 1 {
+		t.Fatalf("expected 1 volcano job, got %d", len(objs))
+	}
+
+	job := objs[0].(*v1alpha1.Job)
+	if job.Name != "job-item-train" || job.Spec.Queue != "q" {
+		t.Errorf("unexpected job %s with queue %s", job.Name, job.Spec.Queue)
+	}
+}
+
+func TestVolcanoJobDriverSyncStatus(t *testing.T) {
+	d := &volcanoJobDriver{}
+
+	tests := []struct {
+		phase     v1alpha1.JobPhase
+		readiness Readiness
+	}{
+		{phase: v1alpha1.Running, readiness: ReadinessPending},
+		{phase: v1alpha1.Completed, readiness: ReadinessReady},
+		{phase: v1alpha1.Failed, readiness: ReadinessFailed},
+	}
+
+	for _, test := range tests {
+		job := &v1alpha1.Job{ObjectMeta: metav1.ObjectMeta{Name: "job-item-train"}}
+		job.Status.State.Phase = test.phase
+
+		status := &appsv1alpha1.ItemStatus{}
+		d.SyncStatus(nil, job, status)
+
+		if state := status.JobStatus[job.Name]; state.Phase != test.phase {
+			t.Errorf("expected phase %s, got %s", test.phase, state.Phase)
+		}
+		if readiness := d.Readiness(nil, job); readiness != test.readiness {
+			t.Errorf("%s: expected readiness %s, got %s", test.phase, test.readiness, readiness)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"strings"
+)
+
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=get
+
+// validateRoles checks the roles and role bindings of items for the user creating or updating job, like the RBAC
+// API checks escalate and bind. Operator is not granted escalate and bind, so that roles are created only if
+// the user can escalate roles or is granted all their rules, and cluster roles are bound only if the user can
+// bind them or is granted all their rules.
+func (r *JobWebHook) validateRoles(ctx context.Context, job *appsv1alpha1.Job) error {
+	items := appsv1alpha1.CalJobItems(job)
+
+	found := false
+	for _, item := range items {
+		if len(item.ItemModules.Roles) != 0 || len(item.ItemModules.RoleBindings) != 0 {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("get user of request err: %s", err.Error())
+	}
+	user := &req.UserInfo
+
+	escalate, err := r.isUserAllowed(ctx, user, &authorizationv1.ResourceAttributes{
+		Namespace: job.Namespace,
+		Verb:      "escalate",
+		Group:     rbacv1.GroupName,
+		Resource:  "roles",
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		for _, role := range item.ItemModules.Roles {
+			if escalate {
+				continue
+			}
+
+			if err := r.isUserGranted(ctx, user, job.Namespace, role.Rules); err != nil {
+				return fmt.Errorf("item %s role %s not allowed: %s", item.Name, role.Name, err.Error())
+			}
+		}
+
+		// roles of item are checked above, a user granted all rules of a role can bind it
+		for _, rb := range item.ItemModules.RoleBindings {
+			if rb.RoleRef.Kind != "ClusterRole" {
+				continue
+			}
+
+			allowed, err := r.isUserAllowed(ctx, user, &authorizationv1.ResourceAttributes{
+				Namespace: job.Namespace,
+				Verb:      "bind",
+				Group:     rbacv1.GroupName,
+				Resource:  "clusterroles",
+				Name:      rb.RoleRef.Name,
+			})
+			if err != nil {
+				return err
+			}
+			if allowed {
+				continue
+			}
+
+			clusterRole := &rbacv1.ClusterRole{}
+			if err := r.Client.Get(ctx, types.NamespacedName{Name: rb.RoleRef.Name}, clusterRole); err != nil {
+				return fmt.Errorf("get cluster role %s err: %s", rb.RoleRef.Name, err.Error())
+			}
+
+			if err := r.isUserGranted(ctx, user, job.Namespace, clusterRole.Rules); err != nil {
+				return fmt.Errorf("item %s role binding %s can not bind cluster role %s: %s",
+					item.Name, rb.Name, rb.RoleRef.Name, err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// isUserGranted returns an error if user is not granted any of rules in namespace.
+func (r *JobWebHook) isUserGranted(ctx context.Context, user *authenticationv1.UserInfo, namespace string, rules []rbacv1.PolicyRule) error {
+	for _, rule := range rules {
+		if len(rule.NonResourceURLs) != 0 {
+			return fmt.Errorf("non resource urls %v can not be granted in namespace", rule.NonResourceURLs)
+		}
+
+		for _, attributes := range calRuleAttributes(namespace, rule) {
+			allowed, err := r.isUserAllowed(ctx, user, attributes)
+			if err != nil {
+				return err
+			}
+			if !allowed {
+				return fmt.Errorf("user %s can not %s %s", user.Username, attributes.Verb, calAttributesResource(attributes))
+			}
+		}
+	}
+
+	return nil
+}
+
+// isUserAllowed reviews the access of user by api server.
+func (r *JobWebHook) isUserAllowed(ctx context.Context, user *authenticationv1.UserInfo, attributes *authorizationv1.ResourceAttributes) (bool, error) {
+	extra := map[string]authorizationv1.ExtraValue{}
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: attributes,
+			User:               user.Username,
+			Groups:             user.Groups,
+			UID:                user.UID,
+			Extra:              extra,
+		},
+	}
+
+	if err := r.Client.Create(ctx, review); err != nil {
+		return false, fmt.Errorf("review access of user %s err: %s", user.Username, err.Error())
+	}
+
+	return review.Status.Allowed, nil
+}
+
+// calRuleAttributes expands rule into the attributes of each group, resource, verb and name it grants.
+func calRuleAttributes(namespace string, rule rbacv1.PolicyRule) []*authorizationv1.ResourceAttributes {
+	var res []*authorizationv1.ResourceAttributes
+
+	names := rule.ResourceNames
+	if len(names) == 0 {
+		names = []string{""}
+	}
+
+	for _, group := range rule.APIGroups {
+		for _, resource := range rule.Resources {
+			resource, subresource, _ := strings.Cut(resource, "/")
+
+			for _, verb := range rule.Verbs {
+				for _, name := range names {
+					res = append(res, &authorizationv1.ResourceAttributes{
+						Namespace:   namespace,
+						Verb:        verb,
+						Group:       group,
+						Resource:    resource,
+						Subresource: subresource,
+						Name:        name,
+					})
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+func calAttributesResource(attributes *authorizationv1.ResourceAttributes) string {
+	res := attributes.Resource
+	if attributes.Subresource != "" {
+		res += "/" + attributes.Subresource
+	}
+	if attributes.Group != "" {
+		res += "." + attributes.Group
+	}
+	if attributes.Name != "" {
+		res += " " + attributes.Name
+	}
+
+	return res
+}
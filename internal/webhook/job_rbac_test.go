@@ -0,0 +1,232 @@
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"strings"
+	"testing"
+)
+
+// newTestRBACWebHook returns a webhook whose subject access reviews allow the attributes in granted,
+// keyed as "<verb> <resource> <name>". Deployments and Secrets are mapped by its client.
+func newTestRBACWebHook(granted map[string]bool, objs ...client.Object) (*JobWebHook, *[]string) {
+	var reviewed []string
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Secret"), meta.RESTScopeNamespace)
+
+	c := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithRESTMapper(mapper).WithObjects(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				review, ok := obj.(*authorizationv1.SubjectAccessReview)
+				if !ok {
+					return c.Create(ctx, obj, opts...)
+				}
+
+				attributes := review.Spec.ResourceAttributes
+				key := attributes.Verb + " " + calAttributesResource(attributes)
+				reviewed = append(reviewed, review.Spec.User+": "+key)
+				review.Status.Allowed = granted[key]
+				return nil
+			},
+		}).Build()
+
+	return &JobWebHook{Client: c, RoleBindingClusterRoles: []string{"view"}}, &reviewed
+}
+
+func newTestRBACContext(user string) context.Context {
+	return admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: user}},
+	})
+}
+
+func newTestRBACJob(modules appsv1alpha1.ItemModuleResource) *appsv1alpha1.Job {
+	return &appsv1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns"},
+		Spec:       appsv1alpha1.JobSpec{Items: []appsv1alpha1.Item{{Name: "a", ItemModules: modules}}},
+	}
+}
+
+func TestValidateRoles(t *testing.T) {
+	reader := appsv1alpha1.RoleTemplate{
+		TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "reader"},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"pods", "pods/log"}, Verbs: []string{"get"}},
+		},
+	}
+	view := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "view"},
+		Rules:      []rbacv1.PolicyRule{{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"list"}}},
+	}
+	bindView := appsv1alpha1.RoleBindingTemplate{
+		TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "view"},
+		RoleRef:          rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "view"},
+	}
+
+	tests := []struct {
+		name    string
+		modules appsv1alpha1.ItemModuleResource
+		granted map[string]bool
+		valid   bool
+	}{
+		{
+			name:  "no roles are not reviewed",
+			valid: true,
+		},
+		{
+			name:    "user granted all rules of role",
+			modules: appsv1alpha1.ItemModuleResource{Roles: []appsv1alpha1.RoleTemplate{reader}},
+			granted: map[string]bool{"get pods": true, "get pods/log": true},
+			valid:   true,
+		},
+		{
+			name:    "user granted part of rules of role",
+			modules: appsv1alpha1.ItemModuleResource{Roles: []appsv1alpha1.RoleTemplate{reader}},
+			granted: map[string]bool{"get pods": true},
+		},
+		{
+			name:    "user can escalate roles",
+			modules: appsv1alpha1.ItemModuleResource{Roles: []appsv1alpha1.RoleTemplate{reader}},
+			granted: map[string]bool{"escalate roles.rbac.authorization.k8s.io": true},
+			valid:   true,
+		},
+		{
+			name:    "user can bind cluster role",
+			modules: appsv1alpha1.ItemModuleResource{RoleBindings: []appsv1alpha1.RoleBindingTemplate{bindView}},
+			granted: map[string]bool{"bind clusterroles.rbac.authorization.k8s.io view": true},
+			valid:   true,
+		},
+		{
+			name:    "user granted all rules of cluster role",
+			modules: appsv1alpha1.ItemModuleResource{RoleBindings: []appsv1alpha1.RoleBindingTemplate{bindView}},
+			granted: map[string]bool{"list deployments.apps": true},
+			valid:   true,
+		},
+		{
+			name:    "user can neither bind cluster role nor is granted its rules",
+			modules: appsv1alpha1.ItemModuleResource{RoleBindings: []appsv1alpha1.RoleBindingTemplate{bindView}},
+		},
+	}
+
+	for _, test := range tests {
+		hook, reviewed := newTestRBACWebHook(test.granted, view)
+
+		err := hook.validateRoles(newTestRBACContext("alice"), newTestRBACJob(test.modules))
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %t, got err %v, reviewed %v", test.name, test.valid, err, *reviewed)
+		}
+
+		for _, review := range *reviewed {
+			if review[:len("alice: ")] != "alice: " {
+				t.Errorf("%s: access should be reviewed for the user of request, got %s", test.name, review)
+			}
+		}
+	}
+}
+
+func TestValidatePermissionsClusterRoleNotAllowed(t *testing.T) {
+	hook, reviewed := newTestRBACWebHook(map[string]bool{"bind clusterroles.rbac.authorization.k8s.io cluster-admin": true})
+
+	job := newTestRBACJob(appsv1alpha1.ItemModuleResource{
+		RoleBindings: []appsv1alpha1.RoleBindingTemplate{{
+			TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "admin"},
+			RoleRef:          rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "cluster-admin"},
+		}},
+	})
+
+	// cluster roles not allowed are rejected even if the user can bind them
+	if _, err := hook.validatePermissions(newTestRBACContext("alice"), job); err == nil {
+		t.Errorf("role binding referring to cluster role not allowed should be rejected")
+	}
+	if len(*reviewed) != 0 {
+		t.Errorf("access should not be reviewed once rejected, got %v", *reviewed)
+	}
+}
+
+func TestCalRuleAttributes(t *testing.T) {
+	rule := rbacv1.PolicyRule{
+		APIGroups:     []string{"", "apps"},
+		Resources:     []string{"pods/log"},
+		Verbs:         []string{"get", "list"},
+		ResourceNames: []string{"a"},
+	}
+
+	attributes := calRuleAttributes("ns", rule)
+	if len(attributes) != 4 {
+		t.Fatalf("expected 4 attributes, got %d", len(attributes))
+	}
+
+	first := attributes[0]
+	if first.Namespace != "ns" || first.Group != "" || first.Resource != "pods" || first.Subresource != "log" ||
+		first.Verb != "get" || first.Name != "a" {
+		t.Errorf("unexpected attributes %v", first)
+	}
+}
+
+func TestValidatePermissionsSubJob(t *testing.T) {
+	reader := newTestRBACJob(appsv1alpha1.ItemModuleResource{Roles: []appsv1alpha1.RoleTemplate{{
+		TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "reader"},
+		Rules:            []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}},
+	}}})
+
+	// wraps spec into the sub job of a parent job
+	wrap := func(spec appsv1alpha1.JobSpec) appsv1alpha1.JobSpec {
+		raw, err := json.Marshal(spec)
+		if err != nil {
+			t.Fatalf("marshal sub job spec err: %s", err.Error())
+		}
+
+		return appsv1alpha1.JobSpec{Items: []appsv1alpha1.Item{{
+			Name: "s",
+			SubJob: &appsv1alpha1.SubJobTemplate{
+				TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "child"},
+				Spec:             runtime.RawExtension{Raw: raw},
+			},
+		}}}
+	}
+
+	tests := []struct {
+		name    string
+		spec    appsv1alpha1.JobSpec
+		granted map[string]bool
+		valid   bool
+	}{
+		{name: "sub job role granted", spec: wrap(reader.Spec), granted: map[string]bool{"get secrets": true}, valid: true},
+		{name: "sub job role not granted", spec: wrap(reader.Spec)},
+		{name: "nested sub job role not granted", spec: wrap(wrap(reader.Spec))},
+	}
+
+	for _, test := range tests {
+		hook, reviewed := newTestRBACWebHook(test.granted)
+		job := &appsv1alpha1.Job{ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns"}, Spec: test.spec}
+
+		_, err := hook.validatePermissions(newTestRBACContext("alice"), job)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %t, got err %v", test.name, test.valid, err)
+		}
+		if len(*reviewed) == 0 {
+			t.Errorf("%s: roles of sub job should be reviewed", test.name)
+		}
+		for _, review := range *reviewed {
+			if !strings.HasPrefix(review, "alice: ") {
+				t.Errorf("%s: access should be reviewed for the user of request, got %s", test.name, review)
+			}
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package v1alpha1
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+// JobTemplateWebHook validates JobTemplates and ClusterJobTemplates.
+type JobTemplateWebHook struct {
+}
+
+func NewJobTemplateWebHook() (*JobTemplateWebHook, error) {
+	return &JobTemplateWebHook{}, nil
+}
+
+// SetupWebhookWithManager will setup the manager to manage the webhooks
+func (r *JobTemplateWebHook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		For(&appsv1alpha1.JobTemplate{}).
+		Complete(); err != nil {
+		return err
+	}
+
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&appsv1alpha1.ClusterJobTemplate{}).
+		Complete()
+}
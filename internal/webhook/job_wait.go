@@ -0,0 +1,62 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+// validateWaits checks that the user creating or updating job can get the objects its wait items wait for.
+// Operator reads them with its own permissions, like Secrets it creates for items, so that the rules of
+// wait items could expose what the user can not read otherwise.
+func (r *JobWebHook) validateWaits(ctx context.Context, job *appsv1alpha1.Job) error {
+	var waits []*appsv1alpha1.ResourceWait
+	for _, item := range appsv1alpha1.CalJobItems(job) {
+		if item.Wait != nil && item.Wait.Resource != nil {
+			waits = append(waits, item.Wait.Resource)
+		}
+	}
+	if len(waits) == 0 {
+		return nil
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("get user of request err: %s", err.Error())
+	}
+	user := &req.UserInfo
+
+	for _, resource := range waits {
+		namespace := resource.Namespace
+		if namespace == "" {
+			namespace = job.Namespace
+		}
+
+		gvk := schema.FromAPIVersionAndKind(resource.APIVersion, resource.Kind)
+		mapping, err := r.Client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return fmt.Errorf("get mapping of %s err: %s", gvk.String(), err.Error())
+		}
+
+		attributes := &authorizationv1.ResourceAttributes{
+			Namespace: namespace,
+			Verb:      "get",
+			Group:     mapping.Resource.Group,
+			Resource:  mapping.Resource.Resource,
+			Name:      resource.Name,
+		}
+		allowed, err := r.isUserAllowed(ctx, user, attributes)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return fmt.Errorf("user %s can not get %s in namespace %s to wait for it",
+				user.Username, calAttributesResource(attributes), namespace)
+		}
+	}
+
+	return nil
+}
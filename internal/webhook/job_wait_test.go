@@ -0,0 +1,62 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"strings"
+	"testing"
+)
+
+func TestValidateWaits(t *testing.T) {
+	expression := appsv1alpha1.StatusRule{Expression: "true"}
+
+	tests := []struct {
+		name     string
+		wait     *appsv1alpha1.WaitTemplate
+		granted  map[string]bool
+		valid    bool
+		reviewed string
+	}{
+		{
+			name:  "http wait is not reviewed",
+			wait:  &appsv1alpha1.WaitTemplate{HTTP: &appsv1alpha1.HTTPWait{URL: "http://db.ns.svc"}},
+			valid: true,
+		},
+		{
+			name: "user can get object",
+			wait: &appsv1alpha1.WaitTemplate{Resource: &appsv1alpha1.ResourceWait{
+				APIVersion: "apps/v1", Kind: "Deployment", Name: "db", StatusRule: expression}},
+			granted:  map[string]bool{"get deployments.apps db": true},
+			valid:    true,
+			reviewed: "alice: get deployments.apps db",
+		},
+		{
+			name: "user can not get secret",
+			wait: &appsv1alpha1.WaitTemplate{Resource: &appsv1alpha1.ResourceWait{
+				APIVersion: "v1", Kind: "Secret", Name: "token", StatusRule: expression}},
+			granted:  map[string]bool{"get deployments.apps db": true},
+			reviewed: "alice: get secrets token",
+		},
+		{
+			name: "kind not served",
+			wait: &appsv1alpha1.WaitTemplate{Resource: &appsv1alpha1.ResourceWait{
+				APIVersion: "example.com/v1", Kind: "Database", Name: "db", StatusRule: expression}},
+		},
+	}
+
+	for _, test := range tests {
+		hook, reviewed := newTestRBACWebHook(test.granted)
+		job := &appsv1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns"},
+			Spec:       appsv1alpha1.JobSpec{Items: []appsv1alpha1.Item{{Name: "a", Wait: test.wait}}},
+		}
+
+		err := hook.validateWaits(newTestRBACContext("alice"), job)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %t, got err %v", test.name, test.valid, err)
+		}
+		if got := strings.Join(*reviewed, ","); got != test.reviewed {
+			t.Errorf("%s: expected reviewed %q, got %q", test.name, test.reviewed, got)
+		}
+	}
+}
@@ -17,20 +17,222 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
+	"fmt"
+	admissionv1 "k8s.io/api/admission/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
 )
 
 type JobWebHook struct {
+	client.Client
+
+	// UnstructuredJobKinds are the kinds unstructured jobs are allowed to create, none if empty.
+	UnstructuredJobKinds []schema.GroupVersionKind
+
+	// RoleBindingClusterRoles are the cluster roles role bindings of items are allowed to refer to, none if empty.
+	RoleBindingClusterRoles []string
 }
 
-func NewJobWebHook() (*JobWebHook, error) {
-	return &JobWebHook{}, nil
+func NewJobWebHook(client client.Client) (*JobWebHook, error) {
+	return &JobWebHook{
+		Client: client,
+	}, nil
 }
 
+//+kubebuilder:rbac:groups=apps.songf.sh,resources=jobtemplates,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps.songf.sh,resources=clusterjobtemplates,verbs=get;list;watch
+
 // SetupWebhookWithManager will setup the manager to manage the webhooks
 func (r *JobWebHook) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&appsv1alpha1.Job{}).
+		WithDefaulter(r).
+		WithValidator(r).
 		Complete()
 }
+
+var _ admission.CustomDefaulter = &JobWebHook{}
+
+// Default renders the template referenced by job, and then defaults job.
+func (r *JobWebHook) Default(ctx context.Context, obj runtime.Object) error {
+	job, ok := obj.(*appsv1alpha1.Job)
+	if !ok {
+		return fmt.Errorf("expected a Job but got a %T", obj)
+	}
+
+	// the annotation is only set by rendering, otherwise a Job created with it would skip its template
+	if req, err := admission.RequestFromContext(ctx); err == nil && req.Operation == admissionv1.Create {
+		delete(job.Annotations, appsv1alpha1.TemplateGenerationAnnotation)
+	}
+
+	if err := r.renderJobTemplate(ctx, job); err != nil {
+		klog.Errorf(err.Error())
+		return err
+	}
+
+	job.Default()
+
+	return nil
+}
+
+// renderJobTemplate renders the referenced template into job once, a rendered job keeps its spec while
+// the template changes.
+func (r *JobWebHook) renderJobTemplate(ctx context.Context, job *appsv1alpha1.Job) error {
+	if appsv1alpha1.IsJobTemplateRendered(job) {
+		return nil
+	}
+
+	definition, generation, err := r.getJobTemplate(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	return appsv1alpha1.RenderJobTemplate(job, definition, generation)
+}
+
+func (r *JobWebHook) getJobTemplate(ctx context.Context, job *appsv1alpha1.Job) (*appsv1alpha1.JobTemplateDefinition, int64, error) {
+	ref := job.Spec.TemplateRef
+
+	switch ref.Kind {
+	case "", appsv1alpha1.JobTemplateKind:
+		template := &appsv1alpha1.JobTemplate{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: job.Namespace, Name: ref.Name}, template); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, 0, fmt.Errorf("job template %s/%s not found", job.Namespace, ref.Name)
+			}
+			return nil, 0, fmt.Errorf("get job template %s/%s err: %s", job.Namespace, ref.Name, err.Error())
+		}
+
+		return &template.Spec, template.Generation, nil
+	case appsv1alpha1.ClusterJobTemplateKind:
+		template := &appsv1alpha1.ClusterJobTemplate{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: ref.Name}, template); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, 0, fmt.Errorf("cluster job template %s not found", ref.Name)
+			}
+			return nil, 0, fmt.Errorf("get cluster job template %s err: %s", ref.Name, err.Error())
+		}
+
+		return &template.Spec, template.Generation, nil
+	default:
+		return nil, 0, fmt.Errorf("template kind %s not supported", ref.Kind)
+	}
+}
+
+var _ admission.CustomValidator = &JobWebHook{}
+
+func (r *JobWebHook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	job, ok := obj.(*appsv1alpha1.Job)
+	if !ok {
+		return nil, fmt.Errorf("expected a Job but got a %T", obj)
+	}
+
+	warnings, err := job.ValidateCreate()
+	if err != nil {
+		return warnings, err
+	}
+
+	return r.validatePermissions(ctx, job)
+}
+
+func (r *JobWebHook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	job, ok := newObj.(*appsv1alpha1.Job)
+	if !ok {
+		return nil, fmt.Errorf("expected a Job but got a %T", newObj)
+	}
+
+	oldJob, ok := oldObj.(*appsv1alpha1.Job)
+	if !ok {
+		return nil, fmt.Errorf("expected a Job but got a %T", oldObj)
+	}
+
+	warnings, err := job.ValidateUpdate(oldJob)
+	if err != nil {
+		return warnings, err
+	}
+
+	// updates not changing spec, like the ones of operator, are not checked again
+	if apiequality.Semantic.DeepEqual(job.Spec, oldJob.Spec) {
+		return warnings, nil
+	}
+
+	return r.validatePermissions(ctx, job)
+}
+
+// validatePermissions checks what job creates or reads on behalf of its creator, which are known by webhook only:
+// the kinds of unstructured jobs, the roles and role bindings of items, and the objects of wait items.
+// Sub jobs are created by operator, so they are checked here with the creator of job too.
+func (r *JobWebHook) validatePermissions(ctx context.Context, job *appsv1alpha1.Job) (admission.Warnings, error) {
+	if flag, msg := appsv1alpha1.IsJobUnstructuredJobAllowed(job, r.UnstructuredJobKinds, r.Client.RESTMapper()); !flag {
+		return admission.Warnings{msg}, fmt.Errorf(msg)
+	}
+
+	if flag, msg := appsv1alpha1.IsJobRoleBindingAllowed(job, r.RoleBindingClusterRoles); !flag {
+		return admission.Warnings{msg}, fmt.Errorf(msg)
+	}
+
+	if err := r.validateRoles(ctx, job); err != nil {
+		return admission.Warnings{err.Error()}, err
+	}
+
+	if err := r.validateWaits(ctx, job); err != nil {
+		return admission.Warnings{err.Error()}, err
+	}
+
+	return r.validateSubJobPermissions(ctx, job)
+}
+
+// validateSubJobPermissions checks the sub jobs of items, rendered from their templates like operator creates them.
+func (r *JobWebHook) validateSubJobPermissions(ctx context.Context, job *appsv1alpha1.Job) (admission.Warnings, error) {
+	items := appsv1alpha1.CalJobItems(job)
+	for i := range items {
+		item := &items[i]
+		if item.SubJob == nil {
+			continue
+		}
+
+		spec, err := appsv1alpha1.GetSubJobSpec(item)
+		if err != nil {
+			return admission.Warnings{err.Error()}, err
+		}
+
+		subJob := &appsv1alpha1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      appsv1alpha1.CalJobItemSubName(job.Name, item.Name, item.SubJob.Name),
+				Namespace: job.Namespace,
+			},
+			Spec: *spec,
+		}
+
+		if err := r.renderJobTemplate(ctx, subJob); err != nil {
+			err = fmt.Errorf("item %s sub job %s: %s", item.Name, item.SubJob.Name, err.Error())
+			return admission.Warnings{err.Error()}, err
+		}
+
+		if _, err := r.validatePermissions(ctx, subJob); err != nil {
+			err = fmt.Errorf("item %s sub job %s: %s", item.Name, item.SubJob.Name, err.Error())
+			return admission.Warnings{err.Error()}, err
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *JobWebHook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	job, ok := obj.(*appsv1alpha1.Job)
+	if !ok {
+		return nil, fmt.Errorf("expected a Job but got a %T", obj)
+	}
+
+	return job.ValidateDelete()
+}
@@ -0,0 +1,47 @@
+package v1alpha1
+
+import (
+	"context"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+)
+
+func TestDefaultRendersTemplateOnCreate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := appsv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme err: %s", err.Error())
+	}
+
+	template := &appsv1alpha1.JobTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "t", Namespace: "ns", Generation: 2},
+		Spec: appsv1alpha1.JobTemplateDefinition{Template: appsv1alpha1.JobTemplateSpec{
+			Spec: appsv1alpha1.JobSpec{Items: []appsv1alpha1.Item{{Name: "a"}}},
+		}},
+	}
+	hook := &JobWebHook{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(template).Build()}
+
+	// a Job created with the annotation set by itself is still rendered
+	job := &appsv1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "job",
+			Namespace:   "ns",
+			Annotations: map[string]string{appsv1alpha1.TemplateGenerationAnnotation: "1"},
+		},
+		Spec: appsv1alpha1.JobSpec{TemplateRef: &appsv1alpha1.TemplateReference{Name: "t"}},
+	}
+	ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Create},
+	})
+
+	if err := hook.Default(ctx, job); err != nil {
+		t.Fatalf("default err: %s", err.Error())
+	}
+	if len(job.Spec.Items) != 1 || job.Annotations[appsv1alpha1.TemplateGenerationAnnotation] != "2" {
+		t.Errorf("template not rendered, got items %v annotations %v", job.Spec.Items, job.Annotations)
+	}
+}
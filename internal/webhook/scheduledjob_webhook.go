@@ -0,0 +1,99 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+)
+
+// ScheduledJobWebHook validates the schedules and job templates of ScheduledJobs. Jobs of ScheduledJob are
+// created by operator, so what they create on behalf of the author of ScheduledJob is checked here.
+type ScheduledJobWebHook struct {
+	JobWebHook *JobWebHook
+}
+
+func NewScheduledJobWebHook(jobWebHook *JobWebHook) (*ScheduledJobWebHook, error) {
+	return &ScheduledJobWebHook{
+		JobWebHook: jobWebHook,
+	}, nil
+}
+
+// SetupWebhookWithManager will setup the manager to manage the webhooks
+func (r *ScheduledJobWebHook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&appsv1alpha1.ScheduledJob{}).
+		WithValidator(r).
+		Complete()
+}
+
+var _ admission.CustomValidator = &ScheduledJobWebHook{}
+
+func (r *ScheduledJobWebHook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	scheduledJob, ok := obj.(*appsv1alpha1.ScheduledJob)
+	if !ok {
+		return nil, fmt.Errorf("expected a ScheduledJob but got a %T", obj)
+	}
+
+	warnings, err := scheduledJob.ValidateCreate()
+	if err != nil {
+		return warnings, err
+	}
+
+	return r.validatePermissions(ctx, scheduledJob)
+}
+
+func (r *ScheduledJobWebHook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	scheduledJob, ok := newObj.(*appsv1alpha1.ScheduledJob)
+	if !ok {
+		return nil, fmt.Errorf("expected a ScheduledJob but got a %T", newObj)
+	}
+
+	oldScheduledJob, ok := oldObj.(*appsv1alpha1.ScheduledJob)
+	if !ok {
+		return nil, fmt.Errorf("expected a ScheduledJob but got a %T", oldObj)
+	}
+
+	warnings, err := scheduledJob.ValidateUpdate(oldScheduledJob)
+	if err != nil {
+		return warnings, err
+	}
+
+	// updates not changing job template, like suspending it, are not checked again
+	if apiequality.Semantic.DeepEqual(scheduledJob.Spec.JobTemplate, oldScheduledJob.Spec.JobTemplate) {
+		return warnings, nil
+	}
+
+	return r.validatePermissions(ctx, scheduledJob)
+}
+
+// validatePermissions checks the job template of scheduledJob like the Job webhook checks Jobs, for the user
+// creating or updating scheduledJob. The referenced template is rendered as it would be for the Jobs.
+func (r *ScheduledJobWebHook) validatePermissions(ctx context.Context, scheduledJob *appsv1alpha1.ScheduledJob) (admission.Warnings, error) {
+	job := &appsv1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      scheduledJob.Name,
+			Namespace: scheduledJob.Namespace,
+		},
+		Spec: *scheduledJob.Spec.JobTemplate.Spec.DeepCopy(),
+	}
+
+	if err := r.JobWebHook.renderJobTemplate(ctx, job); err != nil {
+		return admission.Warnings{err.Error()}, err
+	}
+
+	return r.JobWebHook.validatePermissions(ctx, job)
+}
+
+func (r *ScheduledJobWebHook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	scheduledJob, ok := obj.(*appsv1alpha1.ScheduledJob)
+	if !ok {
+		return nil, fmt.Errorf("expected a ScheduledJob but got a %T", obj)
+	}
+
+	return scheduledJob.ValidateDelete()
+}
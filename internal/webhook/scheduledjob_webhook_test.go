@@ -0,0 +1,70 @@
+package v1alpha1
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsv1alpha1 "songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"strings"
+	"testing"
+)
+
+func newTestRBACScheduledJob(rules []rbacv1.PolicyRule) *appsv1alpha1.ScheduledJob {
+	job := newTestRBACJob(appsv1alpha1.ItemModuleResource{Roles: []appsv1alpha1.RoleTemplate{{
+		TemplateBaseInfo: appsv1alpha1.TemplateBaseInfo{Name: "reader"},
+		Rules:            rules,
+	}}})
+
+	return &appsv1alpha1.ScheduledJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly", Namespace: "ns"},
+		Spec: appsv1alpha1.ScheduledJobSpec{
+			Schedule:    "0 0 * * *",
+			JobTemplate: appsv1alpha1.JobTemplateSpec{Spec: job.Spec},
+		},
+	}
+}
+
+func TestScheduledJobValidatePermissions(t *testing.T) {
+	pods := []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}}
+	secrets := []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}}
+	granted := map[string]bool{"get pods": true}
+
+	tests := []struct {
+		name     string
+		old      *appsv1alpha1.ScheduledJob
+		new      *appsv1alpha1.ScheduledJob
+		valid    bool
+		reviewed bool
+	}{
+		{name: "create granted", new: newTestRBACScheduledJob(pods), valid: true, reviewed: true},
+		{name: "create not granted", new: newTestRBACScheduledJob(secrets), reviewed: true},
+		{name: "update not changing job template", old: newTestRBACScheduledJob(secrets),
+			new: newTestRBACScheduledJob(secrets), valid: true},
+		{name: "update changing job template", old: newTestRBACScheduledJob(pods),
+			new: newTestRBACScheduledJob(secrets), reviewed: true},
+	}
+
+	for _, test := range tests {
+		jobHook, reviewed := newTestRBACWebHook(granted)
+		hook := &ScheduledJobWebHook{JobWebHook: jobHook}
+		ctx := newTestRBACContext("alice")
+
+		var err error
+		if test.old == nil {
+			_, err = hook.ValidateCreate(ctx, test.new)
+		} else {
+			_, err = hook.ValidateUpdate(ctx, test.old, test.new)
+		}
+
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %t, got err %v", test.name, test.valid, err)
+		}
+		if (len(*reviewed) != 0) != test.reviewed {
+			t.Errorf("%s: expected reviewed %t, got %v", test.name, test.reviewed, *reviewed)
+		}
+		for _, review := range *reviewed {
+			if !strings.HasPrefix(review, "alice: ") {
+				t.Errorf("%s: access should be reviewed for the author of scheduled job, got %s", test.name, review)
+			}
+		}
+	}
+}
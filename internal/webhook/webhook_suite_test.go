@@ -114,7 +114,7 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).NotTo(HaveOccurred())
 
-	err = (&JobWebHook{}).SetupWebhookWithManager(mgr)
+	err = (&JobWebHook{Client: mgr.GetClient()}).SetupWebhookWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 
 	//+kubebuilder:scaffold:webhook
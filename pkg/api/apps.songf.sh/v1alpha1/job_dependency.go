@@ -0,0 +1,88 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// CalJobDependencyKey returns the namespaced name of the Job depended by job.
+func CalJobDependencyKey(job *Job, dependency *JobDependency) types.NamespacedName {
+	namespace := dependency.Namespace
+	if namespace == "" {
+		namespace = job.Namespace
+	}
+
+	return types.NamespacedName{Namespace: namespace, Name: dependency.Name}
+}
+
+// GetJobDependencies returns the Jobs depended by the items of job, without repeat.
+func GetJobDependencies(job *Job) []types.NamespacedName {
+	var res []types.NamespacedName
+	found := map[types.NamespacedName]bool{}
+
+	for _, item := range CalJobItems(job) {
+		for i := range item.DependsOnJobs {
+			key := CalJobDependencyKey(job, &item.DependsOnJobs[i])
+			if found[key] {
+				continue
+			}
+
+			found[key] = true
+			res = append(res, key)
+		}
+	}
+
+	return res
+}
+
+// IsJobDependencyValid checks the Jobs depended by items.
+func IsJobDependencyValid(job *Job) (bool, string) {
+	for _, item := range CalJobItems(job) {
+		for i := range item.DependsOnJobs {
+			dependency := &item.DependsOnJobs[i]
+
+			if dependency.Name == "" {
+				return false, fmt.Sprintf("item %s depended job name can not be nil", item.Name)
+			}
+
+			switch dependency.Phase {
+			case "", JobDependencyCompleted, JobDependencyFinished:
+			default:
+				return false, fmt.Sprintf("item %s depended job %s phase %s not supported", item.Name, dependency.Name, dependency.Phase)
+			}
+
+			if key := CalJobDependencyKey(job, dependency); key.Namespace == job.Namespace && key.Name == job.Name {
+				return false, fmt.Sprintf("item %s can not depend on its own job", item.Name)
+			}
+		}
+	}
+
+	return true, ""
+}
+
+// CheckJobDependency checks whether the depended Job reached the phase of dependency, nil means the Job not found.
+// Failed is true if the Job can never reach the phase.
+func CheckJobDependency(dependency *JobDependency, dependedJob *Job) (satisfied, failed bool) {
+	if dependedJob == nil {
+		return false, false
+	}
+
+	phase := dependedJob.Status.State.Phase
+
+	switch dependency.Phase {
+	case JobDependencyFinished:
+		switch phase {
+		case Completed, Failed, Terminated:
+			return true, false
+		}
+	default:
+		switch phase {
+		case Completed:
+			return true, false
+		case Failed, Terminating, Terminated:
+			return false, true
+		}
+	}
+
+	return false, false
+}
@@ -0,0 +1,84 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func TestCheckJobDependency(t *testing.T) {
+	tests := []struct {
+		dependency JobDependencyPhase
+		phase      JobPhase
+		missing    bool
+		satisfied  bool
+		failed     bool
+	}{
+		{dependency: "", missing: true},
+		{dependency: "", phase: Scheduled},
+		{dependency: "", phase: Completing},
+		{dependency: "", phase: Completed, satisfied: true},
+		{dependency: "", phase: Failed, failed: true},
+		{dependency: "", phase: Terminating, failed: true},
+		{dependency: JobDependencyCompleted, phase: Completed, satisfied: true},
+		{dependency: JobDependencyCompleted, phase: Terminated, failed: true},
+		{dependency: JobDependencyFinished, missing: true},
+		{dependency: JobDependencyFinished, phase: Queued},
+		{dependency: JobDependencyFinished, phase: Terminating},
+		{dependency: JobDependencyFinished, phase: Completed, satisfied: true},
+		{dependency: JobDependencyFinished, phase: Failed, satisfied: true},
+		{dependency: JobDependencyFinished, phase: Terminated, satisfied: true},
+	}
+
+	for _, test := range tests {
+		var dependedJob *Job
+		if !test.missing {
+			dependedJob = &Job{Status: JobStatus{State: JobState{Phase: test.phase}}}
+		}
+
+		satisfied, failed := CheckJobDependency(&JobDependency{Name: "a", Phase: test.dependency}, dependedJob)
+		if satisfied != test.satisfied || failed != test.failed {
+			t.Errorf("dependency %q on job %q (missing %t): expected %t %t, got %t %t", test.dependency, test.phase,
+				test.missing, test.satisfied, test.failed, satisfied, failed)
+		}
+	}
+}
+
+func TestGetJobDependencies(t *testing.T) {
+	job := &Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns"},
+		Spec: JobSpec{Items: []Item{
+			{Name: "a", DependsOnJobs: []JobDependency{{Name: "x"}, {Name: "y", Namespace: "other"}}},
+			{Name: "b", RunAfter: []string{"a"}, DependsOnJobs: []JobDependency{{Name: "x", Namespace: "ns", Phase: JobDependencyFinished}}},
+		}},
+	}
+
+	dependencies := GetJobDependencies(job)
+	if len(dependencies) != 2 || dependencies[0].String() != "ns/x" || dependencies[1].String() != "other/y" {
+		t.Errorf("expected ns/x and other/y, got %v", dependencies)
+	}
+}
+
+func TestIsJobDependencyValid(t *testing.T) {
+	tests := []struct {
+		name       string
+		dependency JobDependency
+		valid      bool
+	}{
+		{name: "valid", dependency: JobDependency{Name: "x", Phase: JobDependencyFinished}, valid: true},
+		{name: "job in other namespace named same", dependency: JobDependency{Name: "job", Namespace: "other"}, valid: true},
+		{name: "name nil", dependency: JobDependency{}},
+		{name: "phase not supported", dependency: JobDependency{Name: "x", Phase: "Running"}},
+		{name: "depends on itself", dependency: JobDependency{Name: "job"}},
+	}
+
+	for _, test := range tests {
+		job := &Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "ns"},
+			Spec:       JobSpec{Items: []Item{{Name: "a", DependsOnJobs: []JobDependency{test.dependency}}}},
+		}
+
+		if valid, message := IsJobDependencyValid(job); valid != test.valid {
+			t.Errorf("%s: expected valid %t, got %t %s", test.name, test.valid, valid, message)
+		}
+	}
+}
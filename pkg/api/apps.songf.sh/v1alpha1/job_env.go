@@ -0,0 +1,100 @@
+package v1alpha1
+
+import (
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// EnvJobName is the name of Job.
+	EnvJobName = "SONGF_JOB_NAME"
+	// EnvItemName is the name of Item.
+	EnvItemName = "SONGF_ITEM_NAME"
+	// EnvRunID is the uid of Job, every run of a ScheduledJob is a Job with its own uid.
+	EnvRunID = "SONGF_RUN_ID"
+	// EnvAttempt is the num of retries of Item, 0 for the first attempt.
+	EnvAttempt = "SONGF_ATTEMPT"
+)
+
+// calEnvName converts the parts to an env name, like "SONGF_SERVICE_DB_NAME" for "service", "db" and "name".
+func calEnvName(parts ...string) string {
+	name := strings.ToUpper("songf_" + strings.Join(parts, "_"))
+
+	return strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// CalItemEnv returns the env injected into the pods of item named itemName:
+//   - SONGF_JOB_NAME, SONGF_ITEM_NAME, SONGF_RUN_ID and SONGF_ATTEMPT
+//   - SONGF_SERVICE_<name>_NAME, SONGF_CONFIGMAP_<name>_NAME and SONGF_SECRET_<name>_NAME, the generated names of
+//     services, configmaps and secrets of item
+//   - SONGF_ITEM_<item>_SERVICE_<name>_HOST, the dns names of services of items item runs after directly or indirectly
+//
+// Modules are read from the spec of job, so that retried items, whose modules are kept, get the same env.
+func CalItemEnv(job *Job, itemName string, attempt int32) []corev1.EnvVar {
+	res := []corev1.EnvVar{
+		{Name: EnvJobName, Value: job.Name},
+		{Name: EnvItemName, Value: itemName},
+		{Name: EnvRunID, Value: string(job.UID)},
+		{Name: EnvAttempt, Value: strconv.Itoa(int(attempt))},
+	}
+
+	jobItems := CalJobItems(job)
+	items := map[string]*Item{}
+	for i := range jobItems {
+		items[jobItems[i].Name] = &jobItems[i]
+	}
+
+	item, ok := items[itemName]
+	if !ok {
+		return res
+	}
+
+	for _, t := range item.ItemModules.Services {
+		res = append(res, corev1.EnvVar{
+			Name:  calEnvName("service", t.Name, "name"),
+			Value: CalJobItemSubName(job.Name, itemName, t.Name),
+		})
+	}
+	for _, t := range item.ItemModules.ConfigMaps {
+		res = append(res, corev1.EnvVar{
+			Name:  calEnvName("configmap", t.Name, "name"),
+			Value: CalJobItemSubName(job.Name, itemName, t.Name),
+		})
+	}
+	for _, t := range item.ItemModules.Secrets {
+		res = append(res, corev1.EnvVar{
+			Name:  calEnvName("secret", t.Name, "name"),
+			Value: CalJobItemSubName(job.Name, itemName, t.Name),
+		})
+	}
+
+	var ancestors []string
+	for name := range calItemAncestors(itemName, items) {
+		ancestors = append(ancestors, name)
+	}
+	sort.Strings(ancestors)
+
+	for _, name := range ancestors {
+		ancestor, ok := items[name]
+		if !ok {
+			continue
+		}
+
+		for _, t := range ancestor.ItemModules.Services {
+			res = append(res, corev1.EnvVar{
+				Name:  calEnvName("item", name, "service", t.Name, "host"),
+				Value: fmt.Sprintf("%s.%s.svc", CalJobItemSubName(job.Name, name, t.Name), job.Namespace),
+			})
+		}
+	}
+
+	return res
+}
@@ -12,7 +12,7 @@ func NewGraphFromJob(job *Job) (*ItemNode, map[string]*ItemNode, error) {
 	nodeMap := map[string]*ItemNode{}
 	var fatherNode *ItemNode
 
-	for _, item := range job.Spec.Items {
+	for _, item := range CalJobItems(job) {
 		if _, ok := nodeMap[item.Name]; ok {
 			return nil, nil, fmt.Errorf("new job item tree build err: item Name %s repeated", item.Name)
 		}
@@ -0,0 +1,252 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+)
+
+func CalGroupItemName(groupName, itemName string) string {
+	return fmt.Sprintf("%s-%s", groupName, itemName)
+}
+
+// calGroupSinks returns the names of items in group which no other item of group runs after.
+func calGroupSinks(group *ItemGroup) []string {
+	fathers := map[string]bool{}
+	for _, item := range group.Items {
+		for _, fatherName := range item.RunAfter {
+			fathers[fatherName] = true
+		}
+	}
+
+	var res []string
+	for _, item := range group.Items {
+		if !fathers[item.Name] {
+			res = append(res, CalGroupItemName(group.Name, item.Name))
+		}
+	}
+
+	return res
+}
+
+// CalJobItems returns all items of job, items of groups are flattened into items named "<group>-<item>",
+// and "group:<group>" in RunAfter is replaced by the items of group which no other item of group runs after.
+func CalJobItems(job *Job) []Item {
+	if len(job.Spec.Groups) == 0 {
+		return job.Spec.Items
+	}
+
+	sinks := map[string][]string{}
+	for i := range job.Spec.Groups {
+		sinks[job.Spec.Groups[i].Name] = calGroupSinks(&job.Spec.Groups[i])
+	}
+
+	resolveRunAfter := func(runAfter []string) []string {
+		var res []string
+		for _, name := range runAfter {
+			groupName := strings.TrimPrefix(name, GroupRunAfterPrefix)
+			if groupName == name {
+				res = append(res, name)
+				continue
+			}
+
+			groupSinks, ok := sinks[groupName]
+			if !ok {
+				// keep it, so that it is reported as a parent not found
+				res = append(res, name)
+				continue
+			}
+			res = append(res, groupSinks...)
+		}
+
+		return res
+	}
+
+	var res []Item
+	for _, item := range job.Spec.Items {
+		impl := item.DeepCopy()
+		impl.RunAfter = resolveRunAfter(item.RunAfter)
+		res = append(res, *impl)
+	}
+
+	for _, group := range job.Spec.Groups {
+		for _, item := range group.Items {
+			impl := item.DeepCopy()
+			impl.Name = CalGroupItemName(group.Name, item.Name)
+
+			if len(item.RunAfter) == 0 {
+				impl.RunAfter = resolveRunAfter(group.RunAfter)
+			} else {
+				impl.RunAfter = nil
+				for _, fatherName := range item.RunAfter {
+					impl.RunAfter = append(impl.RunAfter, CalGroupItemName(group.Name, fatherName))
+				}
+			}
+
+			if impl.Annotations == nil {
+				impl.Annotations = map[string]string{}
+			}
+			impl.Annotations[ItemGroupAnnotation] = group.Name
+
+			res = append(res, *impl)
+		}
+	}
+
+	return res
+}
+
+// GetItemGroup returns the group item flattened from, nil if item not in group.
+func GetItemGroup(job *Job, item *Item) *ItemGroup {
+	groupName, ok := item.Annotations[ItemGroupAnnotation]
+	if !ok {
+		return nil
+	}
+
+	for i := range job.Spec.Groups {
+		if job.Spec.Groups[i].Name == groupName {
+			return &job.Spec.Groups[i]
+		}
+	}
+
+	return nil
+}
+
+// IsJobGroupValid checks the names and RunAfter of groups, the DAG of flattened items is checked as other items.
+func IsJobGroupValid(job *Job) (bool, string) {
+	itemNames := map[string]bool{}
+	for _, item := range job.Spec.Items {
+		itemNames[item.Name] = true
+	}
+
+	groupNames := map[string]bool{}
+	for _, group := range job.Spec.Groups {
+		if group.Name == "" {
+			return false, "group name can not be nil"
+		}
+		if groupNames[group.Name] {
+			return false, fmt.Sprintf("group name %s repeated", group.Name)
+		}
+		groupNames[group.Name] = true
+	}
+
+	for _, group := range job.Spec.Groups {
+		if len(group.Items) == 0 {
+			return false, fmt.Sprintf("group %s items can not be nil", group.Name)
+		}
+
+		for _, name := range group.RunAfter {
+			groupName := strings.TrimPrefix(name, GroupRunAfterPrefix)
+			if groupName == name {
+				if !itemNames[name] {
+					return false, fmt.Sprintf("group %s parent %s not found", group.Name, name)
+				}
+				continue
+			}
+
+			if !groupNames[groupName] {
+				return false, fmt.Sprintf("group %s parent group %s not found", group.Name, groupName)
+			}
+			if groupName == group.Name {
+				return false, fmt.Sprintf("group %s can not run after itself", group.Name)
+			}
+		}
+
+		memberNames := map[string]bool{}
+		for _, item := range group.Items {
+			if item.Name == "" {
+				return false, fmt.Sprintf("group %s item name can not be nil", group.Name)
+			}
+			if memberNames[item.Name] {
+				return false, fmt.Sprintf("group %s item name %s repeated", group.Name, item.Name)
+			}
+			memberNames[item.Name] = true
+		}
+
+		for _, item := range group.Items {
+			for _, fatherName := range item.RunAfter {
+				if !memberNames[fatherName] {
+					return false, fmt.Sprintf("group %s item %s parent %s not in group", group.Name, item.Name, fatherName)
+				}
+			}
+		}
+	}
+
+	for _, item := range job.Spec.Items {
+		for _, name := range item.RunAfter {
+			groupName := strings.TrimPrefix(name, GroupRunAfterPrefix)
+			if groupName != name && !groupNames[groupName] {
+				return false, fmt.Sprintf("item %s parent group %s not found", item.Name, groupName)
+			}
+		}
+	}
+
+	return true, ""
+}
+
+// CalGroupStatus rolls up the status of items in group, itemStatus is keyed by the flattened item names.
+func CalGroupStatus(group *ItemGroup, itemStatus map[string]ItemStatus) GroupStatus {
+	res := GroupStatus{
+		Phase: ItemPending,
+	}
+
+	finished := true
+	for _, item := range group.Items {
+		if item.Truncated != nil && *item.Truncated {
+			continue
+		}
+		res.ItemNum++
+
+		status, ok := itemStatus[CalGroupItemName(group.Name, item.Name)]
+		if !ok {
+			finished = false
+			continue
+		}
+
+		switch status.Phase {
+		case ItemScheduling, ItemScheduled:
+			res.RunningItemNum++
+			finished = false
+		case ItemCompleted:
+			res.CompletedItemNum++
+		case ItemFailed:
+			res.FailedItemNum++
+		default:
+			finished = false
+		}
+
+		if status.StartTime != nil && (res.StartTime == nil || status.StartTime.Before(res.StartTime)) {
+			res.StartTime = status.StartTime.DeepCopy()
+		}
+		if status.CompletionTime != nil && (res.CompletionTime == nil || res.CompletionTime.Before(status.CompletionTime)) {
+			res.CompletionTime = status.CompletionTime.DeepCopy()
+		}
+	}
+
+	switch {
+	case res.FailedItemNum > 0:
+		res.Phase = ItemFailed
+	case finished:
+		res.Phase = ItemCompleted
+	case res.RunningItemNum > 0 || res.CompletedItemNum > 0:
+		res.Phase = ItemScheduled
+	}
+
+	if res.Phase != ItemCompleted && res.Phase != ItemFailed {
+		res.CompletionTime = nil
+	}
+
+	return res
+}
+
+// CalJobGroupStatus rolls up the status of all groups in job.
+func CalJobGroupStatus(job *Job) map[string]GroupStatus {
+	if len(job.Spec.Groups) == 0 {
+		return nil
+	}
+
+	res := map[string]GroupStatus{}
+	for i := range job.Spec.Groups {
+		res[job.Spec.Groups[i].Name] = CalGroupStatus(&job.Spec.Groups[i], job.Status.ItemStatus)
+	}
+
+	return res
+}
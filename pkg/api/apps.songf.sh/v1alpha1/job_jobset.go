@@ -0,0 +1,79 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// JobSetGroupVersionKind is the kind of JobSet created for JobSetSpec.
+var JobSetGroupVersionKind = schema.GroupVersionKind{Group: "jobset.x-k8s.io", Version: "v1alpha2", Kind: "JobSet"}
+
+// ReplicatedJob is a group of kube jobs in JobSet created from the same template.
+// +kubebuilder:object:generate=false
+type ReplicatedJob struct {
+	Name     string                  `json:"name"`
+	Replicas *int32                  `json:"replicas,omitempty"`
+	Template batchv1.JobTemplateSpec `json:"template"`
+}
+
+// GetReplicatedJobs decodes the replicated jobs of JobSet spec, the only part of spec songf reads.
+func GetReplicatedJobs(raw *runtime.RawExtension) ([]ReplicatedJob, error) {
+	if raw == nil || len(raw.Raw) == 0 {
+		return nil, fmt.Errorf("jobset spec can not be nil")
+	}
+
+	spec := struct {
+		ReplicatedJobs []ReplicatedJob `json:"replicatedJobs"`
+	}{}
+	if err := json.Unmarshal(raw.Raw, &spec); err != nil {
+		return nil, fmt.Errorf("unmarshal jobset spec err: %s", err.Error())
+	}
+
+	return spec.ReplicatedJobs, nil
+}
+
+// CalReplicatedJobReplicas returns the num of kube jobs of replicated job, 1 if not set.
+func CalReplicatedJobReplicas(job *ReplicatedJob) int32 {
+	if job.Replicas == nil {
+		return 1
+	}
+
+	return *job.Replicas
+}
+
+// isJobSetSpecValid checks the replicated jobs of JobSet spec, other fields are left to JobSet.
+func isJobSetSpecValid(raw *runtime.RawExtension) error {
+	jobs, err := GetReplicatedJobs(raw)
+	if err != nil {
+		return err
+	}
+
+	if len(jobs) == 0 {
+		return fmt.Errorf("replicatedJobs can not be nil")
+	}
+
+	names := map[string]bool{}
+	for _, job := range jobs {
+		if job.Name == "" {
+			return fmt.Errorf("name of replicated job can not be nil")
+		}
+
+		if names[job.Name] {
+			return fmt.Errorf("replicated job %s repeated", job.Name)
+		}
+		names[job.Name] = true
+
+		if CalReplicatedJobReplicas(&job) < 0 {
+			return fmt.Errorf("replicas of replicated job %s can not be negative", job.Name)
+		}
+
+		if len(job.Template.Spec.Template.Spec.Containers) == 0 {
+			return fmt.Errorf("containers of replicated job %s can not be nil", job.Name)
+		}
+	}
+
+	return nil
+}
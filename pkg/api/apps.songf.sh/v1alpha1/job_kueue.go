@@ -0,0 +1,44 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"strings"
+)
+
+const (
+	// KueueQueueNameLabel submits a kube job or JobSet to a Kueue LocalQueue.
+	KueueQueueNameLabel = "kueue.x-k8s.io/queue-name"
+
+	// WaitingAdmissionReason is the reason of jobs and Items waiting for admission of Kueue.
+	WaitingAdmissionReason = "WaitingAdmission"
+)
+
+// CalItemKueueQueueName returns the Kueue LocalQueue of item, the one of item wins over the one of job.
+func CalItemKueueQueueName(job *Job, item *Item) string {
+	if item.KueueQueueName != "" {
+		return item.KueueQueueName
+	}
+
+	return job.Spec.KueueQueueName
+}
+
+// IsKueueQueueNameValid checks the Kueue LocalQueues of job and items are valid label values.
+func IsKueueQueueNameValid(job *Job) (bool, string) {
+	names := []string{job.Spec.KueueQueueName}
+	for _, item := range CalJobItems(job) {
+		names = append(names, item.KueueQueueName)
+	}
+
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+
+		if errs := validation.IsValidLabelValue(name); len(errs) > 0 {
+			return false, fmt.Sprintf("kueue queue name %s invalid: %s", name, strings.Join(errs, ", "))
+		}
+	}
+
+	return true, ""
+}
@@ -0,0 +1,92 @@
+package v1alpha1
+
+import (
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GetPodTemplate returns the pod template and replicas of the workload of long-running item.
+func (t *LongRunningTemplate) GetPodTemplate() (*corev1.PodTemplateSpec, int32) {
+	replicas := int32(1)
+
+	switch {
+	case t.Deployment != nil:
+		if t.Deployment.Replicas != nil {
+			replicas = *t.Deployment.Replicas
+		}
+		return &t.Deployment.Template, replicas
+	case t.StatefulSet != nil:
+		if t.StatefulSet.Replicas != nil {
+			replicas = *t.StatefulSet.Replicas
+		}
+		return &t.StatefulSet.Template, replicas
+	}
+
+	return nil, 0
+}
+
+// IsItemLongRunningValid checks the long-running items.
+func IsItemLongRunningValid(job *Job) (bool, string) {
+	items := CalJobItems(job)
+
+	itemMap := map[string]*Item{}
+	for i := range items {
+		itemMap[items[i].Name] = &items[i]
+	}
+
+	for _, item := range items {
+		longRunning := item.LongRunning
+		if longRunning == nil {
+			continue
+		}
+
+		if len(item.ItemJobs.Jobs) != 0 || item.SubJob != nil || item.Loop != nil || item.Wait != nil {
+			return false, fmt.Sprintf("long-running item %s can not have jobs, sub job, loop or wait", item.Name)
+		}
+
+		if item.CoScheduleGroup != "" {
+			return false, fmt.Sprintf("long-running item %s can not be in co-schedule group", item.Name)
+		}
+
+		if longRunning.Name == "" {
+			return false, fmt.Sprintf("long-running item %s workload name can not be nil", item.Name)
+		}
+
+		if (longRunning.Deployment == nil) == (longRunning.StatefulSet == nil) {
+			return false, fmt.Sprintf("long-running item %s must set exactly one of deployment and statefulSet", item.Name)
+		}
+
+		for _, name := range longRunning.StopAfter {
+			if _, ok := itemMap[name]; !ok {
+				return false, fmt.Sprintf("long-running item %s stop after item %s not found", item.Name, name)
+			}
+
+			if !isItemDownstream(itemMap, name, item.Name, map[string]bool{}) {
+				return false, fmt.Sprintf("long-running item %s stop after item %s is not downstream of it", item.Name, name)
+			}
+		}
+	}
+
+	return true, ""
+}
+
+// isItemDownstream returns true if item named name runs after item named upstream directly or indirectly.
+func isItemDownstream(itemMap map[string]*Item, name, upstream string, visited map[string]bool) bool {
+	if visited[name] {
+		return false
+	}
+	visited[name] = true
+
+	item, ok := itemMap[name]
+	if !ok {
+		return false
+	}
+
+	for _, father := range item.RunAfter {
+		if father == upstream || isItemDownstream(itemMap, father, upstream, visited) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,126 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"github.com/google/cel-go/cel"
+)
+
+// CalItemJobName returns the name of a job or sub job of item, jobs of loop item are suffixed by the index of iteration.
+func CalItemJobName(jobName string, item *Item, baseName string, iteration int32) string {
+	if item.Loop == nil {
+		return CalJobItemSubName(jobName, item.Name, baseName)
+	}
+
+	return CalJobItemSubName(jobName, item.Name, fmt.Sprintf("%s-%d", baseName, iteration))
+}
+
+func newItemLoopEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("outputs", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("iteration", cel.IntType),
+	)
+}
+
+func compileItemLoopCondition(condition string) (cel.Program, error) {
+	env, err := newItemLoopEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	ast, issues := env.Compile(condition)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("condition returns %s, not bool", ast.OutputType())
+	}
+
+	return env.Program(ast)
+}
+
+// EvalItemLoopCondition evaluates the condition of loop with the outputs of an iteration, empty condition is always false.
+func EvalItemLoopCondition(condition string, outputs map[string]string, iteration int32) (bool, error) {
+	if condition == "" {
+		return false, nil
+	}
+
+	program, err := compileItemLoopCondition(condition)
+	if err != nil {
+		return false, err
+	}
+
+	if outputs == nil {
+		outputs = map[string]string{}
+	}
+
+	val, _, err := program.Eval(map[string]interface{}{
+		"outputs":   outputs,
+		"iteration": int64(iteration),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	res, ok := val.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("condition returns %v, not bool", val.Value())
+	}
+
+	return res, nil
+}
+
+// IsItemLoopValid checks the loops of items.
+func IsItemLoopValid(job *Job) (bool, string) {
+	for _, item := range CalJobItems(job) {
+		if item.Loop == nil {
+			continue
+		}
+
+		if len(item.ItemJobs.Jobs) == 0 && item.SubJob == nil {
+			return false, fmt.Sprintf("loop item %s has no job to rerun", item.Name)
+		}
+
+		if item.CoScheduleGroup != "" {
+			return false, fmt.Sprintf("loop item %s can not be in co-schedule group", item.Name)
+		}
+
+		if item.Loop.MaxIterations < 1 {
+			return false, fmt.Sprintf("loop item %s max iterations must be greater than 0", item.Name)
+		}
+
+		if item.Loop.Condition == "" {
+			continue
+		}
+
+		if _, err := compileItemLoopCondition(item.Loop.Condition); err != nil {
+			return false, fmt.Sprintf("loop item %s condition invalid: %s", item.Name, err.Error())
+		}
+	}
+
+	return true, ""
+}
+
+// RecordItemIteration records the current iteration of loop item into its iterations.
+func RecordItemIteration(status *ItemStatus) {
+	iteration := ItemIterationStatus{
+		Iteration:      status.Iteration,
+		Phase:          status.Phase,
+		StartTime:      status.StartTime.DeepCopy(),
+		CompletionTime: status.CompletionTime.DeepCopy(),
+	}
+
+	if status.Outputs != nil {
+		iteration.Outputs = map[string]string{}
+		for k, v := range status.Outputs {
+			iteration.Outputs[k] = v
+		}
+	}
+
+	if n := len(status.Iterations); n > 0 && status.Iterations[n-1].Iteration == status.Iteration {
+		status.Iterations[n-1] = iteration
+		return
+	}
+
+	status.Iterations = append(status.Iterations, iteration)
+}
@@ -0,0 +1,87 @@
+package v1alpha1
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	"testing"
+)
+
+func TestEvalItemLoopCondition(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition string
+		outputs   map[string]string
+		iteration int32
+		stop      bool
+		err       bool
+	}{
+		{name: "empty condition never stops", outputs: map[string]string{"done": "true"}},
+		{name: "output matched", condition: `outputs["done"] == "true"`, outputs: map[string]string{"done": "true"}, stop: true},
+		{name: "output not matched", condition: `outputs["done"] == "true"`, outputs: map[string]string{"done": "false"}},
+		{name: "output converted", condition: `double(outputs["loss"]) < 0.1`, outputs: map[string]string{"loss": "0.05"}, stop: true},
+		{name: "iteration", condition: `iteration >= 2`, iteration: 2, stop: true},
+		{name: "nil outputs", condition: `"done" in outputs`},
+		{name: "missing output", condition: `outputs["done"] == "true"`, outputs: map[string]string{}, err: true},
+		{name: "not bool", condition: `iteration + 1`, err: true},
+		{name: "syntax error", condition: `outputs[`, err: true},
+	}
+
+	for _, test := range tests {
+		stop, err := EvalItemLoopCondition(test.condition, test.outputs, test.iteration)
+		if (err != nil) != test.err {
+			t.Errorf("%s: expected err %t, got %v", test.name, test.err, err)
+			continue
+		}
+		if stop != test.stop {
+			t.Errorf("%s: expected stop %t, got %t", test.name, test.stop, stop)
+		}
+	}
+}
+
+func TestIsItemLoopValid(t *testing.T) {
+	jobs := ItemJobResource{Jobs: []ItemJobTemplate{{
+		TemplateBaseInfo: TemplateBaseInfo{Name: "train"},
+		KubeJobSpec:      &batchv1.JobSpec{},
+	}}}
+
+	tests := []struct {
+		name  string
+		item  Item
+		valid bool
+	}{
+		{name: "valid", item: Item{Name: "a", ItemJobs: jobs, Loop: &ItemLoop{Condition: `iteration > 1`, MaxIterations: 3}}, valid: true},
+		{name: "no condition", item: Item{Name: "a", ItemJobs: jobs, Loop: &ItemLoop{MaxIterations: 3}}, valid: true},
+		{name: "no job", item: Item{Name: "a", Loop: &ItemLoop{MaxIterations: 3}}},
+		{name: "no iteration", item: Item{Name: "a", ItemJobs: jobs, Loop: &ItemLoop{}}},
+		{name: "co-scheduled", item: Item{Name: "a", ItemJobs: jobs, CoScheduleGroup: "g", Loop: &ItemLoop{MaxIterations: 3}}},
+		{name: "condition not bool", item: Item{Name: "a", ItemJobs: jobs, Loop: &ItemLoop{Condition: `outputs`, MaxIterations: 3}}},
+	}
+
+	for _, test := range tests {
+		job := &Job{Spec: JobSpec{Items: []Item{test.item}}}
+		if valid, message := IsItemLoopValid(job); valid != test.valid {
+			t.Errorf("%s: expected valid %t, got %t %s", test.name, test.valid, valid, message)
+		}
+	}
+}
+
+func TestRecordItemIteration(t *testing.T) {
+	status := &ItemStatus{Phase: ItemScheduled, Iteration: 1}
+
+	RecordItemIteration(status)
+	status.Phase = ItemCompleted
+	status.Outputs = map[string]string{"loss": "0.1"}
+	RecordItemIteration(status)
+
+	if len(status.Iterations) != 1 {
+		t.Fatalf("iteration should be recorded once, got %v", status.Iterations)
+	}
+	if iteration := status.Iterations[0]; iteration.Phase != ItemCompleted || iteration.Outputs["loss"] != "0.1" {
+		t.Errorf("iteration should be updated, got %v", iteration)
+	}
+
+	// outputs recorded are not changed with status
+	status.Outputs["loss"] = "0.2"
+	if status.Iterations[0].Outputs["loss"] != "0.1" {
+		t.Errorf("outputs of iteration should be copied")
+	}
+}
@@ -0,0 +1,42 @@
+package v1alpha1
+
+import (
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// CalJobNetworkPolicyName returns the name of the NetworkPolicy isolating job.
+func CalJobNetworkPolicyName(jobName string) string {
+	return fmt.Sprintf("%s-network-isolation", jobName)
+}
+
+// CalJobNetworkPolicySpec returns the spec of the NetworkPolicy isolating job, which selects the pods of job
+// and only allows traffic between them, DNS and the egress of job.
+func CalJobNetworkPolicySpec(job *Job) networkingv1.NetworkPolicySpec {
+	jobPods := []networkingv1.NetworkPolicyPeer{
+		{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{CreateByJob: job.Name}}},
+	}
+
+	dnsPort := intstr.FromInt(53)
+	udp, tcp := corev1.ProtocolUDP, corev1.ProtocolTCP
+
+	egress := []networkingv1.NetworkPolicyEgressRule{
+		{To: jobPods},
+		{Ports: []networkingv1.NetworkPolicyPort{{Protocol: &udp, Port: &dnsPort}, {Protocol: &tcp, Port: &dnsPort}}},
+	}
+	if job.Spec.NetworkIsolation != nil {
+		for _, rule := range job.Spec.NetworkIsolation.Egress {
+			egress = append(egress, *rule.DeepCopy())
+		}
+	}
+
+	return networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{CreateByJob: job.Name}},
+		Ingress:     []networkingv1.NetworkPolicyIngressRule{{From: jobPods}},
+		Egress:      egress,
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+	}
+}
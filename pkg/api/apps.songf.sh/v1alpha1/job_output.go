@@ -0,0 +1,231 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+var itemOutputRegexp = regexp.MustCompile(`\$\(items\.([a-zA-Z0-9_-]+)\.outputs\.([a-zA-Z0-9_.-]+)\)`)
+
+// ItemOutputReference is a reference to the output of Item, "$(items.<item>.outputs.<name>)".
+type ItemOutputReference struct {
+	ItemName   string
+	OutputName string
+}
+
+// GetItemOutputReferences returns the references to item outputs in s.
+func GetItemOutputReferences(s string) []ItemOutputReference {
+	var res []ItemOutputReference
+
+	for _, match := range itemOutputRegexp.FindAllStringSubmatch(s, -1) {
+		res = append(res, ItemOutputReference{
+			ItemName:   match[1],
+			OutputName: match[2],
+		})
+	}
+
+	return res
+}
+
+// CalItemOutputReferences returns the references to item outputs in item.
+func CalItemOutputReferences(item *Item) ([]ItemOutputReference, error) {
+	raw, err := json.Marshal(item)
+	if err != nil {
+		return nil, fmt.Errorf("item %s not illegal: %s", item.Name, err.Error())
+	}
+
+	return GetItemOutputReferences(string(raw)), nil
+}
+
+// CalJobOutputReferences returns the names of outputs referenced by the items and the outputs of job,
+// key is the name of item whose outputs are referenced.
+func CalJobOutputReferences(job *Job) (map[string][]string, error) {
+	var refs []ItemOutputReference
+
+	items := CalJobItems(job)
+	for i := range items {
+		itemRefs, err := CalItemOutputReferences(&items[i])
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, itemRefs...)
+	}
+
+	for _, output := range job.Spec.Outputs {
+		refs = append(refs, GetItemOutputReferences(output.Value)...)
+	}
+
+	res := map[string][]string{}
+	found := map[ItemOutputReference]bool{}
+	for _, ref := range refs {
+		if found[ref] {
+			continue
+		}
+		found[ref] = true
+		res[ref.ItemName] = append(res[ref.ItemName], ref.OutputName)
+	}
+
+	return res, nil
+}
+
+// SubstituteItemOutputs replaces each "$(items.<item>.outputs.<name>)" in s with the output of item,
+// outputs is keyed by item name and then output name. If escape is true, outputs are escaped as
+// they are substituted inside json strings.
+func SubstituteItemOutputs(s string, outputs map[string]map[string]string, escape bool) (string, error) {
+	var err error
+
+	res := itemOutputRegexp.ReplaceAllStringFunc(s, func(ref string) string {
+		match := itemOutputRegexp.FindStringSubmatch(ref)
+
+		value, ok := outputs[match[1]][match[2]]
+		if !ok {
+			if err == nil {
+				err = fmt.Errorf("output %s of item %s not found", match[2], match[1])
+			}
+			return ref
+		}
+
+		if escape {
+			return escapeJSONString(value)
+		}
+		return value
+	})
+
+	return res, err
+}
+
+// RenderItemOutputs returns a copy of item whose references to item outputs are substituted.
+func RenderItemOutputs(item *Item, outputs map[string]map[string]string) (*Item, error) {
+	raw, err := json.Marshal(item)
+	if err != nil {
+		return nil, fmt.Errorf("render item %s outputs err: %s", item.Name, err.Error())
+	}
+
+	if len(GetItemOutputReferences(string(raw))) == 0 {
+		return item.DeepCopy(), nil
+	}
+
+	rendered, err := SubstituteItemOutputs(string(raw), outputs, true)
+	if err != nil {
+		return nil, fmt.Errorf("render item %s outputs err: %s", item.Name, err.Error())
+	}
+
+	res := &Item{}
+	if err := json.Unmarshal([]byte(rendered), res); err != nil {
+		return nil, fmt.Errorf("render item %s outputs err: %s", item.Name, err.Error())
+	}
+
+	return res, nil
+}
+
+// CalJobOutputs resolves the outputs declared in the spec of job.
+func CalJobOutputs(job *Job, outputs map[string]map[string]string) (map[string]string, error) {
+	res := map[string]string{}
+
+	for _, output := range job.Spec.Outputs {
+		value, err := SubstituteItemOutputs(output.Value, outputs, false)
+		if err != nil {
+			return nil, fmt.Errorf("resolve job output %s err: %s", output.Name, err.Error())
+		}
+
+		res[output.Name] = value
+	}
+
+	return res, nil
+}
+
+// GetSubJobSpec returns the spec of the child Job defined in item.
+func GetSubJobSpec(item *Item) (*JobSpec, error) {
+	spec := &JobSpec{}
+
+	if len(item.SubJob.Spec.Raw) == 0 {
+		return spec, nil
+	}
+
+	if err := json.Unmarshal(item.SubJob.Spec.Raw, spec); err != nil {
+		return nil, fmt.Errorf("item %s sub job spec not illegal: %s", item.Name, err.Error())
+	}
+
+	return spec, nil
+}
+
+// calItemAncestors returns the names of items which item runs after directly or indirectly.
+func calItemAncestors(itemName string, items map[string]*Item) map[string]bool {
+	res := map[string]bool{}
+
+	queue := []string{itemName}
+	for len(queue) > 0 {
+		item, ok := items[queue[0]]
+		queue = queue[1:]
+		if !ok {
+			continue
+		}
+
+		for _, fatherName := range item.RunAfter {
+			if res[fatherName] {
+				continue
+			}
+			res[fatherName] = true
+			queue = append(queue, fatherName)
+		}
+	}
+
+	return res
+}
+
+// IsItemOutputReferenceValid checks that items only reference the outputs of items they run after,
+// and the outputs of job only reference existing items.
+func IsItemOutputReferenceValid(job *Job) (bool, string) {
+	jobItems := CalJobItems(job)
+
+	items := map[string]*Item{}
+	for i := range jobItems {
+		items[jobItems[i].Name] = &jobItems[i]
+	}
+
+	for i := range jobItems {
+		item := &jobItems[i]
+
+		if item.SubJob != nil {
+			if item.SubJob.Name == "" {
+				return false, fmt.Sprintf("item %s sub job name can not be nil", item.Name)
+			}
+			if _, err := GetSubJobSpec(item); err != nil {
+				return false, err.Error()
+			}
+		}
+
+		refs, err := CalItemOutputReferences(item)
+		if err != nil {
+			return false, err.Error()
+		}
+
+		ancestors := calItemAncestors(item.Name, items)
+		for _, ref := range refs {
+			if !ancestors[ref.ItemName] {
+				return false, fmt.Sprintf("item %s references output %s of item %s which it does not run after",
+					item.Name, ref.OutputName, ref.ItemName)
+			}
+		}
+	}
+
+	outputNames := map[string]bool{}
+	for _, output := range job.Spec.Outputs {
+		if output.Name == "" {
+			return false, "job output name can not be nil"
+		}
+		if outputNames[output.Name] {
+			return false, fmt.Sprintf("job output %s repeated", output.Name)
+		}
+		outputNames[output.Name] = true
+
+		for _, ref := range GetItemOutputReferences(output.Value) {
+			if _, ok := items[ref.ItemName]; !ok {
+				return false, fmt.Sprintf("job output %s references item %s not found", output.Name, ref.ItemName)
+			}
+		}
+	}
+
+	return true, ""
+}
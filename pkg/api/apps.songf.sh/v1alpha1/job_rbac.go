@@ -0,0 +1,51 @@
+package v1alpha1
+
+import (
+	"fmt"
+)
+
+// IsItemRoleBindingAllowed checks that the role bindings of item only refer to the roles of item or the cluster
+// roles in allowed. Role bindings are created by operator on behalf of the creators of Jobs, so that cluster
+// roles like cluster-admin can not be bound.
+func IsItemRoleBindingAllowed(item *Item, allowed []string) (bool, string) {
+	roles := map[string]bool{}
+	for _, role := range item.ItemModules.Roles {
+		roles[role.Name] = true
+	}
+
+	for _, rb := range item.ItemModules.RoleBindings {
+		if rb.RoleRef.Kind != "ClusterRole" {
+			if !roles[rb.RoleRef.Name] {
+				return false, fmt.Sprintf("item %s role binding %s can only refer to the roles of item, role %s not found",
+					item.Name, rb.Name, rb.RoleRef.Name)
+			}
+			continue
+		}
+
+		found := false
+		for _, name := range allowed {
+			if name == rb.RoleRef.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false, fmt.Sprintf("item %s role binding %s can not refer to cluster role %s, which is not allowed",
+				item.Name, rb.Name, rb.RoleRef.Name)
+		}
+	}
+
+	return true, ""
+}
+
+// IsJobRoleBindingAllowed checks the role bindings of all items of job.
+func IsJobRoleBindingAllowed(job *Job, allowed []string) (bool, string) {
+	items := CalJobItems(job)
+	for i := range items {
+		if flag, msg := IsItemRoleBindingAllowed(&items[i], allowed); !flag {
+			return false, msg
+		}
+	}
+
+	return true, ""
+}
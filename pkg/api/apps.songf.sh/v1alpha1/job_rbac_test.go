@@ -0,0 +1,89 @@
+package v1alpha1
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	"testing"
+)
+
+func TestIsItemRoleBindingAllowed(t *testing.T) {
+	roles := []RoleTemplate{{TemplateBaseInfo: TemplateBaseInfo{Name: "reader"}}}
+	allowed := []string{"view"}
+
+	tests := []struct {
+		name    string
+		roleRef rbacv1.RoleRef
+		allowed bool
+	}{
+		{name: "role of item", roleRef: rbacv1.RoleRef{Kind: "Role", Name: "reader"}, allowed: true},
+		{name: "role not of item", roleRef: rbacv1.RoleRef{Kind: "Role", Name: "admin"}},
+		{name: "allowed cluster role", roleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"}, allowed: true},
+		{name: "cluster role not allowed", roleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"}},
+	}
+
+	for _, test := range tests {
+		item := &Item{Name: "a", ItemModules: ItemModuleResource{
+			Roles: roles,
+			RoleBindings: []RoleBindingTemplate{{
+				TemplateBaseInfo: TemplateBaseInfo{Name: "binding"},
+				RoleRef:          test.roleRef,
+			}},
+		}}
+
+		if flag, msg := IsItemRoleBindingAllowed(item, allowed); flag != test.allowed {
+			t.Errorf("%s: expected allowed %t, got %t: %s", test.name, test.allowed, flag, msg)
+		}
+	}
+}
+
+func TestIsItemModuleResourceValidRoles(t *testing.T) {
+	reader := RoleTemplate{TemplateBaseInfo: TemplateBaseInfo{Name: "reader"}}
+
+	tests := []struct {
+		name    string
+		modules ItemModuleResource
+		valid   bool
+	}{
+		{
+			name: "binding to role of item",
+			modules: ItemModuleResource{Roles: []RoleTemplate{reader}, RoleBindings: []RoleBindingTemplate{{
+				TemplateBaseInfo: TemplateBaseInfo{Name: "binding"},
+				RoleRef:          rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "reader"},
+			}}},
+			valid: true,
+		},
+		{
+			name: "binding to role in namespace",
+			modules: ItemModuleResource{RoleBindings: []RoleBindingTemplate{{
+				TemplateBaseInfo: TemplateBaseInfo{Name: "binding"},
+				RoleRef:          rbacv1.RoleRef{Kind: "Role", Name: "admin"},
+			}}},
+		},
+		{
+			name: "binding with other api group",
+			modules: ItemModuleResource{RoleBindings: []RoleBindingTemplate{{
+				TemplateBaseInfo: TemplateBaseInfo{Name: "binding"},
+				RoleRef:          rbacv1.RoleRef{APIGroup: "example.com", Kind: "ClusterRole", Name: "view"},
+			}}},
+		},
+		{
+			name: "binding to other kind",
+			modules: ItemModuleResource{RoleBindings: []RoleBindingTemplate{{
+				TemplateBaseInfo: TemplateBaseInfo{Name: "binding"},
+				RoleRef:          rbacv1.RoleRef{Kind: "User", Name: "view"},
+			}}},
+		},
+		{
+			name: "role with non resource urls",
+			modules: ItemModuleResource{Roles: []RoleTemplate{{
+				TemplateBaseInfo: TemplateBaseInfo{Name: "reader"},
+				Rules:            []rbacv1.PolicyRule{{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}}},
+			}}},
+		},
+	}
+
+	for _, test := range tests {
+		if flag, msg := IsItemModuleResourceValid(test.modules); flag != test.valid {
+			t.Errorf("%s: expected valid %t, got %t: %s", test.name, test.valid, flag, msg)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+var itemNameReferenceRegexp = regexp.MustCompile(
+	`\$\((modules|items)\.([a-zA-Z0-9_-]+)\.([a-zA-Z]+)\.([a-zA-Z0-9_.-]+)\.name\)`)
+
+// ItemNameReference is a reference to the generated name of an object of Item, "$(modules.<item>.<kind>.<name>.name)"
+// for modules like "$(modules.a.services.db.name)", and "$(items.<item>.jobs.<name>.name)" for jobs.
+type ItemNameReference struct {
+	ItemName string
+	// Kind is the field of modules in ItemModuleResource, like services and configMaps, or jobs.
+	Kind string
+	Name string
+}
+
+// GetItemNameReferences returns the references to generated names in s.
+func GetItemNameReferences(s string) []ItemNameReference {
+	var res []ItemNameReference
+
+	for _, match := range itemNameReferenceRegexp.FindAllStringSubmatch(s, -1) {
+		res = append(res, ItemNameReference{
+			ItemName: match[2],
+			Kind:     match[3],
+			Name:     match[4],
+		})
+	}
+
+	return res
+}
+
+// calItemModuleNames returns the names of modules of item keyed by kind.
+func calItemModuleNames(item *Item) map[string][]string {
+	res := map[string][]string{}
+	modules := item.ItemModules
+
+	for _, t := range modules.Services {
+		res["services"] = append(res["services"], t.Name)
+	}
+	for _, t := range modules.ConfigMaps {
+		res["configMaps"] = append(res["configMaps"], t.Name)
+	}
+	for _, t := range modules.Secrets {
+		res["secrets"] = append(res["secrets"], t.Name)
+	}
+	for _, t := range modules.Pvcs {
+		res["pvcs"] = append(res["pvcs"], t.Name)
+	}
+	for _, t := range modules.Pvs {
+		res["pvs"] = append(res["pvs"], t.Name)
+	}
+	for _, t := range modules.ServiceAccounts {
+		res["serviceAccounts"] = append(res["serviceAccounts"], t.Name)
+	}
+	for _, t := range modules.Roles {
+		res["roles"] = append(res["roles"], t.Name)
+	}
+	for _, t := range modules.RoleBindings {
+		res["roleBindings"] = append(res["roleBindings"], t.Name)
+	}
+	for _, t := range modules.NetworkPolicies {
+		res["networkPolicies"] = append(res["networkPolicies"], t.Name)
+	}
+	for _, t := range modules.Ingresses {
+		res["ingresses"] = append(res["ingresses"], t.Name)
+	}
+	for _, t := range modules.PodDisruptionBudgets {
+		res["podDisruptionBudgets"] = append(res["podDisruptionBudgets"], t.Name)
+	}
+
+	return res
+}
+
+// calItemJobNames returns the names of jobs of item, including the workload of long-running item.
+func calItemJobNames(item *Item) []string {
+	var res []string
+
+	for _, itemJob := range item.ItemJobs.Jobs {
+		res = append(res, itemJob.Name)
+	}
+	if item.LongRunning != nil {
+		res = append(res, item.LongRunning.Name)
+	}
+
+	return res
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isItemNameReferenceResolvable returns an error if the object ref refers to is not defined in items.
+func isItemNameReferenceResolvable(ref ItemNameReference, prefix string, items map[string]*Item) error {
+	item, ok := items[ref.ItemName]
+	if !ok {
+		return fmt.Errorf("item %s not found", ref.ItemName)
+	}
+
+	switch prefix {
+	case "modules":
+		names, ok := calItemModuleNames(item)[ref.Kind]
+		if !ok || !containsString(names, ref.Name) {
+			return fmt.Errorf("%s %s of item %s not found", ref.Kind, ref.Name, ref.ItemName)
+		}
+	case "items":
+		if ref.Kind != "jobs" || !containsString(calItemJobNames(item), ref.Name) {
+			return fmt.Errorf("%s %s of item %s not found", ref.Kind, ref.Name, ref.ItemName)
+		}
+	}
+
+	return nil
+}
+
+// SubstituteItemNames replaces each reference to generated name in s with the name of the object in job.
+// iterations is keyed by item name, which are used to name the jobs of loop items.
+func SubstituteItemNames(s string, jobName string, items map[string]*Item, iterations map[string]int32) (string, error) {
+	var err error
+
+	res := itemNameReferenceRegexp.ReplaceAllStringFunc(s, func(ref string) string {
+		match := itemNameReferenceRegexp.FindStringSubmatch(ref)
+		nameRef := ItemNameReference{ItemName: match[2], Kind: match[3], Name: match[4]}
+
+		if resolveErr := isItemNameReferenceResolvable(nameRef, match[1], items); resolveErr != nil {
+			if err == nil {
+				err = resolveErr
+			}
+			return ref
+		}
+
+		if match[1] == "items" {
+			return CalItemJobName(jobName, items[nameRef.ItemName], nameRef.Name, iterations[nameRef.ItemName])
+		}
+		return CalJobItemSubName(jobName, nameRef.ItemName, nameRef.Name)
+	})
+
+	return res, err
+}
+
+// RenderItemNames returns a copy of item whose references to generated names are substituted.
+func RenderItemNames(job *Job, item *Item, iterations map[string]int32) (*Item, error) {
+	raw, err := json.Marshal(item)
+	if err != nil {
+		return nil, fmt.Errorf("render item %s names err: %s", item.Name, err.Error())
+	}
+
+	if len(GetItemNameReferences(string(raw))) == 0 {
+		return item.DeepCopy(), nil
+	}
+
+	jobItems := CalJobItems(job)
+	items := map[string]*Item{}
+	for i := range jobItems {
+		items[jobItems[i].Name] = &jobItems[i]
+	}
+
+	rendered, err := SubstituteItemNames(string(raw), job.Name, items, iterations)
+	if err != nil {
+		return nil, fmt.Errorf("render item %s names err: %s", item.Name, err.Error())
+	}
+
+	res := &Item{}
+	if err := json.Unmarshal([]byte(rendered), res); err != nil {
+		return nil, fmt.Errorf("render item %s names err: %s", item.Name, err.Error())
+	}
+
+	return res, nil
+}
+
+// IsItemNameReferenceValid checks that every reference to generated name in items resolves to an object
+// defined in job.
+func IsItemNameReferenceValid(job *Job) (bool, string) {
+	jobItems := CalJobItems(job)
+
+	items := map[string]*Item{}
+	for i := range jobItems {
+		items[jobItems[i].Name] = &jobItems[i]
+	}
+
+	for i := range jobItems {
+		item := &jobItems[i]
+
+		raw, err := json.Marshal(item)
+		if err != nil {
+			return false, fmt.Sprintf("item %s not illegal: %s", item.Name, err.Error())
+		}
+
+		if _, err := SubstituteItemNames(string(raw), job.Name, items, nil); err != nil {
+			return false, fmt.Sprintf("item %s references name not resolved: %s", item.Name, err.Error())
+		}
+	}
+
+	return true, ""
+}
@@ -0,0 +1,112 @@
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestReferenceJob(reference string) *Job {
+	return &Job{
+		Spec: JobSpec{Items: []Item{
+			{
+				Name: "a",
+				ItemJobs: ItemJobResource{Jobs: []ItemJobTemplate{
+					{TemplateBaseInfo: TemplateBaseInfo{Name: "train"}},
+				}},
+				ItemModules: ItemModuleResource{Services: []ServiceTemplate{
+					{TemplateBaseInfo: TemplateBaseInfo{Name: "db"}},
+				}},
+				LongRunning: &LongRunningTemplate{TemplateBaseInfo: TemplateBaseInfo{Name: "server"}},
+			},
+			{
+				Name: "l",
+				ItemJobs: ItemJobResource{Jobs: []ItemJobTemplate{
+					{TemplateBaseInfo: TemplateBaseInfo{Name: "train"}},
+				}},
+				Loop: &ItemLoop{MaxIterations: 3},
+			},
+			{
+				Name:     "b",
+				RunAfter: []string{"a", "l"},
+				ItemJobs: ItemJobResource{Jobs: []ItemJobTemplate{
+					{TemplateBaseInfo: TemplateBaseInfo{Name: "eval", Labels: map[string]string{"ref": reference}}},
+				}},
+			},
+		}},
+	}
+}
+
+func TestRenderItemNames(t *testing.T) {
+	tests := []struct {
+		name      string
+		reference string
+		expected  string
+		err       string
+	}{
+		{name: "no reference", reference: "plain", expected: "plain"},
+		{name: "module", reference: "$(modules.a.services.db.name)", expected: "job-a-db"},
+		{name: "job", reference: "$(items.a.jobs.train.name)", expected: "job-a-train"},
+		{name: "long-running workload", reference: "$(items.a.jobs.server.name)", expected: "job-a-server"},
+		{name: "job of loop item", reference: "$(items.l.jobs.train.name)", expected: "job-l-train-2"},
+		{name: "several references", reference: "$(modules.a.services.db.name).$(items.a.jobs.train.name)",
+			expected: "job-a-db.job-a-train"},
+		{name: "unknown item", reference: "$(items.x.jobs.train.name)", err: "item x not found"},
+		{name: "unknown module", reference: "$(modules.a.services.cache.name)", err: "services cache of item a not found"},
+		{name: "unknown module kind", reference: "$(modules.a.deployments.db.name)", err: "deployments db of item a not found"},
+		{name: "module referenced as job", reference: "$(items.a.jobs.db.name)", err: "jobs db of item a not found"},
+		{name: "job referenced as module", reference: "$(modules.a.jobs.train.name)", err: "jobs train of item a not found"},
+		{name: "module kind under items", reference: "$(items.a.services.db.name)", err: "services db of item a not found"},
+		{name: "first of several unresolved", reference: "$(items.x.jobs.train.name).$(items.y.jobs.train.name)",
+			err: "item x not found"},
+	}
+
+	for _, test := range tests {
+		job := newTestReferenceJob(test.reference)
+		job.Name = "job"
+		item := &job.Spec.Items[2]
+
+		res, err := RenderItemNames(job, item, map[string]int32{"l": 2})
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: expected err %s, got %v", test.name, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: render err: %s", test.name, err.Error())
+			continue
+		}
+
+		if got := res.ItemJobs.Jobs[0].Labels["ref"]; got != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, got)
+		}
+		if item.ItemJobs.Jobs[0].Labels["ref"] != test.reference {
+			t.Errorf("%s: item changed by rendering", test.name)
+		}
+	}
+}
+
+func TestIsItemNameReferenceValid(t *testing.T) {
+	tests := []struct {
+		name      string
+		reference string
+		valid     bool
+	}{
+		{name: "no reference", reference: "plain", valid: true},
+		{name: "module", reference: "$(modules.a.services.db.name)", valid: true},
+		{name: "job of loop item", reference: "$(items.l.jobs.train.name)", valid: true},
+		{name: "unknown item", reference: "$(items.x.jobs.train.name)"},
+		{name: "unknown module", reference: "$(modules.a.configMaps.db.name)"},
+		{name: "unknown job", reference: "$(items.a.jobs.eval.name)"},
+	}
+
+	for _, test := range tests {
+		valid, msg := IsItemNameReferenceValid(newTestReferenceJob(test.reference))
+		if valid != test.valid {
+			t.Errorf("%s: expected valid %t, got %t %s", test.name, test.valid, valid, msg)
+		}
+		if !valid && !strings.Contains(msg, "item b references name not resolved") {
+			t.Errorf("%s: unexpected message %s", test.name, msg)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package v1alpha1
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ScriptContainerName is the name of container running script.
+	ScriptContainerName = "script"
+
+	// ScriptFileName is the key of script in its ConfigMap, and the name of the mounted file.
+	ScriptFileName = "script"
+
+	scriptVolumeName = "songf-script"
+	scriptMountPath  = "/songf/script"
+)
+
+// CalScriptConfigMapName returns the name of ConfigMap holding the script of job in the modules of item.
+func CalScriptConfigMapName(itemJobName string) string {
+	return itemJobName + "-script"
+}
+
+// NewScriptJobSpec returns the kube job spec running script, the script is mounted from ConfigMap of item.
+func NewScriptJobSpec(jobName, itemName string, itemJob *ItemJobTemplate) *batchv1.JobSpec {
+	script := itemJob.Script
+
+	command := script.Command
+	if len(command) == 0 {
+		command = []string{"sh"}
+	}
+
+	backoffLimit := int32(0)
+	if script.BackoffLimit != nil {
+		backoffLimit = *script.BackoffLimit
+	}
+
+	mode := int32(0755)
+
+	return &batchv1.JobSpec{
+		BackoffLimit: &backoffLimit,
+		Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				RestartPolicy: corev1.RestartPolicyNever,
+				Containers: []corev1.Container{
+					{
+						Name:      ScriptContainerName,
+						Image:     script.Image,
+						Command:   append(append([]string{}, command...), scriptMountPath+"/"+ScriptFileName),
+						Env:       script.Env,
+						Resources: script.Resources,
+						VolumeMounts: []corev1.VolumeMount{
+							{
+								Name:      scriptVolumeName,
+								MountPath: scriptMountPath,
+								ReadOnly:  true,
+							},
+						},
+					},
+				},
+				Volumes: []corev1.Volume{
+					{
+						Name: scriptVolumeName,
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{
+									Name: CalJobItemSubName(jobName, itemName, CalScriptConfigMapName(itemJob.Name)),
+								},
+								DefaultMode: &mode,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ExpandScriptJobs returns a copy of item whose script jobs are expanded into kube jobs,
+// and the ConfigMaps of scripts are added to its modules.
+func ExpandScriptJobs(jobName string, item *Item) *Item {
+	res := item.DeepCopy()
+
+	for i := range res.ItemJobs.Jobs {
+		itemJob := &res.ItemJobs.Jobs[i]
+		if itemJob.Script == nil {
+			continue
+		}
+
+		itemJob.KubeJobSpec = NewScriptJobSpec(jobName, item.Name, itemJob)
+
+		res.ItemModules.ConfigMaps = append(res.ItemModules.ConfigMaps, ConfigMapTemplate{
+			TemplateBaseInfo: TemplateBaseInfo{
+				Name: CalScriptConfigMapName(itemJob.Name),
+			},
+			ConfigMap: corev1.ConfigMap{
+				Data: map[string]string{
+					ScriptFileName: itemJob.Script.Source,
+				},
+			},
+		})
+
+		itemJob.Script = nil
+	}
+
+	return res
+}
@@ -0,0 +1,87 @@
+package v1alpha1
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"strings"
+	"testing"
+)
+
+func TestExpandScriptJobs(t *testing.T) {
+	three := int32(3)
+
+	item := &Item{
+		Name: "prepare",
+		ItemJobs: ItemJobResource{Jobs: []ItemJobTemplate{
+			{
+				TemplateBaseInfo: TemplateBaseInfo{Name: "fetch"},
+				Script:           &ScriptJobSpec{Image: "busybox", Source: "echo fetch"},
+			},
+			{
+				TemplateBaseInfo: TemplateBaseInfo{Name: "clean"},
+				Script: &ScriptJobSpec{
+					Image:        "python:3",
+					Source:       "print('clean')",
+					Command:      []string{"python", "-u"},
+					Env:          []corev1.EnvVar{{Name: "A", Value: "b"}},
+					BackoffLimit: &three,
+				},
+			},
+			{
+				TemplateBaseInfo: TemplateBaseInfo{Name: "train"},
+				KubeJobSpec:      &batchv1.JobSpec{},
+			},
+		}},
+	}
+
+	expanded := ExpandScriptJobs("job", item)
+
+	tests := []struct {
+		name         string
+		command      string
+		backoffLimit int32
+	}{
+		{name: "fetch", command: "sh /songf/script/script", backoffLimit: 0},
+		{name: "clean", command: "python -u /songf/script/script", backoffLimit: 3},
+	}
+
+	for i, test := range tests {
+		itemJob := expanded.ItemJobs.Jobs[i]
+		if itemJob.Script != nil || itemJob.KubeJobSpec == nil {
+			t.Fatalf("%s: script job should be expanded into kube job", test.name)
+		}
+
+		spec := itemJob.KubeJobSpec
+		container := spec.Template.Spec.Containers[0]
+		if command := strings.Join(container.Command, " "); command != test.command {
+			t.Errorf("%s: expected command %s, got %s", test.name, test.command, command)
+		}
+		if *spec.BackoffLimit != test.backoffLimit || spec.Template.Spec.RestartPolicy != corev1.RestartPolicyNever {
+			t.Errorf("%s: unexpected backoff limit %d or restart policy %s", test.name, *spec.BackoffLimit,
+				spec.Template.Spec.RestartPolicy)
+		}
+
+		// the volume refers to the configmap module rendered for script
+		volume := spec.Template.Spec.Volumes[0]
+		if expect := CalJobItemSubName("job", "prepare", CalScriptConfigMapName(test.name)); volume.ConfigMap.Name != expect {
+			t.Errorf("%s: expected volume of configmap %s, got %s", test.name, expect, volume.ConfigMap.Name)
+		}
+		if container.VolumeMounts[0].Name != volume.Name || !container.VolumeMounts[0].ReadOnly {
+			t.Errorf("%s: script volume should be mounted read only", test.name)
+		}
+	}
+
+	if expanded.ItemJobs.Jobs[1].KubeJobSpec.Template.Spec.Containers[0].Env[0].Name != "A" {
+		t.Errorf("env of script should be kept")
+	}
+
+	configMaps := expanded.ItemModules.ConfigMaps
+	if len(configMaps) != 2 || configMaps[0].Name != "fetch-script" || configMaps[0].ConfigMap.Data[ScriptFileName] != "echo fetch" ||
+		configMaps[1].ConfigMap.Data[ScriptFileName] != "print('clean')" {
+		t.Errorf("unexpected configmaps of scripts %v", configMaps)
+	}
+
+	if item.ItemJobs.Jobs[0].Script == nil || len(item.ItemModules.ConfigMaps) != 0 {
+		t.Errorf("item should not be changed by expanding")
+	}
+}
@@ -0,0 +1,202 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var templateParameterRegexp = regexp.MustCompile(`\$\(params\.([a-zA-Z0-9_-]+)\)`)
+
+// CalJobTemplateParameters returns the values of all parameters declared by template,
+// parameters not set by job take their default values.
+func CalJobTemplateParameters(job *Job, definition *JobTemplateDefinition) (map[string]string, error) {
+	values := map[string]string{}
+
+	declared := map[string]*TemplateParameter{}
+	for i, parameter := range definition.Parameters {
+		if parameter.Name == "" {
+			return nil, fmt.Errorf("template parameter name can not be nil")
+		}
+		if _, ok := declared[parameter.Name]; ok {
+			return nil, fmt.Errorf("template parameter %s repeated", parameter.Name)
+		}
+		declared[parameter.Name] = &definition.Parameters[i]
+
+		if parameter.Default != nil {
+			values[parameter.Name] = *parameter.Default
+		}
+	}
+
+	set := map[string]bool{}
+	for _, parameter := range job.Spec.Parameters {
+		if _, ok := declared[parameter.Name]; !ok {
+			return nil, fmt.Errorf("parameter %s not declared by template", parameter.Name)
+		}
+		if set[parameter.Name] {
+			return nil, fmt.Errorf("parameter %s repeated", parameter.Name)
+		}
+		set[parameter.Name] = true
+
+		values[parameter.Name] = parameter.Value
+	}
+
+	for _, parameter := range definition.Parameters {
+		if _, ok := values[parameter.Name]; !ok {
+			return nil, fmt.Errorf("required parameter %s not set", parameter.Name)
+		}
+	}
+
+	return values, nil
+}
+
+// SubstituteTemplateParameters replaces each "$(params.<name>)" in the json of template with the value of parameter.
+func SubstituteTemplateParameters(raw string, values map[string]string) (string, error) {
+	var err error
+
+	res := templateParameterRegexp.ReplaceAllStringFunc(raw, func(s string) string {
+		name := templateParameterRegexp.FindStringSubmatch(s)[1]
+
+		value, ok := values[name]
+		if !ok {
+			if err == nil {
+				err = fmt.Errorf("parameter %s used by template not declared", name)
+			}
+			return s
+		}
+
+		return escapeJSONString(value)
+	})
+
+	return res, err
+}
+
+// escapeJSONString escapes value to be substituted inside a json string.
+func escapeJSONString(value string) string {
+	quoted, _ := json.Marshal(value)
+	return strings.TrimSuffix(strings.TrimPrefix(string(quoted), `"`), `"`)
+}
+
+// RenderJobTemplate renders template into job. Parameters are substituted, fields unset in the spec of job
+// are taken from template, labels and annotations of template are added to job if not set by job.
+func RenderJobTemplate(job *Job, definition *JobTemplateDefinition, generation int64) error {
+
+	values, err := CalJobTemplateParameters(job, definition)
+	if err != nil {
+		return fmt.Errorf("render template %s err: %s", job.Spec.TemplateRef.Name, err.Error())
+	}
+
+	raw, err := json.Marshal(definition.Template)
+	if err != nil {
+		return fmt.Errorf("render template %s err: %s", job.Spec.TemplateRef.Name, err.Error())
+	}
+
+	rendered, err := SubstituteTemplateParameters(string(raw), values)
+	if err != nil {
+		return fmt.Errorf("render template %s err: %s", job.Spec.TemplateRef.Name, err.Error())
+	}
+
+	template := &JobTemplateSpec{}
+	if err := json.Unmarshal([]byte(rendered), template); err != nil {
+		return fmt.Errorf("render template %s err: %s", job.Spec.TemplateRef.Name, err.Error())
+	}
+
+	spec, err := mergeJobSpec(&template.Spec, &job.Spec)
+	if err != nil {
+		return fmt.Errorf("render template %s err: %s", job.Spec.TemplateRef.Name, err.Error())
+	}
+	job.Spec = *spec
+
+	if job.Labels == nil && len(template.Labels) > 0 {
+		job.Labels = map[string]string{}
+	}
+	for key, value := range template.Labels {
+		if _, ok := job.Labels[key]; !ok {
+			job.Labels[key] = value
+		}
+	}
+
+	if job.Annotations == nil {
+		job.Annotations = map[string]string{}
+	}
+	for key, value := range template.Annotations {
+		if _, ok := job.Annotations[key]; !ok {
+			job.Annotations[key] = value
+		}
+	}
+	job.Annotations[TemplateGenerationAnnotation] = strconv.FormatInt(generation, 10)
+
+	return nil
+}
+
+// mergeJobSpec returns base overridden by the fields set in override.
+func mergeJobSpec(base, override *JobSpec) (*JobSpec, error) {
+	baseRaw, err := json.Marshal(base)
+	if err != nil {
+		return nil, err
+	}
+	overrideRaw, err := json.Marshal(override)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(baseRaw, &fields); err != nil {
+		return nil, err
+	}
+	overrideFields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(overrideRaw, &overrideFields); err != nil {
+		return nil, err
+	}
+	for key, value := range overrideFields {
+		fields[key] = value
+	}
+
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &JobSpec{}
+	if err := json.Unmarshal(raw, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// IsJobTemplateRendered returns true if job doesn't reference a template, or the template was rendered into it.
+func IsJobTemplateRendered(job *Job) bool {
+	if job.Spec.TemplateRef == nil {
+		return true
+	}
+
+	_, ok := job.Annotations[TemplateGenerationAnnotation]
+	return ok
+}
+
+// CalJobTemplateRevision returns the revision of template job rendered from, nil if job doesn't reference a template.
+func CalJobTemplateRevision(job *Job) *TemplateRevision {
+	if job.Spec.TemplateRef == nil {
+		return nil
+	}
+
+	revision := &TemplateRevision{
+		Kind: job.Spec.TemplateRef.Kind,
+		Name: job.Spec.TemplateRef.Name,
+	}
+	if revision.Kind == "" {
+		revision.Kind = JobTemplateKind
+	}
+
+	if value, ok := job.Annotations[TemplateGenerationAnnotation]; ok {
+		generation, err := strconv.ParseInt(value, 10, 64)
+		if err == nil {
+			revision.Generation = generation
+		}
+	}
+
+	return revision
+}
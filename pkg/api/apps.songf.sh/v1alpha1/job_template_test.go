@@ -0,0 +1,169 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func TestCalJobTemplateParameters(t *testing.T) {
+	defaultImage := "train:v1"
+	definition := &JobTemplateDefinition{Parameters: []TemplateParameter{
+		{Name: "image", Default: &defaultImage},
+		{Name: "dataset"},
+	}}
+
+	tests := []struct {
+		name   string
+		values []TemplateParameterValue
+		expect map[string]string
+	}{
+		{
+			name:   "default taken",
+			values: []TemplateParameterValue{{Name: "dataset", Value: "d1"}},
+			expect: map[string]string{"image": "train:v1", "dataset": "d1"},
+		},
+		{
+			name:   "default overridden",
+			values: []TemplateParameterValue{{Name: "dataset", Value: "d1"}, {Name: "image", Value: "train:v2"}},
+			expect: map[string]string{"image": "train:v2", "dataset": "d1"},
+		},
+		{
+			name:   "empty value set",
+			values: []TemplateParameterValue{{Name: "dataset"}},
+			expect: map[string]string{"image": "train:v1", "dataset": ""},
+		},
+		{name: "required not set"},
+		{name: "not declared", values: []TemplateParameterValue{{Name: "dataset"}, {Name: "epochs"}}},
+		{name: "repeated", values: []TemplateParameterValue{{Name: "dataset"}, {Name: "dataset"}}},
+	}
+
+	for _, test := range tests {
+		job := &Job{Spec: JobSpec{Parameters: test.values}}
+
+		values, err := CalJobTemplateParameters(job, definition)
+		if (err == nil) != (test.expect != nil) {
+			t.Errorf("%s: expected values %v, got err %v", test.name, test.expect, err)
+			continue
+		}
+		if len(values) != len(test.expect) {
+			t.Errorf("%s: expected values %v, got %v", test.name, test.expect, values)
+		}
+		for k, v := range test.expect {
+			if values[k] != v {
+				t.Errorf("%s: expected values %v, got %v", test.name, test.expect, values)
+			}
+		}
+	}
+}
+
+func TestSubstituteTemplateParameters(t *testing.T) {
+	raw := `{"command":"echo $(params.message)","image":"$(params.image)"}`
+
+	res, err := SubstituteTemplateParameters(raw, map[string]string{"message": `say "hi"`, "image": "train:v1"})
+	if err != nil {
+		t.Fatalf("substitute err: %s", err.Error())
+	}
+	if expect := `{"command":"echo say \"hi\"","image":"train:v1"}`; res != expect {
+		t.Errorf("expected %s, got %s", expect, res)
+	}
+
+	if _, err := SubstituteTemplateParameters(raw, map[string]string{"message": "hi"}); err == nil {
+		t.Errorf("parameter not declared should be rejected")
+	}
+}
+
+func TestMergeJobSpec(t *testing.T) {
+	two, four := int32(2), int32(4)
+
+	base := &JobSpec{
+		Parallelism: &two,
+		Queue:       "default",
+		Items:       []Item{{Name: "a"}, {Name: "b", RunAfter: []string{"a"}}},
+	}
+	override := &JobSpec{
+		Parallelism: &four,
+		Items:       []Item{{Name: "c"}},
+	}
+
+	spec, err := mergeJobSpec(base, override)
+	if err != nil {
+		t.Fatalf("merge err: %s", err.Error())
+	}
+
+	if *spec.Parallelism != 4 {
+		t.Errorf("field set by override should win, got %d", *spec.Parallelism)
+	}
+	if spec.Queue != "default" {
+		t.Errorf("field unset by override should be kept, got %s", spec.Queue)
+	}
+	// lists are replaced as a whole, not merged
+	if len(spec.Items) != 1 || spec.Items[0].Name != "c" {
+		t.Errorf("items should be replaced by override, got %v", spec.Items)
+	}
+	if *base.Parallelism != 2 || len(base.Items) != 2 {
+		t.Errorf("base should not be changed")
+	}
+}
+
+func TestRenderJobTemplate(t *testing.T) {
+	defaultImage := "train:v1"
+	definition := &JobTemplateDefinition{
+		Parameters: []TemplateParameter{{Name: "image", Default: &defaultImage}, {Name: "team"}},
+		Template: JobTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{"team": "$(params.team)", "app": "train"},
+				Annotations: map[string]string{"owner": "template"},
+			},
+			Spec: JobSpec{
+				Queue: "default",
+				Items: []Item{{Name: "train", Annotations: map[string]string{"image": "$(params.image)"}}},
+			},
+		},
+	}
+
+	job := &Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      map[string]string{"app": "mine"},
+			Annotations: map[string]string{TemplateGenerationAnnotation: "1"},
+		},
+		Spec: JobSpec{
+			TemplateRef: &TemplateReference{Name: "train"},
+			Parameters:  []TemplateParameterValue{{Name: "team", Value: "a"}},
+			Queue:       "mine",
+		},
+	}
+
+	if err := RenderJobTemplate(job, definition, 3); err != nil {
+		t.Fatalf("render err: %s", err.Error())
+	}
+
+	if job.Spec.Queue != "mine" || job.Spec.TemplateRef == nil || len(job.Spec.Parameters) != 1 {
+		t.Errorf("fields set by job should be kept, got %v", job.Spec)
+	}
+	if len(job.Spec.Items) != 1 || job.Spec.Items[0].Annotations["image"] != "train:v1" {
+		t.Errorf("items should be rendered from template with default parameters, got %v", job.Spec.Items)
+	}
+	if job.Labels["team"] != "a" || job.Labels["app"] != "mine" {
+		t.Errorf("labels of template should be added if not set by job, got %v", job.Labels)
+	}
+	if job.Annotations["owner"] != "template" || job.Annotations[TemplateGenerationAnnotation] != "3" {
+		t.Errorf("unexpected annotations %v", job.Annotations)
+	}
+
+	revision := CalJobTemplateRevision(job)
+	if revision == nil || revision.Kind != JobTemplateKind || revision.Name != "train" || revision.Generation != 3 {
+		t.Errorf("unexpected revision %v", revision)
+	}
+	if !IsJobTemplateRendered(job) {
+		t.Errorf("job should be rendered")
+	}
+
+	// required parameter not set
+	job = &Job{Spec: JobSpec{TemplateRef: &TemplateReference{Name: "train"}}}
+	if err := RenderJobTemplate(job, definition, 3); err == nil {
+		t.Errorf("job not setting required parameter should not be rendered")
+	}
+	if IsJobTemplateRendered(job) {
+		t.Errorf("job should not be rendered")
+	}
+}
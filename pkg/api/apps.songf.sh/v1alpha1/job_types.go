@@ -17,9 +17,14 @@ limitations under the License.
 package v1alpha1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
 )
 
@@ -61,6 +66,179 @@ type JobSpec struct {
 	// the Job becomes eligible to be deleted immediately after it finishes.
 	// +optional
 	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty" protobuf:"varint,2,opt,name=ttlSecondsAfterFinished"`
+
+	// Queue is the name of the Queue this Job is submitted to.
+	// If set, the Job stays Queued until the queue admits it.
+	// If unset, the Job is scheduled immediately.
+	// +optional
+	Queue string `json:"queue,omitempty" protobuf:"bytes,3,opt,name=queue"`
+
+	// Priority of the Job inside its queue, Jobs with higher priority are admitted first.
+	// Default to 0.
+	// +optional
+	Priority *int32 `json:"priority,omitempty" protobuf:"varint,4,opt,name=priority"`
+
+	// SchedulingPolicy decides which ready Items to dispatch and in what order.
+	// If unset, the default policy of operator is used. ShortestExpectedDuration and CriticalPath
+	// expect the durations of Items from the completed Items of the same names in the Jobs kept in cluster
+	// which are rendered from the same template, or created by the same ScheduledJob, or this Job itself.
+	// +kubebuilder:validation:Enum=FIFO;Priority;ShortestExpectedDuration;CriticalPath
+	// +optional
+	SchedulingPolicy string `json:"schedulingPolicy,omitempty" protobuf:"bytes,5,opt,name=schedulingPolicy"`
+
+	// Parallelism limits the num of Items in Scheduling or Scheduled phase at the same time.
+	// Ready Items exceeding the limit stay Pending. If unset, the num is not limited.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Parallelism *int32 `json:"parallelism,omitempty" protobuf:"varint,6,opt,name=parallelism"`
+
+	// MaxRunningPods limits the sum of pods wanted by the jobs of Items in Scheduling or Scheduled phase.
+	// Ready Items exceeding the limit stay Pending. An Item wanting more pods than the limit
+	// is dispatched only when no other Item is running. If unset, the num is not limited.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxRunningPods *int32 `json:"maxRunningPods,omitempty" protobuf:"varint,7,opt,name=maxRunningPods"`
+
+	// TemplateRef references the JobTemplate or ClusterJobTemplate this Job is rendered from.
+	// If set, the template is rendered into the Job by webhook on creation, so Items can be left unset.
+	// Later edits of the template don't affect the Job.
+	// +optional
+	TemplateRef *TemplateReference `json:"templateRef,omitempty" protobuf:"bytes,8,opt,name=templateRef"`
+
+	// Parameters are the values of parameters declared by the referenced template.
+	// +optional
+	Parameters []TemplateParameterValue `json:"parameters,omitempty" protobuf:"bytes,9,rep,name=parameters"`
+
+	// Outputs of the Job, resolved when the Job completes. Values can reference the outputs
+	// of Items by "$(items.<item>.outputs.<name>)". A Job running as the sub job of an Item
+	// passes its outputs to the Item.
+	// +optional
+	Outputs []JobOutput `json:"outputs,omitempty" protobuf:"bytes,10,rep,name=outputs"`
+
+	// Groups are named sets of Items with their own DAG. Items of a group are scheduled as Items
+	// named "<group>-<item>", and other Items can run after a group as a whole by "group:<group>" in RunAfter.
+	// +optional
+	Groups []ItemGroup `json:"groups,omitempty" protobuf:"bytes,11,rep,name=groups"`
+
+	// KueueQueueName is the Kueue LocalQueue the kube jobs and JobSets of Items are submitted to.
+	// They are created suspended with the label kueue.x-k8s.io/queue-name, and run once admitted by Kueue.
+	// +optional
+	KueueQueueName string `json:"kueueQueueName,omitempty" protobuf:"bytes,12,opt,name=kueueQueueName"`
+
+	// NetworkIsolation isolates the pods of Items from the other pods once set. A NetworkPolicy owned by
+	// the Job is created before the first Item runs, which only allows traffic between the pods labeled
+	// songf.sh/job=<job>, DNS and the egress set here.
+	// +optional
+	NetworkIsolation *NetworkIsolation `json:"networkIsolation,omitempty" protobuf:"bytes,13,opt,name=networkIsolation"`
+
+	// PodDefaults are applied to the pod templates of all jobs and workloads of Items, including every task of
+	// volcano jobs. They are merged with the pod templates by strategic merge, and the values of Items win.
+	// +optional
+	PodDefaults *PodDefaults `json:"podDefaults,omitempty" protobuf:"bytes,14,opt,name=podDefaults"`
+}
+
+// PodDefaults defines the values shared by the pods of all Items in Job.
+type PodDefaults struct {
+
+	// Labels of pods.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty" protobuf:"bytes,1,rep,name=labels"`
+
+	// Annotations of pods.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty" protobuf:"bytes,2,rep,name=annotations"`
+
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty" protobuf:"bytes,3,rep,name=nodeSelector"`
+
+	// Tolerations are added to the ones of Item, a toleration of Item overrides the defaults of the same key and effect.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty" protobuf:"bytes,4,rep,name=tolerations"`
+
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty" protobuf:"bytes,5,rep,name=imagePullSecrets"`
+
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty" protobuf:"bytes,6,opt,name=priorityClassName"`
+
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty" protobuf:"bytes,7,opt,name=serviceAccountName"`
+
+	// Env of all containers and init containers.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty" protobuf:"bytes,8,rep,name=env"`
+}
+
+// NetworkIsolation defines the traffic allowed for the pods of an isolated Job besides the one between them.
+type NetworkIsolation struct {
+
+	// Egress allowed besides the pods of Job and DNS.
+	// +optional
+	Egress []networkingv1.NetworkPolicyEgressRule `json:"egress,omitempty" protobuf:"bytes,1,rep,name=egress"`
+}
+
+// ItemGroup defines a set of Items and the policies applied to them.
+type ItemGroup struct {
+
+	// The name of group, must be unique in all groups.
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+
+	// RunAfter defines the Items or groups, by "group:<group>", this group runs after.
+	// Items of group not running after other Items of group run after them.
+	// +optional
+	RunAfter []string `json:"runAfter,omitempty" protobuf:"bytes,2,rep,name=runAfter"`
+
+	// Items of group, RunAfter of them can only reference Items of the same group by their names in group.
+	// The group completes when all its Items completed.
+	Items []Item `json:"items" protobuf:"bytes,3,rep,name=items"`
+
+	// BackoffLimit is the num of retries of each failed Item in group, the jobs and sub job of Item
+	// are recreated while retrying. Default to 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty" protobuf:"varint,4,opt,name=backoffLimit"`
+
+	// TimeoutSeconds limits the running time of each Item in group, Items running longer are failed.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int64 `json:"timeoutSeconds,omitempty" protobuf:"varint,5,opt,name=timeoutSeconds"`
+
+	// Parallelism limits the num of Items of group in Scheduling or Scheduled phase at the same time.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Parallelism *int32 `json:"parallelism,omitempty" protobuf:"varint,6,opt,name=parallelism"`
+}
+
+// JobOutput declares an output of Job.
+type JobOutput struct {
+
+	// The name of output, must be unique in the Job.
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+
+	// +optional
+	Value string `json:"value,omitempty" protobuf:"bytes,2,opt,name=value"`
+}
+
+// TemplateReference references a JobTemplate or ClusterJobTemplate.
+type TemplateReference struct {
+
+	// Kind of template, JobTemplate or ClusterJobTemplate. Default to JobTemplate.
+	// +kubebuilder:validation:Enum=JobTemplate;ClusterJobTemplate
+	// +optional
+	Kind string `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
+
+	// Name of template. A JobTemplate is looked up in the namespace of Job.
+	Name string `json:"name" protobuf:"bytes,2,opt,name=name"`
+}
+
+// TemplateParameterValue sets the value of a template parameter.
+type TemplateParameterValue struct {
+
+	// The name of parameter declared by template.
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+
+	// +optional
+	Value string `json:"value,omitempty" protobuf:"bytes,2,opt,name=value"`
 }
 
 // JobStatus defines the observed state of Job
@@ -72,6 +250,70 @@ type JobStatus struct {
 	// Current state of each open Item, including jobs and modules.
 	// +optional
 	ItemStatus map[string]ItemStatus `json:"itemStatus,omitempty" protobuf:"bytes,2,opt,name=itemStatus"`
+
+	// Position of the Job in its queue while Queued, 1 means the Job is the next one to admit.
+	// +optional
+	QueuePosition *int32 `json:"queuePosition,omitempty" protobuf:"varint,3,opt,name=queuePosition"`
+
+	// The revision of template the Job was rendered from.
+	// +optional
+	TemplateRevision *TemplateRevision `json:"templateRevision,omitempty" protobuf:"bytes,4,opt,name=templateRevision"`
+
+	// Outputs of the Job resolved from spec.outputs, key is output name.
+	// +optional
+	Outputs map[string]string `json:"outputs,omitempty" protobuf:"bytes,5,rep,name=outputs"`
+
+	// Current state of each group, key is group name.
+	// +optional
+	GroupStatus map[string]GroupStatus `json:"groupStatus,omitempty" protobuf:"bytes,6,rep,name=groupStatus"`
+}
+
+// GroupStatus rolls up the status of Items in group.
+type GroupStatus struct {
+	// The phase of group. Pending if no Item started, Scheduled if any Item started,
+	// Completed if all Items completed, and Failed if any Item failed.
+	// +optional
+	Phase ItemPhase `json:"phase,omitempty" protobuf:"bytes,1,opt,name=phase"`
+
+	// The num of Items in group.
+	// +optional
+	ItemNum int32 `json:"itemNum,omitempty" protobuf:"varint,2,opt,name=itemNum"`
+
+	// The num of Items in Scheduling or Scheduled phase.
+	// +optional
+	RunningItemNum int32 `json:"runningItemNum,omitempty" protobuf:"varint,3,opt,name=runningItemNum"`
+
+	// The num of completed Items.
+	// +optional
+	CompletedItemNum int32 `json:"completedItemNum,omitempty" protobuf:"varint,4,opt,name=completedItemNum"`
+
+	// The num of failed Items.
+	// +optional
+	FailedItemNum int32 `json:"failedItemNum,omitempty" protobuf:"varint,5,opt,name=failedItemNum"`
+
+	// Time the first Item of group was dispatched.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty" protobuf:"bytes,6,opt,name=startTime"`
+
+	// Time the group completed or failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty" protobuf:"bytes,7,opt,name=completionTime"`
+}
+
+// TemplateRevision identifies the revision of a template.
+type TemplateRevision struct {
+
+	// Kind of template, JobTemplate or ClusterJobTemplate.
+	// +optional
+	Kind string `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
+
+	// Name of template.
+	// +optional
+	Name string `json:"name,omitempty" protobuf:"bytes,2,opt,name=name"`
+
+	// Generation of template while rendered.
+	// +optional
+	Generation int64 `json:"generation,omitempty" protobuf:"varint,3,opt,name=generation"`
 }
 
 // Item defines the specific execution process of Job
@@ -97,9 +339,215 @@ type Item struct {
 	// +optional
 	ItemJobs ItemJobResource `json:"itemJobs,omitempty" protobuf:"bytes,4,opt,name=ItemJobs"`
 
-	// ItemModules defines the modules in this Item, including service, configmap and secret.
+	// ItemModules defines the modules in this Item, including service, configmap, secret, pvc, pv, service account,
+	// role, role binding, network policy, ingress and pod disruption budget.
+	// Jobs and modules of Items refer to the generated names of modules by "$(modules.<item>.<kind>.<name>.name)",
+	// like "$(modules.a.services.db.name)", and to the generated names of jobs by "$(items.<item>.jobs.<name>.name)".
 	// +optional
 	ItemModules ItemModuleResource `json:"itemModules,omitempty" protobuf:"bytes,5,opt,name=itemModules"`
+
+	// Annotations of the Item, read by songf itself. For example, songf.sh/item-priority
+	// is used by the Priority scheduling policy.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty" protobuf:"bytes,6,rep,name=annotations"`
+
+	// CoScheduleGroup is the name of the group this Item co-scheduled with. Items in the same group
+	// are dispatched together once all of them are ready, and a volcano PodGroup spanning the pods of
+	// their kube jobs is created, so that these pods are gang scheduled by volcano.
+	// Items in the group can only run kube jobs and scripts, and can not depend on each other.
+	// Members finished are not waited for, so that a retried member is dispatched with the unfinished ones.
+	// +optional
+	CoScheduleGroup string `json:"coScheduleGroup,omitempty" protobuf:"bytes,7,opt,name=coScheduleGroup"`
+
+	// SubJob defines a child songf Job run by this Item, owned by the Job of this Item.
+	// The Item completes or fails with the child Job, and takes the outputs of the child Job as its outputs.
+	// +optional
+	SubJob *SubJobTemplate `json:"subJob,omitempty" protobuf:"bytes,8,opt,name=subJob"`
+
+	// Loop reruns the jobs and sub job of this Item until the condition is true or max iterations reached.
+	// Jobs of every iteration are named with the index of iteration as suffix, modules are shared by all iterations.
+	// +optional
+	Loop *ItemLoop `json:"loop,omitempty" protobuf:"bytes,9,opt,name=loop"`
+
+	// DependsOnJobs are other songf Jobs this Item waits for, besides the Items in RunAfter.
+	// The Item fails if a depended Job found before is deleted, or if it is not created in 10 minutes
+	// after this Job was created.
+	// +optional
+	DependsOnJobs []JobDependency `json:"dependsOnJobs,omitempty" protobuf:"bytes,10,rep,name=dependsOnJobs"`
+
+	// Wait makes this Item a wait Item, which runs no job and completes once the object or endpoint is ready.
+	// +optional
+	Wait *WaitTemplate `json:"wait,omitempty" protobuf:"bytes,11,opt,name=wait"`
+
+	// KueueQueueName overrides the Kueue LocalQueue of Job for this Item.
+	// +optional
+	KueueQueueName string `json:"kueueQueueName,omitempty" protobuf:"bytes,12,opt,name=kueueQueueName"`
+
+	// LongRunning makes this Item a long-running Item, which runs a Deployment or StatefulSet and completes
+	// once it is ready. The workload keeps running until the Items in StopAfter completed or the Job finished.
+	// +optional
+	LongRunning *LongRunningTemplate `json:"longRunning,omitempty" protobuf:"bytes,13,opt,name=longRunning"`
+}
+
+// LongRunningTemplate defines the workload of a long-running Item, exactly one of Deployment and StatefulSet must be set.
+type LongRunningTemplate struct {
+
+	// Name of the workload is "<job>-<item>-<name>".
+	// +optional
+	TemplateBaseInfo `json:",inline"`
+
+	// Deployment to run.
+	// +optional
+	Deployment *appsv1.DeploymentSpec `json:"deployment,omitempty" protobuf:"bytes,1,opt,name=deployment"`
+
+	// StatefulSet to run.
+	// +optional
+	StatefulSet *appsv1.StatefulSetSpec `json:"statefulSet,omitempty" protobuf:"bytes,2,opt,name=statefulSet"`
+
+	// StopAfter are the downstream Items after whose completion the workload is deleted.
+	// If not set, the workload is deleted when the Job finished.
+	// +optional
+	StopAfter []string `json:"stopAfter,omitempty" protobuf:"bytes,3,rep,name=stopAfter"`
+}
+
+// WaitTemplate defines what a wait Item waits for, exactly one of Resource and HTTP must be set.
+type WaitTemplate struct {
+	// Resource waits for a Kubernetes object to satisfy a condition, the creator of Job must be able to get it.
+	// +optional
+	Resource *ResourceWait `json:"resource,omitempty" protobuf:"bytes,1,opt,name=resource"`
+
+	// HTTP waits for an in-cluster HTTP endpoint to return a success code.
+	// +optional
+	HTTP *HTTPWait `json:"http,omitempty" protobuf:"bytes,2,opt,name=http"`
+
+	// TimeoutSeconds fails the Item if it is still waiting after it, not limited if not set.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int64 `json:"timeoutSeconds,omitempty" protobuf:"varint,3,opt,name=timeoutSeconds"`
+
+	// PeriodSeconds is the interval between checks, defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty" protobuf:"varint,4,opt,name=periodSeconds"`
+}
+
+// ResourceWait refers to a namespaced object, and the condition it must satisfy. Operator must be granted
+// get on the kind of object.
+type ResourceWait struct {
+	// API version of the object, e.g. apps/v1.
+	// +kubebuilder:validation:MinLength=1
+	APIVersion string `json:"apiVersion" protobuf:"bytes,1,opt,name=apiVersion"`
+
+	// Kind of the object, e.g. Deployment.
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind" protobuf:"bytes,2,opt,name=kind"`
+
+	// Namespace of the object, defaults to the namespace of Job. An object in another namespace must allow
+	// the namespace of Job by the annotation songf.sh/allowed-namespaces. Cluster scoped objects can not be
+	// waited for.
+	// +optional
+	Namespace string `json:"namespace,omitempty" protobuf:"bytes,3,opt,name=namespace"`
+
+	// Name of the object.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name" protobuf:"bytes,4,opt,name=name"`
+
+	// The condition object must satisfy.
+	StatusRule `json:",inline"`
+}
+
+// WaitCondition is a condition in status.conditions.
+type WaitCondition struct {
+	// Type of condition, e.g. Available.
+	// +kubebuilder:validation:MinLength=1
+	Type string `json:"type" protobuf:"bytes,1,opt,name=type"`
+
+	// Status of condition, defaults to True.
+	// +optional
+	Status string `json:"status,omitempty" protobuf:"bytes,2,opt,name=status"`
+}
+
+// JSONPathWait compares a field of object with a value.
+type JSONPathWait struct {
+	// Path of field in kubectl JSONPath syntax, e.g. {.status.phase}.
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path" protobuf:"bytes,1,opt,name=path"`
+
+	// Value the field must equal.
+	Value string `json:"value" protobuf:"bytes,2,opt,name=value"`
+}
+
+// HTTPWait refers to an HTTP endpoint.
+type HTTPWait struct {
+	// URL of the endpoint, requested with GET. Only Services of cluster can be requested, like
+	// http://<service>.<namespace>.svc:<port>/<path>. A Service in another namespace must allow the namespace
+	// of Job by the annotation songf.sh/allowed-namespaces.
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url" protobuf:"bytes,1,opt,name=url"`
+
+	// SuccessCodes are the status codes taken as success, defaults to 2xx.
+	// +optional
+	SuccessCodes []int32 `json:"successCodes,omitempty" protobuf:"varint,2,rep,name=successCodes"`
+}
+
+// JobDependencyPhase is the phase a depended Job must reach.
+// +kubebuilder:validation:Enum=Completed;Finished
+type JobDependencyPhase string
+
+const (
+	// JobDependencyCompleted waits for the depended Job to complete, Item fails if the Job fails or is deleted.
+	JobDependencyCompleted JobDependencyPhase = "Completed"
+
+	// JobDependencyFinished waits for the depended Job to reach any terminal phase.
+	JobDependencyFinished JobDependencyPhase = "Finished"
+)
+
+// JobDependency refers to a songf Job an Item waits for.
+type JobDependency struct {
+	// Namespace of the depended Job, defaults to the namespace of this Job. A Job in another namespace
+	// must list the namespace of this Job in its songf.sh/allowed-namespaces annotation.
+	// +optional
+	Namespace string `json:"namespace,omitempty" protobuf:"bytes,1,opt,name=namespace"`
+
+	// Name of the depended Job.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name" protobuf:"bytes,2,opt,name=name"`
+
+	// Phase the depended Job must reach, defaults to Completed.
+	// +optional
+	Phase JobDependencyPhase `json:"phase,omitempty" protobuf:"bytes,3,opt,name=phase"`
+}
+
+// ItemLoop defines when a loop Item stops.
+type ItemLoop struct {
+	// Condition is a CEL expression evaluated after every completed iteration, the loop stops once it is true.
+	// Variables "outputs" (map of string to string, the outputs of the iteration) and
+	// "iteration" (int, index of the iteration from 0) can be used in it.
+	// The loop runs until max iterations if it is empty.
+	// +optional
+	Condition string `json:"condition,omitempty" protobuf:"bytes,1,opt,name=condition"`
+
+	// MaxIterations is the max num of iterations, the loop stops after it even if condition is false.
+	// +kubebuilder:validation:Minimum=1
+	MaxIterations int32 `json:"maxIterations" protobuf:"varint,2,opt,name=maxIterations"`
+}
+
+// SubJobTemplate defines the child Job to create in Item.
+// "$(items.<item>.outputs.<name>)" in it is replaced by the output of an Item the Item runs after,
+// so the outputs of parent Items can be passed to the child Job by spec.parameters.
+type SubJobTemplate struct {
+
+	// Name of the child Job is "<job>-<item>-<name>".
+	// +optional
+	TemplateBaseInfo `json:",inline"`
+
+	// Spec of the child Job, which is a JobSpec. Set spec.templateRef and spec.parameters
+	// to render the child Job from a template. What the child Job creates on behalf of its creator, like
+	// roles and unstructured jobs, is checked for the creator of the parent Job.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	Spec runtime.RawExtension `json:"spec" protobuf:"bytes,1,opt,name=spec"`
 }
 
 // ItemJobResource defines the jobs to create in Item
@@ -141,9 +589,33 @@ type ItemModuleResource struct {
 	// pvs to create, names can not be repeated
 	// +optional
 	Pvs []PvTemplate `json:"pvs,omitempty" protobuf:"bytes,6,opt,name=pvs"`
+
+	// service accounts to create, names can not be repeated
+	// +optional
+	ServiceAccounts []ServiceAccountTemplate `json:"serviceAccounts,omitempty" protobuf:"bytes,7,rep,name=serviceAccounts"`
+
+	// roles to create, names can not be repeated
+	// +optional
+	Roles []RoleTemplate `json:"roles,omitempty" protobuf:"bytes,8,rep,name=roles"`
+
+	// role bindings to create, names can not be repeated
+	// +optional
+	RoleBindings []RoleBindingTemplate `json:"roleBindings,omitempty" protobuf:"bytes,9,rep,name=roleBindings"`
+
+	// network policies to create, names can not be repeated
+	// +optional
+	NetworkPolicies []NetworkPolicyTemplate `json:"networkPolicies,omitempty" protobuf:"bytes,10,rep,name=networkPolicies"`
+
+	// ingresses to create, names can not be repeated
+	// +optional
+	Ingresses []IngressTemplate `json:"ingresses,omitempty" protobuf:"bytes,11,rep,name=ingresses"`
+
+	// pod disruption budgets to create, names can not be repeated
+	// +optional
+	PodDisruptionBudgets []PodDisruptionBudgetTemplate `json:"podDisruptionBudgets,omitempty" protobuf:"bytes,12,rep,name=podDisruptionBudgets"`
 }
 
-// ItemJobTemplate defines the jobs to create in Item, detailed information. KubeJobSpec and VolcanoJobSpec only one exist.
+// ItemJobTemplate defines the jobs to create in Item, detailed information. Only one kind of job spec can exist.
 type ItemJobTemplate struct {
 
 	// +optional
@@ -177,6 +649,85 @@ type ItemJobTemplate struct {
 	// Specification of the desired behavior of the volcano job, including the minAvailable
 	// +optional
 	VolcanoJobSpec *v1alpha1.JobSpec `json:"VolcanoJobSpec,omitempty" protobuf:"bytes,5,opt,name=VolcanoJobSpec"`
+
+	// Script is a shorthand of kube job running a script, it is expanded into a kube job while dispatching,
+	// with the script mounted from a ConfigMap created in the modules of Item.
+	// +optional
+	Script *ScriptJobSpec `json:"script,omitempty" protobuf:"bytes,6,opt,name=script"`
+
+	// Unstructured runs an object of any kind as job, the status of object is mapped to the phase of job by rules.
+	// +optional
+	Unstructured *UnstructuredJobSpec `json:"unstructured,omitempty" protobuf:"bytes,7,opt,name=unstructured"`
+
+	// Specification of the desired behavior of the jobset.x-k8s.io JobSet, which is kept as is
+	// so that songf works with the JobSet versions sharing the same replicatedJobs.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +optional
+	JobSetSpec *runtime.RawExtension `json:"jobSetSpec,omitempty" protobuf:"bytes,8,opt,name=jobSetSpec"`
+}
+
+// UnstructuredJobSpec defines an object of any kind run as job, like PyTorchJob, RayJob or SparkApplication.
+type UnstructuredJobSpec struct {
+	// Manifest of the object, apiVersion and kind are required, name and namespace are set by songf.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Manifest runtime.RawExtension `json:"manifest" protobuf:"bytes,1,opt,name=manifest"`
+
+	// SuccessRule decides whether the job completed.
+	SuccessRule StatusRule `json:"successRule" protobuf:"bytes,2,opt,name=successRule"`
+
+	// FailureRule decides whether the job failed, it is checked before SuccessRule.
+	// +optional
+	FailureRule *StatusRule `json:"failureRule,omitempty" protobuf:"bytes,3,opt,name=failureRule"`
+
+	// RunningRule decides whether the job is running, the job is pending before it is true.
+	// The job is running once created if not set.
+	// +optional
+	RunningRule *StatusRule `json:"runningRule,omitempty" protobuf:"bytes,4,opt,name=runningRule"`
+}
+
+// StatusRule is a condition over the status of object, exactly one of Condition, JSONPath and Expression must be set.
+type StatusRule struct {
+	// Condition is satisfied by a condition in status.conditions of the object.
+	// +optional
+	Condition *WaitCondition `json:"condition,omitempty" protobuf:"bytes,1,opt,name=condition"`
+
+	// JSONPath is satisfied if a field of the object equals a value.
+	// +optional
+	JSONPath *JSONPathWait `json:"jsonPath,omitempty" protobuf:"bytes,2,opt,name=jsonPath"`
+
+	// Expression is satisfied if a CEL expression over the object is true, the object is the variable "object".
+	// +optional
+	Expression string `json:"expression,omitempty" protobuf:"bytes,3,opt,name=expression"`
+}
+
+// ScriptJobSpec defines a kube job running a script in one pod.
+type ScriptJobSpec struct {
+	// Image to run the script in.
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image" protobuf:"bytes,1,opt,name=image"`
+
+	// Source of the script.
+	// +kubebuilder:validation:MinLength=1
+	Source string `json:"source" protobuf:"bytes,2,opt,name=source"`
+
+	// Command to run the script file with, the path of script file is appended to it, defaults to ["sh"].
+	// +optional
+	Command []string `json:"command,omitempty" protobuf:"bytes,3,rep,name=command"`
+
+	// Env of the script container.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty" protobuf:"bytes,4,rep,name=env"`
+
+	// Resources of the script container.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty" protobuf:"bytes,5,opt,name=resources"`
+
+	// The num of retries of the kube job, defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty" protobuf:"varint,6,opt,name=backoffLimit"`
 }
 
 // ServiceTemplate defines the service to create in Item, detailed information.
@@ -216,6 +767,11 @@ type ConfigMapTemplate struct {
 
 	// +optional
 	ConfigMap corev1.ConfigMap `json:"configMap,omitempty" protobuf:"bytes,2,opt,name=configMap"`
+
+	// ValueFrom copies the data of existing ConfigMaps when Item is created, merged in order,
+	// and the data in ConfigMap wins over them.
+	// +optional
+	ValueFrom []ModuleValueSource `json:"valueFrom,omitempty" protobuf:"bytes,3,rep,name=valueFrom"`
 }
 
 // SecretTemplate defines the secret to create in Item, detailed information.
@@ -235,6 +791,37 @@ type SecretTemplate struct {
 
 	// +optional
 	Secret corev1.Secret `json:"secret,omitempty" protobuf:"bytes,2,opt,name=secret"`
+
+	// ValueFrom copies the data of existing Secrets when Item is created, merged in order,
+	// and the data in Secret wins over them. So that the data is not stored in Job.
+	// +optional
+	ValueFrom []ModuleValueSource `json:"valueFrom,omitempty" protobuf:"bytes,3,rep,name=valueFrom"`
+
+	// EncryptedData are the values sealed by "songf seal" with the public key of operator for the namespace
+	// of Job and the name of this template, key is data key. They are decrypted by operator when Item is created,
+	// and win over the data in Secret and ValueFrom.
+	// +optional
+	EncryptedData map[string]string `json:"encryptedData,omitempty" protobuf:"bytes,4,rep,name=encryptedData"`
+}
+
+// ModuleValueSource refers to an existing ConfigMap or Secret whose data is copied into a module.
+type ModuleValueSource struct {
+
+	// Name of the source.
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+
+	// Namespace of the source, the namespace of Job if not set. A source in another namespace must allow
+	// the namespace of Job by the annotation songf.sh/allowed-namespaces.
+	// +optional
+	Namespace string `json:"namespace,omitempty" protobuf:"bytes,2,opt,name=namespace"`
+
+	// Keys to copy, all keys are copied if not set.
+	// +optional
+	Keys []string `json:"keys,omitempty" protobuf:"bytes,3,rep,name=keys"`
+
+	// Optional ignores the source and the keys not found, otherwise Item fails to be created.
+	// +optional
+	Optional bool `json:"optional,omitempty" protobuf:"varint,4,opt,name=optional"`
 }
 
 // PvcTemplate defines the pvc to create in Item, detailed information.
@@ -275,6 +862,129 @@ type PvTemplate struct {
 	Pv corev1.PersistentVolumeSpec `json:"pvc,omitempty" protobuf:"bytes,2,opt,name=pv"`
 }
 
+// ServiceAccountTemplate defines the service account to create in Item, detailed information.
+type ServiceAccountTemplate struct {
+
+	// +optional
+	TemplateBaseInfo `json:",inline"`
+
+	// ttlSecondsAfterFinished limits the lifetime of a Job that has finished
+	// execution (either Completed or Failed). If this field is set,
+	// ttlSecondsAfterFinished after the Job finishes, this service account will be
+	// automatically deleted. If this field is unset,
+	// the Job won't be automatically deleted. If this field is set to zero,
+	// the Job becomes eligible to be deleted immediately after it finishes.
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty" protobuf:"varint,1,opt,name=ttlSecondsAfterFinished"`
+
+	// +optional
+	ServiceAccount corev1.ServiceAccount `json:"serviceAccount,omitempty" protobuf:"bytes,2,opt,name=serviceAccount"`
+}
+
+// RoleTemplate defines the role to create in Item, detailed information.
+type RoleTemplate struct {
+
+	// +optional
+	TemplateBaseInfo `json:",inline"`
+
+	// ttlSecondsAfterFinished limits the lifetime of a Job that has finished
+	// execution (either Completed or Failed). If this field is set,
+	// ttlSecondsAfterFinished after the Job finishes, this role will be
+	// automatically deleted. If this field is unset,
+	// the Job won't be automatically deleted. If this field is set to zero,
+	// the Job becomes eligible to be deleted immediately after it finishes.
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty" protobuf:"varint,1,opt,name=ttlSecondsAfterFinished"`
+
+	// Rules of the role, the creator of Job must be granted all of them.
+	// +optional
+	Rules []rbacv1.PolicyRule `json:"rules,omitempty" protobuf:"bytes,2,rep,name=rules"`
+}
+
+// RoleBindingTemplate defines the role binding to create in Item, detailed information.
+// A role named as a role of the Item and a service account without namespace named as a service account
+// of the Item refer to the ones created by the Item. Role bindings can only refer to the roles of the Item
+// and the cluster roles allowed by operator, and the creator of Job must be able to bind them.
+type RoleBindingTemplate struct {
+
+	// +optional
+	TemplateBaseInfo `json:",inline"`
+
+	// ttlSecondsAfterFinished limits the lifetime of a Job that has finished
+	// execution (either Completed or Failed). If this field is set,
+	// ttlSecondsAfterFinished after the Job finishes, this role binding will be
+	// automatically deleted. If this field is unset,
+	// the Job won't be automatically deleted. If this field is set to zero,
+	// the Job becomes eligible to be deleted immediately after it finishes.
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty" protobuf:"varint,1,opt,name=ttlSecondsAfterFinished"`
+
+	// RoleRef of the role binding.
+	RoleRef rbacv1.RoleRef `json:"roleRef" protobuf:"bytes,2,opt,name=roleRef"`
+
+	// Subjects of the role binding.
+	// +optional
+	Subjects []rbacv1.Subject `json:"subjects,omitempty" protobuf:"bytes,3,rep,name=subjects"`
+}
+
+// NetworkPolicyTemplate defines the network policy to create in Item, detailed information.
+type NetworkPolicyTemplate struct {
+
+	// +optional
+	TemplateBaseInfo `json:",inline"`
+
+	// ttlSecondsAfterFinished limits the lifetime of a Job that has finished
+	// execution (either Completed or Failed). If this field is set,
+	// ttlSecondsAfterFinished after the Job finishes, this network policy will be
+	// automatically deleted. If this field is unset,
+	// the Job won't be automatically deleted. If this field is set to zero,
+	// the Job becomes eligible to be deleted immediately after it finishes.
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty" protobuf:"varint,1,opt,name=ttlSecondsAfterFinished"`
+
+	// +optional
+	Spec networkingv1.NetworkPolicySpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// IngressTemplate defines the ingress to create in Item, detailed information.
+// A backend service named as a service of the Item refers to the one created by the Item.
+type IngressTemplate struct {
+
+	// +optional
+	TemplateBaseInfo `json:",inline"`
+
+	// ttlSecondsAfterFinished limits the lifetime of a Job that has finished
+	// execution (either Completed or Failed). If this field is set,
+	// ttlSecondsAfterFinished after the Job finishes, this ingress will be
+	// automatically deleted. If this field is unset,
+	// the Job won't be automatically deleted. If this field is set to zero,
+	// the Job becomes eligible to be deleted immediately after it finishes.
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty" protobuf:"varint,1,opt,name=ttlSecondsAfterFinished"`
+
+	// +optional
+	Spec networkingv1.IngressSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// PodDisruptionBudgetTemplate defines the pod disruption budget to create in Item, detailed information.
+type PodDisruptionBudgetTemplate struct {
+
+	// +optional
+	TemplateBaseInfo `json:",inline"`
+
+	// ttlSecondsAfterFinished limits the lifetime of a Job that has finished
+	// execution (either Completed or Failed). If this field is set,
+	// ttlSecondsAfterFinished after the Job finishes, this pod disruption budget will be
+	// automatically deleted. If this field is unset,
+	// the Job won't be automatically deleted. If this field is set to zero,
+	// the Job becomes eligible to be deleted immediately after it finishes.
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty" protobuf:"varint,1,opt,name=ttlSecondsAfterFinished"`
+
+	// +optional
+	Spec policyv1.PodDisruptionBudgetSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
 type TemplateBaseInfo struct {
 	// +optional
 	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
@@ -291,6 +1001,7 @@ type JobPhase string
 
 const (
 	Unknown     JobPhase = "Unknown"
+	Queued      JobPhase = "Queued"
 	Scheduled   JobPhase = "Scheduled"
 	Completed   JobPhase = "Completed"
 	Failed      JobPhase = "Failed"
@@ -339,6 +1050,14 @@ type ItemStatus struct {
 	// +optional
 	Phase ItemPhase `json:"phase,omitempty" protobuf:"bytes,2,opt,name=phase"`
 
+	// Unique, one-word, CamelCase reason for the Item staying in its phase, e.g. why a ready Item is Pending.
+	// +optional
+	Reason string `json:"reason,omitempty" protobuf:"bytes,12,opt,name=reason"`
+
+	// Human-readable message indicating details about the reason.
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,13,opt,name=message"`
+
 	// The num of Job which is running.
 	// +optional
 	RunningJobNum *int32 `json:"runningJobNum,omitempty" protobuf:"bytes,3,opt,name=runningJobNum"`
@@ -351,6 +1070,36 @@ type ItemStatus struct {
 	// +optional
 	FailedJobNum *int32 `json:"failedJobNum,omitempty" protobuf:"bytes,5,opt,name=failedJobNum"`
 
+	// Time the Item was dispatched.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty" protobuf:"bytes,10,opt,name=startTime"`
+
+	// Time the Item completed or failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty" protobuf:"bytes,11,opt,name=completionTime"`
+
+	// Outputs of Item, key is output name. Outputs come from the outputs of sub job, and from
+	// the termination messages of the succeeded pods of jobs, which are json objects of strings.
+	// Item fails with reason OutputNotCollected if outputs referenced by the Job can not be read.
+	// +optional
+	Outputs map[string]string `json:"outputs,omitempty" protobuf:"bytes,14,rep,name=outputs"`
+
+	// The status summary of sub job.
+	// +optional
+	SubJobStatus *SubJobStatus `json:"subJobStatus,omitempty" protobuf:"bytes,15,opt,name=subJobStatus"`
+
+	// The num of retries of Item, set by the retry policy of group.
+	// +optional
+	RetryCount int32 `json:"retryCount,omitempty" protobuf:"varint,16,opt,name=retryCount"`
+
+	// The index of current iteration of loop Item, from 0.
+	// +optional
+	Iteration int32 `json:"iteration,omitempty" protobuf:"varint,17,opt,name=iteration"`
+
+	// The status of iterations of loop Item.
+	// +optional
+	Iterations []ItemIterationStatus `json:"iterations,omitempty" protobuf:"bytes,18,rep,name=iterations"`
+
 	// The status of volcano job, key is job name.
 	// +optional
 	JobStatus map[string]v1alpha1.JobState `json:"jobStatus,omitempty" protobuf:"bytes,6,opt,name=jobStatus"`
@@ -372,6 +1121,79 @@ type ItemStatus struct {
 
 	// +optional
 	PvStatus map[string]RegularModuleStatus `json:"pvStatus,omitempty" protobuf:"bytes,9,opt,name=pvStatus"`
+
+	// The status of service account, key is service account name.
+	// +optional
+	ServiceAccountStatus map[string]RegularModuleStatus `json:"serviceAccountStatus,omitempty" protobuf:"bytes,19,rep,name=serviceAccountStatus"`
+
+	// The status of role, key is role name.
+	// +optional
+	RoleStatus map[string]RegularModuleStatus `json:"roleStatus,omitempty" protobuf:"bytes,20,rep,name=roleStatus"`
+
+	// The status of role binding, key is role binding name.
+	// +optional
+	RoleBindingStatus map[string]RegularModuleStatus `json:"roleBindingStatus,omitempty" protobuf:"bytes,21,rep,name=roleBindingStatus"`
+
+	// The status of network policy, key is network policy name.
+	// +optional
+	NetworkPolicyStatus map[string]RegularModuleStatus `json:"networkPolicyStatus,omitempty" protobuf:"bytes,22,rep,name=networkPolicyStatus"`
+
+	// The status of ingress, key is ingress name.
+	// +optional
+	IngressStatus map[string]RegularModuleStatus `json:"ingressStatus,omitempty" protobuf:"bytes,23,rep,name=ingressStatus"`
+
+	// The status of pod disruption budget, key is pod disruption budget name.
+	// +optional
+	PodDisruptionBudgetStatus map[string]RegularModuleStatus `json:"podDisruptionBudgetStatus,omitempty" protobuf:"bytes,24,rep,name=podDisruptionBudgetStatus"`
+}
+
+// ItemIterationStatus records an iteration of loop Item.
+type ItemIterationStatus struct {
+	// The index of iteration, from 0.
+	Iteration int32 `json:"iteration" protobuf:"varint,1,opt,name=iteration"`
+
+	// The phase of Item in this iteration.
+	// +optional
+	Phase ItemPhase `json:"phase,omitempty" protobuf:"bytes,2,opt,name=phase"`
+
+	// Represents time when the iteration was started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty" protobuf:"bytes,3,opt,name=startTime"`
+
+	// Represents time when the iteration was completed or failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty" protobuf:"bytes,4,opt,name=completionTime"`
+
+	// The outputs of the iteration.
+	// +optional
+	Outputs map[string]string `json:"outputs,omitempty" protobuf:"bytes,5,rep,name=outputs"`
+}
+
+// SubJobStatus summarizes the status of a child Job.
+type SubJobStatus struct {
+	// The name of child Job.
+	// +optional
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+
+	// Current state of child Job.
+	// +optional
+	State JobState `json:"state,omitempty" protobuf:"bytes,2,opt,name=state"`
+
+	// The num of Items in child Job.
+	// +optional
+	ItemNum int32 `json:"itemNum,omitempty" protobuf:"varint,3,opt,name=itemNum"`
+
+	// The num of Items in Scheduling or Scheduled phase.
+	// +optional
+	RunningItemNum int32 `json:"runningItemNum,omitempty" protobuf:"varint,4,opt,name=runningItemNum"`
+
+	// The num of completed Items.
+	// +optional
+	CompletedItemNum int32 `json:"completedItemNum,omitempty" protobuf:"varint,5,opt,name=completedItemNum"`
+
+	// The num of failed Items.
+	// +optional
+	FailedItemNum int32 `json:"failedItemNum,omitempty" protobuf:"varint,6,opt,name=failedItemNum"`
 }
 
 // RegularModulePhase defines the phase of regular module.
@@ -0,0 +1,187 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"strings"
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+// GetUnstructuredJobObject returns the object defined by the manifest of unstructured job.
+func GetUnstructuredJobObject(spec *UnstructuredJobSpec) (*unstructured.Unstructured, error) {
+	if len(spec.Manifest.Raw) == 0 {
+		return nil, fmt.Errorf("manifest can not be nil")
+	}
+
+	object := &unstructured.Unstructured{}
+	if err := json.Unmarshal(spec.Manifest.Raw, &object.Object); err != nil {
+		return nil, fmt.Errorf("unmarshal manifest err: %s", err.Error())
+	}
+
+	if object.GetAPIVersion() == "" || object.GetKind() == "" {
+		return nil, fmt.Errorf("apiVersion and kind of manifest can not be nil")
+	}
+
+	return object, nil
+}
+
+// isUnstructuredJobValid checks the manifest and rules of unstructured job.
+func isUnstructuredJobValid(spec *UnstructuredJobSpec) error {
+	if _, err := GetUnstructuredJobObject(spec); err != nil {
+		return err
+	}
+
+	if err := isStatusRuleValid(&spec.SuccessRule); err != nil {
+		return fmt.Errorf("success rule %s", err.Error())
+	}
+	if spec.FailureRule != nil {
+		if err := isStatusRuleValid(spec.FailureRule); err != nil {
+			return fmt.Errorf("failure rule %s", err.Error())
+		}
+	}
+	if spec.RunningRule != nil {
+		if err := isStatusRuleValid(spec.RunningRule); err != nil {
+			return fmt.Errorf("running rule %s", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// CalUnstructuredJobState maps the status of object to the state of job by the rules of unstructured job.
+func CalUnstructuredJobState(spec *UnstructuredJobSpec, object *unstructured.Unstructured) v1alpha1.JobState {
+
+	if spec.FailureRule != nil {
+		failed, _, err := CheckStatusRule(spec.FailureRule, object)
+		if err != nil {
+			return v1alpha1.JobState{Phase: v1alpha1.Failed, Reason: "RuleError", Message: err.Error()}
+		}
+		if failed {
+			return v1alpha1.JobState{Phase: v1alpha1.Failed, Reason: "FailureRule"}
+		}
+	}
+
+	completed, message, err := CheckStatusRule(&spec.SuccessRule, object)
+	if err != nil {
+		return v1alpha1.JobState{Phase: v1alpha1.Failed, Reason: "RuleError", Message: err.Error()}
+	}
+	if completed {
+		return v1alpha1.JobState{Phase: v1alpha1.Completed, Reason: "SuccessRule"}
+	}
+
+	if spec.RunningRule != nil {
+		running, message, err := CheckStatusRule(spec.RunningRule, object)
+		if err != nil {
+			return v1alpha1.JobState{Phase: v1alpha1.Failed, Reason: "RuleError", Message: err.Error()}
+		}
+		if !running {
+			return v1alpha1.JobState{Phase: v1alpha1.Pending, Message: message}
+		}
+	}
+
+	return v1alpha1.JobState{Phase: v1alpha1.Running, Message: message}
+}
+
+// GetUnstructuredJobGVKs returns the kinds of unstructured jobs in job, without repeat.
+func GetUnstructuredJobGVKs(job *Job) []schema.GroupVersionKind {
+	var res []schema.GroupVersionKind
+	found := map[schema.GroupVersionKind]bool{}
+
+	for _, item := range CalJobItems(job) {
+		for _, itemJob := range item.ItemJobs.Jobs {
+			if itemJob.Unstructured == nil {
+				continue
+			}
+
+			object, err := GetUnstructuredJobObject(itemJob.Unstructured)
+			if err != nil {
+				continue
+			}
+
+			gvk := object.GroupVersionKind()
+			if !found[gvk] {
+				found[gvk] = true
+				res = append(res, gvk)
+			}
+		}
+	}
+
+	return res
+}
+
+// ParseUnstructuredJobKinds parses the kinds unstructured jobs are allowed to create, which are written as
+// <apiVersion>/<kind>, like "kubeflow.org/v1/PyTorchJob" or "v1/Pod".
+func ParseUnstructuredJobKinds(kinds []string) ([]schema.GroupVersionKind, error) {
+	var res []schema.GroupVersionKind
+
+	for _, kind := range kinds {
+		i := strings.LastIndex(kind, "/")
+		if i <= 0 || i == len(kind)-1 {
+			return nil, fmt.Errorf("kind %s is not <apiVersion>/<kind>", kind)
+		}
+
+		res = append(res, schema.FromAPIVersionAndKind(kind[:i], kind[i+1:]))
+	}
+
+	return res, nil
+}
+
+// IsUnstructuredJobKindAllowed checks that objects of kind can be created by unstructured jobs. Only the namespaced
+// kinds in allowed can be created, since objects are created by operator on behalf of the creators of Jobs.
+func IsUnstructuredJobKindAllowed(gvk schema.GroupVersionKind, allowed []schema.GroupVersionKind, mapper meta.RESTMapper) (bool, string) {
+	found := false
+	for _, kind := range allowed {
+		if kind == gvk {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false, fmt.Sprintf("kind %s is not allowed for unstructured jobs", gvk.String())
+	}
+
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, fmt.Sprintf("kind %s of unstructured jobs not found: %s", gvk.String(), err.Error())
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return false, fmt.Sprintf("kind %s of unstructured jobs is not namespaced", gvk.String())
+	}
+
+	return true, ""
+}
+
+// IsItemUnstructuredJobAllowed checks the kinds of unstructured jobs in item.
+func IsItemUnstructuredJobAllowed(item *Item, allowed []schema.GroupVersionKind, mapper meta.RESTMapper) (bool, string) {
+	for _, itemJob := range item.ItemJobs.Jobs {
+		if itemJob.Unstructured == nil {
+			continue
+		}
+
+		object, err := GetUnstructuredJobObject(itemJob.Unstructured)
+		if err != nil {
+			return false, fmt.Sprintf("item %s unstructured job %s %s", item.Name, itemJob.Name, err.Error())
+		}
+
+		if flag, msg := IsUnstructuredJobKindAllowed(object.GroupVersionKind(), allowed, mapper); !flag {
+			return false, fmt.Sprintf("item %s unstructured job %s: %s", item.Name, itemJob.Name, msg)
+		}
+	}
+
+	return true, ""
+}
+
+// IsJobUnstructuredJobAllowed checks the kinds of unstructured jobs in all items of job.
+func IsJobUnstructuredJobAllowed(job *Job, allowed []schema.GroupVersionKind, mapper meta.RESTMapper) (bool, string) {
+	items := CalJobItems(job)
+	for i := range items {
+		if flag, msg := IsItemUnstructuredJobAllowed(&items[i], allowed, mapper); !flag {
+			return false, msg
+		}
+	}
+
+	return true, ""
+}
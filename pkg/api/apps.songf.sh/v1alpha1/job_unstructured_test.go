@@ -0,0 +1,183 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"testing"
+	"volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func TestParseUnstructuredJobKinds(t *testing.T) {
+	tests := []struct {
+		kinds    []string
+		expected []schema.GroupVersionKind
+		valid    bool
+	}{
+		{kinds: nil, valid: true},
+		{
+			kinds: []string{"kubeflow.org/v1/PyTorchJob", "v1/Pod"},
+			expected: []schema.GroupVersionKind{
+				{Group: "kubeflow.org", Version: "v1", Kind: "PyTorchJob"},
+				{Version: "v1", Kind: "Pod"},
+			},
+			valid: true,
+		},
+		{kinds: []string{"Pod"}},
+		{kinds: []string{"v1/"}},
+		{kinds: []string{"/Pod"}},
+	}
+
+	for _, test := range tests {
+		res, err := ParseUnstructuredJobKinds(test.kinds)
+		if (err == nil) != test.valid {
+			t.Errorf("%v: expected valid %t, got err %v", test.kinds, test.valid, err)
+			continue
+		}
+		if len(res) != len(test.expected) {
+			t.Errorf("%v: expected %v, got %v", test.kinds, test.expected, res)
+			continue
+		}
+		for i := range res {
+			if res[i] != test.expected[i] {
+				t.Errorf("%v: expected %v, got %v", test.kinds, test.expected, res)
+			}
+		}
+	}
+}
+
+func TestIsUnstructuredJobKindAllowed(t *testing.T) {
+	pytorchJob := schema.GroupVersionKind{Group: "kubeflow.org", Version: "v1", Kind: "PyTorchJob"}
+	clusterRoleBinding := schema.GroupVersionKind{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRoleBinding"}
+	tfJob := schema.GroupVersionKind{Group: "kubeflow.org", Version: "v1", Kind: "TFJob"}
+	unknown := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Unknown"}
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(pytorchJob, meta.RESTScopeNamespace)
+	mapper.Add(tfJob, meta.RESTScopeNamespace)
+	mapper.Add(clusterRoleBinding, meta.RESTScopeRoot)
+
+	allowed := []schema.GroupVersionKind{pytorchJob, clusterRoleBinding, unknown}
+
+	tests := []struct {
+		gvk     schema.GroupVersionKind
+		allowed bool
+	}{
+		{gvk: pytorchJob, allowed: true},
+		// cluster scoped kinds are rejected even if allowed
+		{gvk: clusterRoleBinding},
+		// namespaced kinds not allowed
+		{gvk: tfJob},
+		// kinds not served
+		{gvk: unknown},
+	}
+
+	for _, test := range tests {
+		if flag, msg := IsUnstructuredJobKindAllowed(test.gvk, allowed, mapper); flag != test.allowed {
+			t.Errorf("%s: expected allowed %t, got %t: %s", test.gvk.String(), test.allowed, flag, msg)
+		}
+	}
+
+	job := &Job{Spec: JobSpec{Items: []Item{{
+		Name: "a",
+		ItemJobs: ItemJobResource{Jobs: []ItemJobTemplate{{
+			TemplateBaseInfo: TemplateBaseInfo{Name: "binding"},
+			Unstructured: &UnstructuredJobSpec{
+				Manifest: runtime.RawExtension{
+					Raw: []byte(`{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRoleBinding"}`),
+				},
+			},
+		}}},
+	}}}}
+	if flag, _ := IsJobUnstructuredJobAllowed(job, allowed, mapper); flag {
+		t.Errorf("job creating cluster role binding should be rejected")
+	}
+
+	job.Spec.Items[0].ItemJobs.Jobs[0].Unstructured.Manifest.Raw = []byte(`{"apiVersion":"kubeflow.org/v1","kind":"PyTorchJob"}`)
+	if flag, msg := IsJobUnstructuredJobAllowed(job, allowed, mapper); !flag {
+		t.Errorf("job creating pytorch job should be allowed: %s", msg)
+	}
+}
+
+func TestCalUnstructuredJobState(t *testing.T) {
+	spec := &UnstructuredJobSpec{
+		SuccessRule: StatusRule{Condition: &WaitCondition{Type: "Succeeded"}},
+		FailureRule: &StatusRule{JSONPath: &JSONPathWait{Path: "{.status.phase}", Value: "Failed"}},
+		RunningRule: &StatusRule{Expression: `object.status.active > 0`},
+	}
+
+	newObject := func(phase string, active int64, conditions ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"status": map[string]interface{}{"phase": phase, "active": active, "conditions": conditions},
+		}}
+	}
+	succeeded := map[string]interface{}{"type": "Succeeded", "status": "True"}
+
+	tests := []struct {
+		name   string
+		spec   *UnstructuredJobSpec
+		object *unstructured.Unstructured
+		phase  v1alpha1.JobPhase
+		reason string
+	}{
+		{name: "pending", spec: spec, object: newObject("Created", 0), phase: v1alpha1.Pending},
+		{name: "running", spec: spec, object: newObject("Running", 2), phase: v1alpha1.Running},
+		{name: "completed", spec: spec, object: newObject("Done", 0, succeeded), phase: v1alpha1.Completed, reason: "SuccessRule"},
+		// failure rule is checked before success rule
+		{name: "failed", spec: spec, object: newObject("Failed", 0, succeeded), phase: v1alpha1.Failed, reason: "FailureRule"},
+		{
+			name:   "running without running rule",
+			spec:   &UnstructuredJobSpec{SuccessRule: spec.SuccessRule},
+			object: newObject("Created", 0),
+			phase:  v1alpha1.Running,
+		},
+		{
+			name:   "rule error",
+			spec:   &UnstructuredJobSpec{SuccessRule: StatusRule{Expression: `object.status.phase`}},
+			object: newObject("Created", 0),
+			phase:  v1alpha1.Failed,
+			reason: "RuleError",
+		},
+	}
+
+	for _, test := range tests {
+		state := CalUnstructuredJobState(test.spec, test.object)
+		if state.Phase != test.phase || state.Reason != test.reason {
+			t.Errorf("%s: expected %s %s, got %s %s %s", test.name, test.phase, test.reason, state.Phase, state.Reason, state.Message)
+		}
+	}
+}
+
+func TestIsUnstructuredJobValid(t *testing.T) {
+	manifest := runtime.RawExtension{Raw: []byte(`{"apiVersion":"kubeflow.org/v1","kind":"PyTorchJob"}`)}
+	success := StatusRule{Condition: &WaitCondition{Type: "Succeeded"}}
+
+	tests := []struct {
+		name  string
+		spec  *UnstructuredJobSpec
+		valid bool
+	}{
+		{name: "valid", spec: &UnstructuredJobSpec{Manifest: manifest, SuccessRule: success}, valid: true},
+		{name: "manifest nil", spec: &UnstructuredJobSpec{SuccessRule: success}},
+		{
+			name: "kind nil",
+			spec: &UnstructuredJobSpec{Manifest: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1"}`)}, SuccessRule: success},
+		},
+		{name: "success rule nil", spec: &UnstructuredJobSpec{Manifest: manifest}},
+		{
+			name: "failure rule invalid",
+			spec: &UnstructuredJobSpec{Manifest: manifest, SuccessRule: success, FailureRule: &StatusRule{Expression: "object."}},
+		},
+		{
+			name: "running rule invalid",
+			spec: &UnstructuredJobSpec{Manifest: manifest, SuccessRule: success, RunningRule: &StatusRule{}},
+		},
+	}
+
+	for _, test := range tests {
+		if err := isUnstructuredJobValid(test.spec); (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %t, got err %v", test.name, test.valid, err)
+		}
+	}
+}
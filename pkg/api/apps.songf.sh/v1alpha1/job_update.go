@@ -0,0 +1,102 @@
+package v1alpha1
+
+import (
+	"fmt"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+)
+
+// isItemStarted returns true if item was dispatched at least once.
+func isItemStarted(job *Job, itemName string) bool {
+	status, ok := job.Status.ItemStatus[itemName]
+	if !ok {
+		return false
+	}
+
+	return status.Phase != ItemPending || status.RetryCount > 0 || status.Iteration > 0
+}
+
+// isRunAfterAppended returns true if newRunAfter keeps all parents of oldRunAfter.
+func isRunAfterAppended(oldRunAfter, newRunAfter []string) bool {
+	parents := map[string]bool{}
+	for _, name := range newRunAfter {
+		parents[name] = true
+	}
+
+	for _, name := range oldRunAfter {
+		if !parents[name] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsJobUpdateValid checks the update of running job. Items can be added, parents can be appended to
+// the items not started yet and their Truncated can be toggled, parallelism can be raised.
+// Items started or finished can not be modified, and other fields of spec can not be changed.
+func IsJobUpdateValid(oldJob, newJob *Job) (bool, string) {
+
+	switch oldJob.Status.State.Phase {
+	case Completing, Completed, Failed, Terminating, Terminated:
+		return false, fmt.Sprintf("job %s is %s, spec can not be changed", oldJob.Name, oldJob.Status.State.Phase)
+	}
+
+	// nil parallelism means not limited
+	if newJob.Spec.Parallelism != nil &&
+		(oldJob.Spec.Parallelism == nil || *newJob.Spec.Parallelism < *oldJob.Spec.Parallelism) {
+		return false, "parallelism can only be raised"
+	}
+
+	// fields other than items, groups and parallelism
+	oldSpec, newSpec := oldJob.Spec.DeepCopy(), newJob.Spec.DeepCopy()
+	oldSpec.Items, newSpec.Items = nil, nil
+	oldSpec.Parallelism, newSpec.Parallelism = nil, nil
+	for i := range oldSpec.Groups {
+		oldSpec.Groups[i].Items = nil
+	}
+	for i := range newSpec.Groups {
+		newSpec.Groups[i].Items = nil
+	}
+
+	// new groups can be added after the old ones
+	if len(newSpec.Groups) >= len(oldSpec.Groups) {
+		newSpec.Groups = newSpec.Groups[:len(oldSpec.Groups)]
+	}
+
+	if !apiequality.Semantic.DeepEqual(oldSpec, newSpec) {
+		return false, "job updates may only add items, append parents to or truncate items not started, and raise parallelism"
+	}
+
+	newItems := map[string]*Item{}
+	for _, item := range CalJobItems(newJob) {
+		newItems[item.Name] = item.DeepCopy()
+	}
+
+	for _, oldItem := range CalJobItems(oldJob) {
+		newItem, ok := newItems[oldItem.Name]
+		if !ok {
+			return false, fmt.Sprintf("item %s can not be removed", oldItem.Name)
+		}
+
+		if apiequality.Semantic.DeepEqual(&oldItem, newItem) {
+			continue
+		}
+
+		if isItemStarted(oldJob, oldItem.Name) {
+			return false, fmt.Sprintf("item %s was started, it can not be modified", oldItem.Name)
+		}
+
+		if !isRunAfterAppended(oldItem.RunAfter, newItem.RunAfter) {
+			return false, fmt.Sprintf("parents of item %s can only be appended", oldItem.Name)
+		}
+
+		// besides parents and truncated, item can not be modified
+		oldItem.RunAfter, newItem.RunAfter = nil, nil
+		oldItem.Truncated, newItem.Truncated = nil, nil
+		if !apiequality.Semantic.DeepEqual(&oldItem, newItem) {
+			return false, fmt.Sprintf("item %s can only append parents or change truncated", oldItem.Name)
+		}
+	}
+
+	return true, ""
+}
@@ -0,0 +1,110 @@
+package v1alpha1
+
+import (
+	"testing"
+)
+
+func TestIsJobUpdateValid(t *testing.T) {
+	truncated := true
+	one, two := int32(1), int32(2)
+
+	newJob := func(parallelism *int32, items ...Item) *Job {
+		return &Job{Spec: JobSpec{Parallelism: parallelism, Items: items}}
+	}
+	started := map[string]ItemStatus{"a": {Name: "a", Phase: ItemScheduled}, "b": {Name: "b", Phase: ItemPending}}
+
+	tests := []struct {
+		name   string
+		old    *Job
+		new    *Job
+		status map[string]ItemStatus
+		phase  JobPhase
+		valid  bool
+	}{
+		{
+			name:  "add item",
+			old:   newJob(nil, Item{Name: "a"}),
+			new:   newJob(nil, Item{Name: "a"}, Item{Name: "b", RunAfter: []string{"a"}}),
+			valid: true,
+		},
+		{
+			name: "remove item",
+			old:  newJob(nil, Item{Name: "a"}, Item{Name: "b", RunAfter: []string{"a"}}),
+			new:  newJob(nil, Item{Name: "a"}),
+		},
+		{
+			name:   "append parent to item not started",
+			old:    newJob(nil, Item{Name: "a"}, Item{Name: "b", RunAfter: []string{"a"}}, Item{Name: "c", RunAfter: []string{"a"}}),
+			new:    newJob(nil, Item{Name: "a"}, Item{Name: "b", RunAfter: []string{"a"}}, Item{Name: "c", RunAfter: []string{"a", "b"}}),
+			status: started,
+			valid:  true,
+		},
+		{
+			name:   "replace parent of item not started",
+			old:    newJob(nil, Item{Name: "a"}, Item{Name: "b", RunAfter: []string{"a"}}, Item{Name: "c", RunAfter: []string{"a"}}),
+			new:    newJob(nil, Item{Name: "a"}, Item{Name: "b", RunAfter: []string{"a"}}, Item{Name: "c", RunAfter: []string{"b"}}),
+			status: started,
+		},
+		{
+			name:   "truncate item not started",
+			old:    newJob(nil, Item{Name: "a"}, Item{Name: "b", RunAfter: []string{"a"}}),
+			new:    newJob(nil, Item{Name: "a"}, Item{Name: "b", RunAfter: []string{"a"}, Truncated: &truncated}),
+			status: started,
+			valid:  true,
+		},
+		{
+			name:   "truncate item started",
+			old:    newJob(nil, Item{Name: "a"}, Item{Name: "b", RunAfter: []string{"a"}}),
+			new:    newJob(nil, Item{Name: "a", Truncated: &truncated}, Item{Name: "b", RunAfter: []string{"a"}}),
+			status: started,
+		},
+		{
+			name:   "truncate item retried",
+			old:    newJob(nil, Item{Name: "a"}),
+			new:    newJob(nil, Item{Name: "a", Truncated: &truncated}),
+			status: map[string]ItemStatus{"a": {Name: "a", Phase: ItemPending, RetryCount: 1}},
+		},
+		{
+			name: "modify item not started",
+			old:  newJob(nil, Item{Name: "a"}),
+			new:  newJob(nil, Item{Name: "a", CoScheduleGroup: "g"}),
+		},
+		{
+			name:  "raise parallelism",
+			old:   newJob(&one, Item{Name: "a"}),
+			new:   newJob(&two, Item{Name: "a"}),
+			valid: true,
+		},
+		{
+			name: "lower parallelism",
+			old:  newJob(&two, Item{Name: "a"}),
+			new:  newJob(&one, Item{Name: "a"}),
+		},
+		{
+			name:  "remove parallelism",
+			old:   newJob(&two, Item{Name: "a"}),
+			new:   newJob(nil, Item{Name: "a"}),
+			valid: true,
+		},
+		{
+			name: "set parallelism",
+			old:  newJob(nil, Item{Name: "a"}),
+			new:  newJob(&two, Item{Name: "a"}),
+		},
+		{
+			name:  "add item to completed job",
+			old:   newJob(nil, Item{Name: "a"}),
+			new:   newJob(nil, Item{Name: "a"}, Item{Name: "b", RunAfter: []string{"a"}}),
+			phase: Completed,
+		},
+	}
+
+	for _, test := range tests {
+		test.old.Status.State.Phase = test.phase
+		test.old.Status.ItemStatus = test.status
+
+		if valid, message := IsJobUpdateValid(test.old, test.new); valid != test.valid {
+			t.Errorf("%s: expected valid %t, got %t %s", test.name, test.valid, valid, message)
+		}
+	}
+}
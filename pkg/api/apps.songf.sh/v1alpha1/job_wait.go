@@ -0,0 +1,214 @@
+package v1alpha1
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/google/cel-go/cel"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/util/jsonpath"
+	"net/url"
+	"strings"
+)
+
+// DefaultWaitPeriodSeconds is the interval between checks of wait Item if not set.
+const DefaultWaitPeriodSeconds = 10
+
+// clusterDomain is the domain of Services which http waits may be suffixed with.
+const clusterDomain = "cluster.local"
+
+func compileWaitExpression(expression string) (cel.Program, error) {
+	env, err := cel.NewEnv(cel.Variable("object", cel.DynType))
+	if err != nil {
+		return nil, err
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("expression returns %s, not bool", ast.OutputType())
+	}
+
+	return env.Program(ast)
+}
+
+func parseWaitJSONPath(path string) (*jsonpath.JSONPath, error) {
+	parser := jsonpath.New("wait").AllowMissingKeys(true)
+	if err := parser.Parse(path); err != nil {
+		return nil, err
+	}
+
+	return parser, nil
+}
+
+// IsItemWaitValid checks the wait items.
+func IsItemWaitValid(job *Job) (bool, string) {
+	for _, item := range CalJobItems(job) {
+		wait := item.Wait
+		if wait == nil {
+			continue
+		}
+
+		if len(item.ItemJobs.Jobs) != 0 || item.SubJob != nil || item.Loop != nil {
+			return false, fmt.Sprintf("wait item %s can not have jobs, sub job or loop", item.Name)
+		}
+
+		if (wait.Resource == nil) == (wait.HTTP == nil) {
+			return false, fmt.Sprintf("wait item %s must set exactly one of resource and http", item.Name)
+		}
+
+		if wait.HTTP != nil {
+			if _, _, err := ParseHTTPWaitService(wait.HTTP.URL); err != nil {
+				return false, fmt.Sprintf("wait item %s %s", item.Name, err.Error())
+			}
+			continue
+		}
+
+		resource := wait.Resource
+		if resource.APIVersion == "" || resource.Kind == "" || resource.Name == "" {
+			return false, fmt.Sprintf("wait item %s apiVersion, kind and name of resource can not be nil", item.Name)
+		}
+
+		if err := isStatusRuleValid(&resource.StatusRule); err != nil {
+			return false, fmt.Sprintf("wait item %s %s", item.Name, err.Error())
+		}
+	}
+
+	return true, ""
+}
+
+// ParseHTTPWaitService returns the name and namespace of the Service requested by url of http wait. Only
+// Services of cluster can be requested, like "http://<service>.<namespace>.svc:8080/health", so that wait
+// items can not reach other endpoints the network of operator reaches, like the metadata of nodes.
+func ParseHTTPWaitService(rawURL string) (string, string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "", fmt.Errorf("url %s invalid", rawURL)
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), "."+clusterDomain)
+	labels := strings.Split(host, ".")
+	if len(labels) != 3 || labels[2] != "svc" ||
+		len(validation.IsDNS1035Label(labels[0])) != 0 || len(validation.IsDNS1123Label(labels[1])) != 0 {
+		return "", "", fmt.Errorf("url %s must request a service of cluster, like http://<service>.<namespace>.svc:<port>/<path>", rawURL)
+	}
+
+	return labels[0], labels[1], nil
+}
+
+// isStatusRuleValid checks that exactly one condition is set in rule and it can be parsed.
+func isStatusRuleValid(rule *StatusRule) error {
+	num := 0
+	if rule.Condition != nil {
+		num++
+		if rule.Condition.Type == "" {
+			return fmt.Errorf("condition type can not be nil")
+		}
+	}
+	if rule.JSONPath != nil {
+		num++
+		if _, err := parseWaitJSONPath(rule.JSONPath.Path); err != nil {
+			return fmt.Errorf("json path invalid: %s", err.Error())
+		}
+	}
+	if rule.Expression != "" {
+		num++
+		if _, err := compileWaitExpression(rule.Expression); err != nil {
+			return fmt.Errorf("expression invalid: %s", err.Error())
+		}
+	}
+	if num != 1 {
+		return fmt.Errorf("must set exactly one of condition, jsonPath and expression")
+	}
+
+	return nil
+}
+
+// CheckStatusRule checks whether object satisfies rule, the message describes why not.
+func CheckStatusRule(rule *StatusRule, object *unstructured.Unstructured) (bool, string, error) {
+
+	switch {
+	case rule.Condition != nil:
+		status := rule.Condition.Status
+		if status == "" {
+			status = "True"
+		}
+
+		conditions, _, err := unstructured.NestedSlice(object.Object, "status", "conditions")
+		if err != nil {
+			return false, "", fmt.Errorf("read conditions err: %s", err.Error())
+		}
+
+		for _, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok || condition["type"] != rule.Condition.Type {
+				continue
+			}
+
+			if condition["status"] == status {
+				return true, "", nil
+			}
+			return false, fmt.Sprintf("condition %s is %v", rule.Condition.Type, condition["status"]), nil
+		}
+
+		return false, fmt.Sprintf("condition %s not found", rule.Condition.Type), nil
+
+	case rule.JSONPath != nil:
+		parser, err := parseWaitJSONPath(rule.JSONPath.Path)
+		if err != nil {
+			return false, "", err
+		}
+
+		buf := &bytes.Buffer{}
+		if err := parser.Execute(buf, object.Object); err != nil {
+			return false, "", fmt.Errorf("execute json path err: %s", err.Error())
+		}
+
+		value := strings.TrimSpace(buf.String())
+		if value == rule.JSONPath.Value {
+			return true, "", nil
+		}
+		return false, fmt.Sprintf("%s is %q", rule.JSONPath.Path, value), nil
+
+	default:
+		program, err := compileWaitExpression(rule.Expression)
+		if err != nil {
+			return false, "", err
+		}
+
+		val, _, err := program.Eval(map[string]interface{}{
+			"object": object.Object,
+		})
+		if err != nil {
+			return false, fmt.Sprintf("expression not true: %s", err.Error()), nil
+		}
+
+		res, ok := val.Value().(bool)
+		if !ok {
+			return false, "", fmt.Errorf("expression returns %v, not bool", val.Value())
+		}
+		if !res {
+			return false, "expression is false", nil
+		}
+
+		return true, "", nil
+	}
+}
+
+// IsHTTPWaitSucceeded checks the status code returned by the endpoint of wait.
+func IsHTTPWaitSucceeded(wait *HTTPWait, code int) bool {
+	if len(wait.SuccessCodes) == 0 {
+		return code >= 200 && code < 300
+	}
+
+	for _, successCode := range wait.SuccessCodes {
+		if int(successCode) == code {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,114 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"testing"
+)
+
+func TestCheckStatusRule(t *testing.T) {
+	object := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": int64(2)},
+		"status": map[string]interface{}{
+			"phase":         "Running",
+			"readyReplicas": int64(2),
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+				map[string]interface{}{"type": "Degraded", "status": "False"},
+			},
+		},
+	}}
+
+	tests := []struct {
+		name      string
+		rule      StatusRule
+		satisfied bool
+		err       bool
+	}{
+		{name: "condition true", rule: StatusRule{Condition: &WaitCondition{Type: "Ready"}}, satisfied: true},
+		{name: "condition status set", rule: StatusRule{Condition: &WaitCondition{Type: "Degraded", Status: "False"}}, satisfied: true},
+		{name: "condition false", rule: StatusRule{Condition: &WaitCondition{Type: "Degraded"}}},
+		{name: "condition not found", rule: StatusRule{Condition: &WaitCondition{Type: "Available"}}},
+		{name: "json path matched", rule: StatusRule{JSONPath: &JSONPathWait{Path: "{.status.phase}", Value: "Running"}}, satisfied: true},
+		{name: "json path not matched", rule: StatusRule{JSONPath: &JSONPathWait{Path: "{.status.phase}", Value: "Succeeded"}}},
+		{name: "json path missing", rule: StatusRule{JSONPath: &JSONPathWait{Path: "{.status.missing}", Value: "x"}}},
+		{name: "json path missing matches empty", rule: StatusRule{JSONPath: &JSONPathWait{Path: "{.status.missing}"}}, satisfied: true},
+		{name: "expression true", rule: StatusRule{Expression: `object.status.readyReplicas == object.spec.replicas`}, satisfied: true},
+		{name: "expression false", rule: StatusRule{Expression: `object.status.phase == "Succeeded"`}},
+		// fields not set yet are waited for, not taken as errors
+		{name: "expression field missing", rule: StatusRule{Expression: `object.status.succeeded > 0`}},
+		{name: "expression not bool", rule: StatusRule{Expression: `object.status.phase`}, err: true},
+	}
+
+	for _, test := range tests {
+		satisfied, message, err := CheckStatusRule(&test.rule, object)
+		if (err != nil) != test.err {
+			t.Errorf("%s: expected err %t, got %v", test.name, test.err, err)
+			continue
+		}
+		if satisfied != test.satisfied {
+			t.Errorf("%s: expected satisfied %t, got %t %s", test.name, test.satisfied, satisfied, message)
+		}
+		if !satisfied && !test.err && message == "" {
+			t.Errorf("%s: message should describe why not satisfied", test.name)
+		}
+	}
+}
+
+func TestIsItemWaitValid(t *testing.T) {
+	resource := func(rule StatusRule) *ResourceWait {
+		return &ResourceWait{APIVersion: "apps/v1", Kind: "Deployment", Name: "db", StatusRule: rule}
+	}
+
+	tests := []struct {
+		name  string
+		wait  *WaitTemplate
+		valid bool
+	}{
+		{name: "condition", wait: &WaitTemplate{Resource: resource(StatusRule{Condition: &WaitCondition{Type: "Available"}})}, valid: true},
+		{name: "http", wait: &WaitTemplate{HTTP: &HTTPWait{URL: "https://db.ns.svc/health"}}, valid: true},
+		{name: "none set", wait: &WaitTemplate{}},
+		{name: "both set", wait: &WaitTemplate{Resource: resource(StatusRule{Expression: "true"}), HTTP: &HTTPWait{URL: "http://db"}}},
+		{name: "url scheme invalid", wait: &WaitTemplate{HTTP: &HTTPWait{URL: "file:///etc/passwd"}}},
+		{name: "service with cluster domain", wait: &WaitTemplate{HTTP: &HTTPWait{URL: "http://db.ns.svc.cluster.local:8080/health"}}, valid: true},
+		{name: "short service name", wait: &WaitTemplate{HTTP: &HTTPWait{URL: "http://db/health"}}},
+		{name: "metadata ip", wait: &WaitTemplate{HTTP: &HTTPWait{URL: "http://169.254.169.254/latest/meta-data"}}},
+		{name: "external host", wait: &WaitTemplate{HTTP: &HTTPWait{URL: "https://example.com/health"}}},
+		{name: "external host like service", wait: &WaitTemplate{HTTP: &HTTPWait{URL: "https://db.ns.svc.example.com/health"}}},
+		{name: "pod of service", wait: &WaitTemplate{HTTP: &HTTPWait{URL: "http://db-0.db.ns.svc/health"}}},
+		{name: "resource name nil", wait: &WaitTemplate{Resource: &ResourceWait{APIVersion: "v1", Kind: "Pod", StatusRule: StatusRule{Expression: "true"}}}},
+		{name: "no rule", wait: &WaitTemplate{Resource: resource(StatusRule{})}},
+		{name: "two rules", wait: &WaitTemplate{Resource: resource(StatusRule{Expression: "true", Condition: &WaitCondition{Type: "Ready"}})}},
+		{name: "condition type nil", wait: &WaitTemplate{Resource: resource(StatusRule{Condition: &WaitCondition{}})}},
+		{name: "json path invalid", wait: &WaitTemplate{Resource: resource(StatusRule{JSONPath: &JSONPathWait{Path: "{.status"}})}},
+		{name: "expression invalid", wait: &WaitTemplate{Resource: resource(StatusRule{Expression: "object."})}},
+		{name: "expression not bool", wait: &WaitTemplate{Resource: resource(StatusRule{Expression: `"ready"`})}},
+	}
+
+	for _, test := range tests {
+		job := &Job{Spec: JobSpec{Items: []Item{{Name: "a", Wait: test.wait}}}}
+		if valid, message := IsItemWaitValid(job); valid != test.valid {
+			t.Errorf("%s: expected valid %t, got %t %s", test.name, test.valid, valid, message)
+		}
+	}
+}
+
+func TestIsHTTPWaitSucceeded(t *testing.T) {
+	tests := []struct {
+		codes     []int32
+		code      int
+		succeeded bool
+	}{
+		{code: 200, succeeded: true},
+		{code: 204, succeeded: true},
+		{code: 302},
+		{code: 503},
+		{codes: []int32{200, 401}, code: 401, succeeded: true},
+		{codes: []int32{200, 401}, code: 204},
+	}
+
+	for _, test := range tests {
+		if succeeded := IsHTTPWaitSucceeded(&HTTPWait{SuccessCodes: test.codes}, test.code); succeeded != test.succeeded {
+			t.Errorf("codes %v code %d: expected %t, got %t", test.codes, test.code, test.succeeded, succeeded)
+		}
+	}
+}
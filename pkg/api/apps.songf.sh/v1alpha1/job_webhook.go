@@ -20,7 +20,7 @@ func (r *Job) Default() {
 	// container save set
 	containerSaveMap := map[string]string{}
 
-	for _, item := range r.Spec.Items {
+	for _, item := range CalJobItems(r) {
 		for _, jobTemplate := range item.ItemJobs.Jobs {
 			if jobTemplate.ContainerExtend != nil && *jobTemplate.ContainerExtend != "" {
 				names := JobExtendStr2Names(*jobTemplate.ContainerExtend)
@@ -46,6 +46,21 @@ func (r *Job) Default() {
 		}
 	}
 
+	// items of groups are extended by their flattened names
+	for i, group := range r.Spec.Groups {
+		for j, item := range group.Items {
+			jobName, ok := containerSaveMap[CalGroupItemName(group.Name, item.Name)]
+			if ok {
+				for k, itemJob := range item.ItemJobs.Jobs {
+					if itemJob.Name == jobName {
+						r.Spec.Groups[i].Items[j].ItemJobs.Jobs[k].ContainerSave = true
+						break
+					}
+				}
+			}
+		}
+	}
+
 }
 
 //+kubebuilder:webhook:path=/validate-apps-songf-sh-v1alpha1-job,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps.songf.sh,resources=jobs,verbs=create;update;delete,versions=v1alpha1,name=vjob.kb.io,admissionReviewVersions=v1
@@ -58,23 +73,74 @@ func (r *Job) ValidateCreate() (admission.Warnings, error) {
 
 	var warnings admission.Warnings
 
-	if len(r.Spec.Items) == 0 {
+	if !IsJobTemplateRendered(r) {
+		errMsg := fmt.Sprintf("%s template %s not rendered", r.Name, r.Spec.TemplateRef.Name)
+		warnings = append(warnings, errMsg)
+		return warnings, fmt.Errorf(errMsg)
+	}
+
+	if len(r.Spec.Items) == 0 && len(r.Spec.Groups) == 0 {
 		errMsg := fmt.Sprintf("%s items can not be nil", r.Name)
 		warnings = append(warnings, errMsg)
 		return warnings, fmt.Errorf(errMsg)
 	}
 
+	if flag, msg := IsJobGroupValid(r); !flag {
+		warnings = append(warnings, msg)
+		return warnings, fmt.Errorf(msg)
+	}
+
 	if flag, msg := IsJobItemValid(r); !flag {
 		warnings = append(warnings, msg)
 		return warnings, fmt.Errorf(msg)
 	}
 
+	if flag, msg := IsCoScheduleGroupValid(r); !flag {
+		warnings = append(warnings, msg)
+		return warnings, fmt.Errorf(msg)
+	}
+
+	if flag, msg := IsItemOutputReferenceValid(r); !flag {
+		warnings = append(warnings, msg)
+		return warnings, fmt.Errorf(msg)
+	}
+
+	if flag, msg := IsItemNameReferenceValid(r); !flag {
+		warnings = append(warnings, msg)
+		return warnings, fmt.Errorf(msg)
+	}
+
+	if flag, msg := IsItemLoopValid(r); !flag {
+		warnings = append(warnings, msg)
+		return warnings, fmt.Errorf(msg)
+	}
+
+	if flag, msg := IsJobDependencyValid(r); !flag {
+		warnings = append(warnings, msg)
+		return warnings, fmt.Errorf(msg)
+	}
+
+	if flag, msg := IsItemWaitValid(r); !flag {
+		warnings = append(warnings, msg)
+		return warnings, fmt.Errorf(msg)
+	}
+
+	if flag, msg := IsKueueQueueNameValid(r); !flag {
+		warnings = append(warnings, msg)
+		return warnings, fmt.Errorf(msg)
+	}
+
+	if flag, msg := IsItemLongRunningValid(r); !flag {
+		warnings = append(warnings, msg)
+		return warnings, fmt.Errorf(msg)
+	}
+
 	flag, err := IsJobHasCycle(r)
 	if err != nil {
 		warnings = append(warnings, err.Error())
 		return warnings, err
 	}
-	if !flag {
+	if flag {
 		msg := "can not build cycle graph in job"
 		warnings = append(warnings, msg)
 		return warnings, fmt.Errorf(msg)
@@ -91,16 +157,29 @@ func (r *Job) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
 
 	oldJob, ok := old.(*Job)
 	if !ok {
-		warnings = append(warnings, "update old is not Job")
+		msg := "update old is not Job"
+		warnings = append(warnings, msg)
+		return warnings, fmt.Errorf(msg)
 	}
 
-	if !apiequality.Semantic.DeepEqual(r.Spec, oldJob.Spec) {
-		msg := "job updates may not change fields other than"
+	// the template a Job rendered from can not be changed, and a Job not rendered can not skip its template
+	if r.Annotations[TemplateGenerationAnnotation] != oldJob.Annotations[TemplateGenerationAnnotation] {
+		msg := fmt.Sprintf("annotation %s can not be changed", TemplateGenerationAnnotation)
 		warnings = append(warnings, msg)
 		return warnings, fmt.Errorf(msg)
 	}
 
-	return nil, nil
+	if apiequality.Semantic.DeepEqual(r.Spec, oldJob.Spec) {
+		return nil, nil
+	}
+
+	if flag, msg := IsJobUpdateValid(oldJob, r); !flag {
+		warnings = append(warnings, msg)
+		return warnings, fmt.Errorf(msg)
+	}
+
+	// the updated graph is checked as a new one
+	return r.ValidateCreate()
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -0,0 +1,47 @@
+package v1alpha1
+
+import (
+	"testing"
+)
+
+func TestJobValidateCreateGraph(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []Item
+		valid bool
+	}{
+		{
+			name:  "acyclic graph",
+			items: []Item{{Name: "a"}, {Name: "b", RunAfter: []string{"a"}}, {Name: "c", RunAfter: []string{"a", "b"}}},
+			valid: true,
+		},
+		{
+			name:  "cycle graph",
+			items: []Item{{Name: "a"}, {Name: "b", RunAfter: []string{"a", "c"}}, {Name: "c", RunAfter: []string{"b"}}},
+		},
+		{
+			name:  "parent not found",
+			items: []Item{{Name: "a"}, {Name: "b", RunAfter: []string{"x"}}},
+		},
+	}
+
+	for _, test := range tests {
+		job := &Job{Spec: JobSpec{Items: test.items}}
+		if _, err := job.ValidateCreate(); (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %t, got err %v", test.name, test.valid, err)
+		}
+	}
+}
+
+func TestIsJobItemValidParent(t *testing.T) {
+	// parents are looked up by their own names, not the name of child
+	job := &Job{Spec: JobSpec{Items: []Item{{Name: "a"}, {Name: "b", RunAfter: []string{"x"}}}}}
+	if flag, _ := IsJobItemValid(job); flag {
+		t.Errorf("item whose parent not found should be invalid")
+	}
+
+	job.Spec.Items[1].RunAfter = []string{"a"}
+	if flag, msg := IsJobItemValid(job); !flag {
+		t.Errorf("item whose parent found should be valid: %s", msg)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2023 firewood.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:categories=all,path=jobtemplates,shortName=sfjt
+
+// JobTemplate is the Schema for the jobtemplates API.
+// Jobs in the same namespace reference a JobTemplate by spec.templateRef instead of inlining their Items.
+type JobTemplate struct {
+	metav1.TypeMeta `json:",inline"`
+
+	//+optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Specification of the Job rendered from the template
+	// +optional
+	Spec JobTemplateDefinition `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:categories=all,path=clusterjobtemplates,scope=Cluster,shortName=sfcjt
+
+// ClusterJobTemplate is the Schema for the clusterjobtemplates API.
+// It is the same as JobTemplate, but can be referenced by Jobs of all namespaces.
+type ClusterJobTemplate struct {
+	metav1.TypeMeta `json:",inline"`
+
+	//+optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Specification of the Job rendered from the template
+	// +optional
+	Spec JobTemplateDefinition `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// JobTemplateDefinition defines the parameters of template and the Job rendered from it.
+type JobTemplateDefinition struct {
+
+	// Parameters declared by the template. Each "$(params.<name>)" in Template is replaced by
+	// the value set in the Job, or by the default value if the Job doesn't set it.
+	// +optional
+	Parameters []TemplateParameter `json:"parameters,omitempty" protobuf:"bytes,1,rep,name=parameters"`
+
+	// Template of the Job. Fields set in the spec of Job take precedence over the ones in Template,
+	// labels and annotations of Template are added to the Job if not set by the Job.
+	// +optional
+	Template JobTemplateSpec `json:"template,omitempty" protobuf:"bytes,2,opt,name=template"`
+}
+
+// TemplateParameter declares a parameter of template.
+type TemplateParameter struct {
+
+	// The name of parameter, must be unique in the template.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]+$`
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+
+	// Human-readable description of parameter.
+	// +optional
+	Description string `json:"description,omitempty" protobuf:"bytes,2,opt,name=description"`
+
+	// Default value of parameter. If unset, the parameter is required.
+	// +optional
+	Default *string `json:"default,omitempty" protobuf:"bytes,3,opt,name=default"`
+}
+
+//+kubebuilder:object:root=true
+
+// JobTemplateList contains a list of JobTemplate
+type JobTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []JobTemplate `json:"items"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterJobTemplateList contains a list of ClusterJobTemplate
+type ClusterJobTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterJobTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&JobTemplate{}, &JobTemplateList{}, &ClusterJobTemplate{}, &ClusterJobTemplateList{})
+}
@@ -0,0 +1,118 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"regexp"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var templateParameterNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+//+kubebuilder:webhook:path=/validate-apps-songf-sh-v1alpha1-jobtemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps.songf.sh,resources=jobtemplates,verbs=create;update,versions=v1alpha1,name=vjobtemplate.kb.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-apps-songf-sh-v1alpha1-clusterjobtemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps.songf.sh,resources=clusterjobtemplates,verbs=create;update,versions=v1alpha1,name=vclusterjobtemplate.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &JobTemplate{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *JobTemplate) ValidateCreate() (admission.Warnings, error) {
+	klog.Info("validate create", "name", r.Name)
+
+	if flag, msg := IsJobTemplateDefinitionValid(&r.Spec); !flag {
+		return admission.Warnings{msg}, fmt.Errorf(msg)
+	}
+
+	return nil, nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *JobTemplate) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	klog.Info("validate update", "name", r.Name)
+
+	return r.ValidateCreate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *JobTemplate) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
+
+var _ webhook.Validator = &ClusterJobTemplate{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterJobTemplate) ValidateCreate() (admission.Warnings, error) {
+	klog.Info("validate create", "name", r.Name)
+
+	if flag, msg := IsJobTemplateDefinitionValid(&r.Spec); !flag {
+		return admission.Warnings{msg}, fmt.Errorf(msg)
+	}
+
+	return nil, nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterJobTemplate) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	klog.Info("validate update", "name", r.Name)
+
+	return r.ValidateCreate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterJobTemplate) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
+
+// IsJobTemplateDefinitionValid checks the parameters of template and the parameters used by it. A template
+// whose parameters all have default values is also checked as the Job rendered from it without parameters,
+// other templates are checked once rendered into Jobs.
+func IsJobTemplateDefinitionValid(definition *JobTemplateDefinition) (bool, string) {
+	values := map[string]string{}
+	required := false
+
+	for _, parameter := range definition.Parameters {
+		if !templateParameterNameRegexp.MatchString(parameter.Name) {
+			return false, fmt.Sprintf("template parameter name %s invalid", parameter.Name)
+		}
+		if _, ok := values[parameter.Name]; ok {
+			return false, fmt.Sprintf("template parameter %s repeated", parameter.Name)
+		}
+
+		values[parameter.Name] = ""
+		if parameter.Default != nil {
+			values[parameter.Name] = *parameter.Default
+		} else {
+			required = true
+		}
+	}
+
+	if definition.Template.Spec.TemplateRef != nil {
+		return false, "template can not reference another template"
+	}
+
+	raw, err := json.Marshal(definition.Template)
+	if err != nil {
+		return false, fmt.Sprintf("marshal template err: %s", err.Error())
+	}
+	if _, err := SubstituteTemplateParameters(string(raw), values); err != nil {
+		return false, err.Error()
+	}
+
+	if required || (len(definition.Template.Spec.Items) == 0 && len(definition.Template.Spec.Groups) == 0) {
+		return true, ""
+	}
+
+	job := &Job{}
+	job.Name = "template"
+	job.Spec.TemplateRef = &TemplateReference{Name: job.Name}
+	if err := RenderJobTemplate(job, definition, 0); err != nil {
+		return false, err.Error()
+	}
+	if _, err := job.ValidateCreate(); err != nil {
+		return false, fmt.Sprintf("job rendered from template invalid: %s", err.Error())
+	}
+
+	return true, ""
+}
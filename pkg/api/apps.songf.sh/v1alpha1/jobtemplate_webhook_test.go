@@ -0,0 +1,76 @@
+package v1alpha1
+
+import (
+	"testing"
+)
+
+func TestIsJobTemplateDefinitionValid(t *testing.T) {
+	parent, other := "a", "x"
+	items := []Item{{Name: "a"}, {Name: "b", RunAfter: []string{"$(params.parent)"}}}
+
+	tests := []struct {
+		name       string
+		parameters []TemplateParameter
+		spec       JobSpec
+		valid      bool
+	}{
+		{
+			name:       "parameters with defaults rendering a valid job",
+			parameters: []TemplateParameter{{Name: "parent", Default: &parent}},
+			spec:       JobSpec{Items: items},
+			valid:      true,
+		},
+		{
+			name:       "parameters with defaults rendering an invalid job",
+			parameters: []TemplateParameter{{Name: "parent", Default: &other}},
+			spec:       JobSpec{Items: items},
+		},
+		{
+			// the job rendered is only checked once the required parameter is set
+			name:       "required parameter",
+			parameters: []TemplateParameter{{Name: "parent"}},
+			spec:       JobSpec{Items: items},
+			valid:      true,
+		},
+		{
+			name:       "parameter used but not declared",
+			parameters: []TemplateParameter{{Name: "other"}},
+			spec:       JobSpec{Items: items},
+		},
+		{
+			name:       "parameter repeated",
+			parameters: []TemplateParameter{{Name: "parent"}, {Name: "parent"}},
+			spec:       JobSpec{Items: items},
+		},
+		{
+			name:       "parameter name invalid",
+			parameters: []TemplateParameter{{Name: "a.b"}},
+		},
+		{
+			name: "template referencing another template",
+			spec: JobSpec{TemplateRef: &TemplateReference{Name: "other"}},
+		},
+	}
+
+	for _, test := range tests {
+		definition := &JobTemplateDefinition{Parameters: test.parameters, Template: JobTemplateSpec{Spec: test.spec}}
+		if flag, msg := IsJobTemplateDefinitionValid(definition); flag != test.valid {
+			t.Errorf("%s: expected valid %t, got %t: %s", test.name, test.valid, flag, msg)
+		}
+	}
+}
+
+func TestJobValidateUpdateTemplateGeneration(t *testing.T) {
+	oldJob := &Job{Spec: JobSpec{TemplateRef: &TemplateReference{Name: "t"}, Items: []Item{{Name: "a"}}}}
+	newJob := oldJob.DeepCopy()
+	newJob.Annotations = map[string]string{TemplateGenerationAnnotation: "1"}
+
+	if _, err := newJob.ValidateUpdate(oldJob); err == nil {
+		t.Errorf("annotation %s set by update should be rejected", TemplateGenerationAnnotation)
+	}
+
+	oldJob.Annotations = map[string]string{TemplateGenerationAnnotation: "1"}
+	if _, err := newJob.ValidateUpdate(oldJob); err != nil {
+		t.Errorf("annotation %s unchanged should be allowed: %s", TemplateGenerationAnnotation, err.Error())
+	}
+}
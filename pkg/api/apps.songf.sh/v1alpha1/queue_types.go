@@ -0,0 +1,103 @@
+/*
+Copyright 2023 firewood.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:categories=all,path=queues,scope=Cluster,shortName=sfq
+//+kubebuilder:subresource:status
+
+// Queue is the Schema for the queues API.
+// Jobs reference a Queue by name and wait in it until they are admitted.
+type Queue struct {
+	metav1.TypeMeta `json:",inline"`
+
+	//+optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Specification of the desired behavior of the queue
+	// +optional
+	Spec QueueSpec `json:"spec,omitempty" protobuf:"bytes,2,opt,name=spec"`
+
+	// Current status of the queue
+	// +optional
+	Status QueueStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// QueueSpec defines the desired state of Queue
+type QueueSpec struct {
+
+	// MaxRunningJobs limits the num of Jobs admitted from this queue and not finished yet.
+	// If unset, the num of running Jobs is not limited.
+	// +optional
+	MaxRunningJobs *int32 `json:"maxRunningJobs,omitempty" protobuf:"varint,1,opt,name=maxRunningJobs"`
+
+	// MaxRunningItems limits the num of Items in Scheduling or Scheduled phase of all Jobs in this queue.
+	// If unset, the num of running Items is not limited.
+	// +optional
+	MaxRunningItems *int32 `json:"maxRunningItems,omitempty" protobuf:"varint,2,opt,name=maxRunningItems"`
+
+	// Quota limits the sum of pod requests of all running Jobs in this queue.
+	// Resources not listed here are not limited.
+	// +optional
+	Quota corev1.ResourceList `json:"quota,omitempty" protobuf:"bytes,3,rep,name=quota"`
+
+	// NamespaceWeights defines the weight of each namespace while sharing the queue, key is namespace.
+	// Namespaces not listed here have weight 1. Queued Jobs are admitted from the namespace
+	// which has the lowest num of running Jobs divided by its weight.
+	// +optional
+	NamespaceWeights map[string]int32 `json:"namespaceWeights,omitempty" protobuf:"bytes,4,rep,name=namespaceWeights"`
+}
+
+// QueueStatus defines the observed state of Queue
+type QueueStatus struct {
+
+	// The num of Jobs waiting for admission.
+	// +optional
+	Pending int32 `json:"pending,omitempty" protobuf:"varint,1,opt,name=pending"`
+
+	// The num of Jobs admitted and not finished.
+	// +optional
+	Running int32 `json:"running,omitempty" protobuf:"varint,2,opt,name=running"`
+
+	// The num of Items in Scheduling or Scheduled phase of running Jobs.
+	// +optional
+	RunningItems int32 `json:"runningItems,omitempty" protobuf:"varint,3,opt,name=runningItems"`
+
+	// The sum of pod requests of running Jobs.
+	// +optional
+	Allocated corev1.ResourceList `json:"allocated,omitempty" protobuf:"bytes,4,rep,name=allocated"`
+}
+
+//+kubebuilder:object:root=true
+
+// QueueList contains a list of Queue
+type QueueList struct {
+	metav1.TypeMeta `json:",inline"`
+
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Queue `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Queue{}, &QueueList{})
+}
@@ -0,0 +1,69 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"time"
+)
+
+//+kubebuilder:webhook:path=/validate-apps-songf-sh-v1alpha1-scheduledjob,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps.songf.sh,resources=scheduledjobs,verbs=create;update,versions=v1alpha1,name=vscheduledjob.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &ScheduledJob{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *ScheduledJob) ValidateCreate() (admission.Warnings, error) {
+	klog.Info("validate create", "name", r.Name)
+
+	if _, _, err := ParseScheduledJobSchedule(r); err != nil {
+		return admission.Warnings{err.Error()}, err
+	}
+
+	// jobs referencing templates are checked once rendered
+	if r.Spec.JobTemplate.Spec.TemplateRef != nil {
+		return nil, nil
+	}
+
+	job := &Job{Spec: r.Spec.JobTemplate.Spec}
+	job.Name = r.Name
+	job.Namespace = r.Namespace
+	if warnings, err := job.ValidateCreate(); err != nil {
+		return warnings, fmt.Errorf("job template invalid: %s", err.Error())
+	}
+
+	return nil, nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *ScheduledJob) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	klog.Info("validate update", "name", r.Name)
+
+	return r.ValidateCreate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ScheduledJob) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ParseScheduledJobSchedule parses the schedule and time zone of scheduled job, the local time zone of
+// operator is returned if time zone is not set.
+func ParseScheduledJobSchedule(scheduledJob *ScheduledJob) (cron.Schedule, *time.Location, error) {
+	location := time.Local
+	if scheduledJob.Spec.TimeZone != nil {
+		var err error
+		if location, err = time.LoadLocation(*scheduledJob.Spec.TimeZone); err != nil {
+			return nil, nil, fmt.Errorf("time zone %s invalid: %s", *scheduledJob.Spec.TimeZone, err.Error())
+		}
+	}
+
+	schedule, err := cron.ParseStandard(scheduledJob.Spec.Schedule)
+	if err != nil {
+		return nil, nil, fmt.Errorf("schedule %s invalid: %s", scheduledJob.Spec.Schedule, err.Error())
+	}
+
+	return schedule, location, nil
+}
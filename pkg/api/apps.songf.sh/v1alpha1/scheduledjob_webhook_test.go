@@ -0,0 +1,42 @@
+package v1alpha1
+
+import (
+	"testing"
+)
+
+func TestScheduledJobValidateCreate(t *testing.T) {
+	utc, invalidZone := "UTC", "Mars/Olympus"
+	items := []Item{{Name: "a"}, {Name: "b", RunAfter: []string{"a"}}}
+
+	tests := []struct {
+		name     string
+		schedule string
+		timeZone *string
+		spec     JobSpec
+		valid    bool
+	}{
+		{name: "valid", schedule: "*/5 * * * *", timeZone: &utc, spec: JobSpec{Items: items}, valid: true},
+		{name: "schedule invalid", schedule: "every minute", spec: JobSpec{Items: items}},
+		{name: "time zone invalid", schedule: "@hourly", timeZone: &invalidZone, spec: JobSpec{Items: items}},
+		{name: "job template invalid", schedule: "@hourly", spec: JobSpec{Items: []Item{{Name: "a", RunAfter: []string{"x"}}}}},
+		{
+			// jobs referencing templates are checked once rendered
+			name:     "job template referencing template",
+			schedule: "@hourly",
+			spec:     JobSpec{TemplateRef: &TemplateReference{Name: "t"}},
+			valid:    true,
+		},
+	}
+
+	for _, test := range tests {
+		scheduledJob := &ScheduledJob{Spec: ScheduledJobSpec{
+			Schedule:    test.schedule,
+			TimeZone:    test.timeZone,
+			JobTemplate: JobTemplateSpec{Spec: test.spec},
+		}}
+
+		if _, err := scheduledJob.ValidateCreate(); (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %t, got err %v", test.name, test.valid, err)
+		}
+	}
+}
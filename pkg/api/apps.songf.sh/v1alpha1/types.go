@@ -3,4 +3,44 @@ package v1alpha1
 const (
 	CreateByJob     = "songf.sh/job"
 	CreateByJobItem = "songf.sh/job-item"
+
+	ItemPriorityAnnotation = "songf.sh/item-priority"
+
+	// TemplateGenerationAnnotation records the generation of template a Job was rendered from,
+	// set by webhook, and a Job with it will not be rendered again. It is dropped from created Jobs
+	// before rendering and can not be changed by updates.
+	TemplateGenerationAnnotation = "songf.sh/template-generation"
+
+	// ItemGroupAnnotation records the group of Items flattened from groups.
+	ItemGroupAnnotation = "songf.sh/item-group"
+
+	// GroupRunAfterPrefix prefixes the groups in RunAfter.
+	GroupRunAfterPrefix = "group:"
+
+	// ItemJobAnnotation records the name of job in Item an object created for.
+	ItemJobAnnotation = "songf.sh/item-job"
+
+	// AllowedNamespacesAnnotation lists the namespaces, separated by comma, whose Jobs can copy the data of
+	// the ConfigMap or Secret annotated, or wait for the object annotated. "*" allows all namespaces.
+	AllowedNamespacesAnnotation = "songf.sh/allowed-namespaces"
+)
+
+const (
+	FIFOSchedulingPolicy                     = "FIFO"
+	PrioritySchedulingPolicy                 = "Priority"
+	ShortestExpectedDurationSchedulingPolicy = "ShortestExpectedDuration"
+	CriticalPathSchedulingPolicy             = "CriticalPath"
+)
+
+const (
+	JobTemplateKind        = "JobTemplate"
+	ClusterJobTemplateKind = "ClusterJobTemplate"
+)
+
+const (
+	// ScheduledTimeAnnotation records the time a Job created by ScheduledJob was scheduled at.
+	ScheduledTimeAnnotation = "songf.sh/scheduled-at"
+
+	// ScheduleValidCondition is the condition of ScheduledJob telling whether its schedule can be parsed.
+	ScheduleValidCondition = "ScheduleValid"
 )
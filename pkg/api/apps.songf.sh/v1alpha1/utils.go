@@ -2,6 +2,10 @@ package v1alpha1
 
 import (
 	"fmt"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strings"
 )
@@ -15,11 +19,141 @@ func CalJobItemSubName(jobName, itemName, baseName string) string {
 	return fmt.Sprintf("%s-%s-%s", jobName, itemName, baseName)
 }
 
+func CalJobCoScheduleGroupName(jobName, groupName string) string {
+	return fmt.Sprintf("%s-%s", jobName, groupName)
+}
+
+// CalItemPodNum returns the num of pods the jobs of item want to run at the same time.
+func CalItemPodNum(item *Item) int32 {
+	var num int32
+
+	for _, itemJob := range item.ItemJobs.Jobs {
+		if itemJob.KubeJobSpec != nil {
+			if itemJob.KubeJobSpec.Parallelism != nil {
+				num += *itemJob.KubeJobSpec.Parallelism
+			} else {
+				num++
+			}
+		}
+
+		if itemJob.VolcanoJobSpec != nil {
+			for _, task := range itemJob.VolcanoJobSpec.Tasks {
+				num += task.Replicas
+			}
+		}
+
+		if itemJob.Script != nil {
+			num++
+		}
+
+		if itemJob.JobSetSpec != nil {
+			jobs, err := GetReplicatedJobs(itemJob.JobSetSpec)
+			if err != nil {
+				continue
+			}
+
+			for i := range jobs {
+				num += CalReplicatedJobReplicas(&jobs[i]) * calKubeJobParallelism(&jobs[i].Template.Spec)
+			}
+		}
+	}
+
+	if item.LongRunning != nil {
+		_, replicas := item.LongRunning.GetPodTemplate()
+		num += replicas
+	}
+
+	return num
+}
+
+// calKubeJobParallelism returns the num of pods kube job runs at the same time, 1 if not set.
+func calKubeJobParallelism(spec *batchv1.JobSpec) int32 {
+	if spec.Parallelism == nil {
+		return 1
+	}
+
+	return *spec.Parallelism
+}
+
+// CalItemResourceRequests returns the sum of pod requests of the jobs in item.
+func CalItemResourceRequests(item *Item) corev1.ResourceList {
+	res := corev1.ResourceList{}
+
+	addPodRequests := func(spec *corev1.PodSpec, replicas int32) {
+		for _, container := range spec.Containers {
+			for name, quantity := range container.Resources.Requests {
+				total := res[name]
+				total.Add(*resource.NewMilliQuantity(quantity.MilliValue()*int64(replicas), quantity.Format))
+				res[name] = total
+			}
+		}
+	}
+
+	for _, itemJob := range item.ItemJobs.Jobs {
+		if itemJob.KubeJobSpec != nil {
+			replicas := int32(1)
+			if itemJob.KubeJobSpec.Parallelism != nil {
+				replicas = *itemJob.KubeJobSpec.Parallelism
+			}
+			addPodRequests(&itemJob.KubeJobSpec.Template.Spec, replicas)
+		}
+
+		if itemJob.VolcanoJobSpec != nil {
+			for _, task := range itemJob.VolcanoJobSpec.Tasks {
+				addPodRequests(&task.Template.Spec, task.Replicas)
+			}
+		}
+
+		if itemJob.Script != nil {
+			addPodRequests(&corev1.PodSpec{
+				Containers: []corev1.Container{{Resources: itemJob.Script.Resources}},
+			}, 1)
+		}
+
+		if itemJob.JobSetSpec != nil {
+			jobs, err := GetReplicatedJobs(itemJob.JobSetSpec)
+			if err != nil {
+				continue
+			}
+
+			for i := range jobs {
+				job := &jobs[i]
+				addPodRequests(&job.Template.Spec.Template.Spec, CalReplicatedJobReplicas(job)*calKubeJobParallelism(&job.Template.Spec))
+			}
+		}
+	}
+
+	if item.LongRunning != nil {
+		if template, replicas := item.LongRunning.GetPodTemplate(); template != nil {
+			addPodRequests(&template.Spec, replicas)
+		}
+	}
+
+	return res
+}
+
+// CalJobResourceRequests returns the sum of pod requests of all items in job.
+func CalJobResourceRequests(job *Job) corev1.ResourceList {
+	res := corev1.ResourceList{}
+
+	items := CalJobItems(job)
+	for i := range items {
+		for name, quantity := range CalItemResourceRequests(&items[i]) {
+			total := res[name]
+			total.Add(quantity)
+			res[name] = total
+		}
+	}
+
+	return res
+}
+
 func IsJobItemValid(job *Job) (bool, string) {
 	fatherNum := 0
 	itemNames := map[string]*Item{}
+	items := CalJobItems(job)
 
-	for _, item := range job.Spec.Items {
+	for _, item := range items {
 		if item.Name == "" {
 			return false, "item name can not be nil"
 		}
@@ -63,9 +197,9 @@ func IsJobItemValid(job *Job) (bool, string) {
 	}
 
 	// parent & extend not found
-	for _, item := range job.Spec.Items {
+	for _, item := range items {
 		for _, fatherName := range item.RunAfter {
-			if _, ok := itemNames[item.Name]; !ok {
+			if _, ok := itemNames[fatherName]; !ok {
 				return false, fmt.Sprintf("item %s parent %s not found", item.Name, fatherName)
 			}
 		}
@@ -117,6 +251,51 @@ func IsJobItemValid(job *Job) (bool, string) {
 	return true, ""
 }
 
+// IsCoScheduleGroupValid checks items in the same co-schedule group only running kube jobs and not depending
+// on each other, otherwise they can never be ready at the same time.
+func IsCoScheduleGroupValid(job *Job) (bool, string) {
+	_, nodeMap, err := NewGraphFromJob(job)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	for _, node := range nodeMap {
+		group := node.Item.CoScheduleGroup
+		if group == "" {
+			continue
+		}
+
+		// only the pods of kube jobs join the PodGroup of group, other kinds are gang scheduled by themselves
+		for _, itemJob := range node.Item.ItemJobs.Jobs {
+			if itemJob.KubeJobSpec == nil && itemJob.Script == nil {
+				return false, fmt.Sprintf("item %s in co-schedule group %s can only run kube jobs and scripts, job %s is not",
+					node.Item.Name, group, itemJob.Name)
+			}
+		}
+
+		visited := map[string]bool{}
+		children := append([]*ItemNode{}, node.Child...)
+		for len(children) > 0 {
+			child := children[0]
+			children = children[1:]
+
+			if visited[child.Item.Name] {
+				continue
+			}
+			visited[child.Item.Name] = true
+
+			if child.Item.CoScheduleGroup == group {
+				return false, fmt.Sprintf("item %s and %s in co-schedule group %s depend on each other",
+					node.Item.Name, child.Item.Name, group)
+			}
+
+			children = append(children, child.Child...)
+		}
+	}
+
+	return true, ""
+}
+
 func JobExtendStr2Names(s string) []string {
 	return strings.Split(s, "->")
 }
@@ -133,12 +312,24 @@ func IsItemModuleResourceValid(modules ItemModuleResource) (bool, string) {
 		if cm.Name == "" {
 			return false, fmt.Sprintf("configmap name can not be nil")
 		}
+
+		for _, source := range cm.ValueFrom {
+			if source.Name == "" {
+				return false, fmt.Sprintf("configmap %s value source name can not be nil", cm.Name)
+			}
+		}
 	}
 
 	for _, sc := range modules.Secrets {
 		if sc.Name == "" {
 			return false, fmt.Sprintf("secret name can not be nil")
 		}
+
+		for _, source := range sc.ValueFrom {
+			if source.Name == "" {
+				return false, fmt.Sprintf("secret %s value source name can not be nil", sc.Name)
+			}
+		}
 	}
 
 	for _, pvc := range modules.Pvcs {
@@ -153,17 +344,103 @@ func IsItemModuleResourceValid(modules ItemModuleResource) (bool, string) {
 		}
 	}
 
+	for _, sa := range modules.ServiceAccounts {
+		if sa.Name == "" {
+			return false, fmt.Sprintf("service account name can not be nil")
+		}
+	}
+
+	roles := map[string]bool{}
+	for _, role := range modules.Roles {
+		if role.Name == "" {
+			return false, fmt.Sprintf("role name can not be nil")
+		}
+		roles[role.Name] = true
+
+		for _, rule := range role.Rules {
+			if len(rule.NonResourceURLs) != 0 {
+				return false, fmt.Sprintf("role %s can not have non resource urls", role.Name)
+			}
+		}
+	}
+
+	for _, rb := range modules.RoleBindings {
+		if rb.Name == "" {
+			return false, fmt.Sprintf("role binding name can not be nil")
+		}
+
+		if rb.RoleRef.APIGroup != "" && rb.RoleRef.APIGroup != rbacv1.GroupName {
+			return false, fmt.Sprintf("role binding %s role api group must be %s", rb.Name, rbacv1.GroupName)
+		}
+
+		switch rb.RoleRef.Kind {
+		case "Role":
+			// roles in namespace of job are not referred, which are granted to others by their owners
+			if !roles[rb.RoleRef.Name] {
+				return false, fmt.Sprintf("role binding %s must refer to a role of the item, role %s not found",
+					rb.Name, rb.RoleRef.Name)
+			}
+		case "ClusterRole":
+			if rb.RoleRef.Name == "" {
+				return false, fmt.Sprintf("role binding %s cluster role name can not be nil", rb.Name)
+			}
+		default:
+			return false, fmt.Sprintf("role binding %s must refer to a Role or ClusterRole by name", rb.Name)
+		}
+	}
+
+	for _, np := range modules.NetworkPolicies {
+		if np.Name == "" {
+			return false, fmt.Sprintf("network policy name can not be nil")
+		}
+	}
+
+	for _, ing := range modules.Ingresses {
+		if ing.Name == "" {
+			return false, fmt.Sprintf("ingress name can not be nil")
+		}
+	}
+
+	for _, pdb := range modules.PodDisruptionBudgets {
+		if pdb.Name == "" {
+			return false, fmt.Sprintf("pod disruption budget name can not be nil")
+		}
+	}
+
 	return true, ""
 }
 
 func IsItemJobResourceValid(jobs ItemJobResource) (bool, string) {
 	for _, job := range jobs.Jobs {
-		if job.VolcanoJobSpec == nil && job.KubeJobSpec == nil {
-			return false, fmt.Sprintf("kube_job and volcano_job can not be total nil")
+		specNum := 0
+		for _, set := range []bool{job.KubeJobSpec != nil, job.VolcanoJobSpec != nil, job.Script != nil, job.Unstructured != nil, job.JobSetSpec != nil} {
+			if set {
+				specNum++
+			}
 		}
 
-		if job.VolcanoJobSpec != nil && job.KubeJobSpec != nil {
-			return false, fmt.Sprintf("kube_job and volcano_job can not be total set")
+		if specNum == 0 {
+			return false, fmt.Sprintf("kube_job, volcano_job, script, unstructured and jobset can not be total nil")
+		}
+
+		if specNum > 1 {
+			return false, fmt.Sprintf("only one of kube_job, volcano_job, script, unstructured and jobset can be set")
+		}
+
+		if job.Script != nil && (job.Script.Image == "" || job.Script.Source == "") {
+			return false, fmt.Sprintf("script job %s image and source can not be nil", job.Name)
+		}
+
+		if job.Unstructured != nil {
+			if err := isUnstructuredJobValid(job.Unstructured); err != nil {
+				return false, fmt.Sprintf("unstructured job %s invalid: %s", job.Name, err.Error())
+			}
+		}
+
+		if job.JobSetSpec != nil {
+			if err := isJobSetSpecValid(job.JobSetSpec); err != nil {
+				return false, fmt.Sprintf("jobset %s invalid: %s", job.Name, err.Error())
+			}
 		}
 
 		if job.Name == "" {
@@ -225,31 +502,64 @@ func IsExtendNamesIllegal(names []string, itemNames map[string]*Item) bool {
 
 func IsJobHasCycle(job *Job) (bool, error) {
 
-	node, _, err := NewGraphFromJob(job)
+	node, nodeMap, err := NewGraphFromJob(job)
 	if err != nil {
 		return false, err
 	}
 
-	flag := IsJobHasCycleDfs(node, map[string]bool{})
+	finished := map[string]bool{}
+	if IsJobHasCycleDfs(node, map[string]bool{}, finished) {
+		return true, nil
+	}
+
+	// items not reachable from start item may still build a cycle among themselves
+	for _, node := range nodeMap {
+		if IsJobHasCycleDfs(node, map[string]bool{}, finished) {
+			return true, nil
+		}
+	}
 
-	return flag, nil
+	return false, nil
 }
 
-func IsJobHasCycleDfs(node *ItemNode, visited map[string]bool) bool {
+// IsJobHasCycleDfs visits the items after node, visited are the items on current path,
+// finished are the items whose children were all visited without a cycle.
+func IsJobHasCycleDfs(node *ItemNode, visited, finished map[string]bool) bool {
 
 	if visited[node.Item.Name] {
 		return true
 	}
+	if finished[node.Item.Name] {
+		return false
+	}
 
 	visited[node.Item.Name] = true
 
 	for _, child := range node.Child {
-		if IsJobHasCycleDfs(child, visited) {
+		if IsJobHasCycleDfs(child, visited, finished) {
 			return true
 		}
 	}
 
 	visited[node.Item.Name] = false
+	finished[node.Item.Name] = true
+
+	return false
+}
+
+// IsNamespaceAllowed returns true if namespace is listed in the allowed namespaces annotation of obj.
+func IsNamespaceAllowed(obj client.Object, namespace string) bool {
+	allowed, ok := obj.GetAnnotations()[AllowedNamespacesAnnotation]
+	if !ok {
+		return false
+	}
+
+	for _, name := range strings.Split(allowed, ",") {
+		name = strings.TrimSpace(name)
+		if name == "*" || name == namespace {
+			return true
+		}
+	}
 
 	return false
 }
@@ -0,0 +1,60 @@
+package v1alpha1
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	"testing"
+	volcanov1alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+func TestIsCoScheduleGroupValid(t *testing.T) {
+	kubeJob := ItemJobTemplate{TemplateBaseInfo: TemplateBaseInfo{Name: "kube"}, KubeJobSpec: &batchv1.JobSpec{}}
+	script := ItemJobTemplate{TemplateBaseInfo: TemplateBaseInfo{Name: "script"}, Script: &ScriptJobSpec{}}
+	volcanoJob := ItemJobTemplate{TemplateBaseInfo: TemplateBaseInfo{Name: "volcano"}, VolcanoJobSpec: &volcanov1alpha1.JobSpec{}}
+
+	tests := []struct {
+		name  string
+		items []Item
+		valid bool
+	}{
+		{
+			name: "kube jobs and scripts",
+			items: []Item{
+				{Name: "init"},
+				{Name: "a", RunAfter: []string{"init"}, CoScheduleGroup: "g", ItemJobs: ItemJobResource{Jobs: []ItemJobTemplate{kubeJob}}},
+				{Name: "b", RunAfter: []string{"init"}, CoScheduleGroup: "g", ItemJobs: ItemJobResource{Jobs: []ItemJobTemplate{script}}},
+			},
+			valid: true,
+		},
+		{
+			name: "volcano job in group",
+			items: []Item{
+				{Name: "init"},
+				{Name: "a", RunAfter: []string{"init"}, CoScheduleGroup: "g", ItemJobs: ItemJobResource{Jobs: []ItemJobTemplate{kubeJob}}},
+				{Name: "b", RunAfter: []string{"init"}, CoScheduleGroup: "g", ItemJobs: ItemJobResource{Jobs: []ItemJobTemplate{volcanoJob}}},
+			},
+		},
+		{
+			name: "volcano job out of group",
+			items: []Item{
+				{Name: "init", ItemJobs: ItemJobResource{Jobs: []ItemJobTemplate{volcanoJob}}},
+				{Name: "a", RunAfter: []string{"init"}, CoScheduleGroup: "g", ItemJobs: ItemJobResource{Jobs: []ItemJobTemplate{kubeJob}}},
+			},
+			valid: true,
+		},
+		{
+			name: "members depending on each other",
+			items: []Item{
+				{Name: "init"},
+				{Name: "a", RunAfter: []string{"init"}, CoScheduleGroup: "g"},
+				{Name: "b", RunAfter: []string{"a"}, CoScheduleGroup: "g"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		job := &Job{Spec: JobSpec{Items: test.items}}
+		if flag, msg := IsCoScheduleGroupValid(job); flag != test.valid {
+			t.Errorf("%s: expected valid %t, got %t: %s", test.name, test.valid, flag, msg)
+		}
+	}
+}
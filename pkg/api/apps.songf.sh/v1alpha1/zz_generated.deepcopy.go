@@ -21,11 +21,74 @@ limitations under the License.
 package v1alpha1
 
 import (
-	"k8s.io/api/batch/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	batchv1alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterJobTemplate) DeepCopyInto(out *ClusterJobTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterJobTemplate.
+func (in *ClusterJobTemplate) DeepCopy() *ClusterJobTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterJobTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterJobTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterJobTemplateList) DeepCopyInto(out *ClusterJobTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterJobTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterJobTemplateList.
+func (in *ClusterJobTemplateList) DeepCopy() *ClusterJobTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterJobTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterJobTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapTemplate) DeepCopyInto(out *ConfigMapTemplate) {
 	*out = *in
@@ -36,6 +99,13 @@ func (in *ConfigMapTemplate) DeepCopyInto(out *ConfigMapTemplate) {
 		**out = **in
 	}
 	in.ConfigMap.DeepCopyInto(&out.ConfigMap)
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = make([]ModuleValueSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapTemplate.
@@ -48,6 +118,71 @@ func (in *ConfigMapTemplate) DeepCopy() *ConfigMapTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GroupStatus) DeepCopyInto(out *GroupStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupStatus.
+func (in *GroupStatus) DeepCopy() *GroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPWait) DeepCopyInto(out *HTTPWait) {
+	*out = *in
+	if in.SuccessCodes != nil {
+		in, out := &in.SuccessCodes, &out.SuccessCodes
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPWait.
+func (in *HTTPWait) DeepCopy() *HTTPWait {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPWait)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressTemplate) DeepCopyInto(out *IngressTemplate) {
+	*out = *in
+	in.TemplateBaseInfo.DeepCopyInto(&out.TemplateBaseInfo)
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressTemplate.
+func (in *IngressTemplate) DeepCopy() *IngressTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Item) DeepCopyInto(out *Item) {
 	*out = *in
@@ -63,6 +198,38 @@ func (in *Item) DeepCopyInto(out *Item) {
 	}
 	in.ItemJobs.DeepCopyInto(&out.ItemJobs)
 	in.ItemModules.DeepCopyInto(&out.ItemModules)
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SubJob != nil {
+		in, out := &in.SubJob, &out.SubJob
+		*out = new(SubJobTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Loop != nil {
+		in, out := &in.Loop, &out.Loop
+		*out = new(ItemLoop)
+		**out = **in
+	}
+	if in.DependsOnJobs != nil {
+		in, out := &in.DependsOnJobs, &out.DependsOnJobs
+		*out = make([]JobDependency, len(*in))
+		copy(*out, *in)
+	}
+	if in.Wait != nil {
+		in, out := &in.Wait, &out.Wait
+		*out = new(WaitTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LongRunning != nil {
+		in, out := &in.LongRunning, &out.LongRunning
+		*out = new(LongRunningTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Item.
@@ -75,6 +242,78 @@ func (in *Item) DeepCopy() *Item {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ItemGroup) DeepCopyInto(out *ItemGroup) {
+	*out = *in
+	if in.RunAfter != nil {
+		in, out := &in.RunAfter, &out.RunAfter
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Item, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Parallelism != nil {
+		in, out := &in.Parallelism, &out.Parallelism
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ItemGroup.
+func (in *ItemGroup) DeepCopy() *ItemGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(ItemGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ItemIterationStatus) DeepCopyInto(out *ItemIterationStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ItemIterationStatus.
+func (in *ItemIterationStatus) DeepCopy() *ItemIterationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ItemIterationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ItemJobResource) DeepCopyInto(out *ItemJobResource) {
 	*out = *in
@@ -113,7 +352,7 @@ func (in *ItemJobTemplate) DeepCopyInto(out *ItemJobTemplate) {
 	}
 	if in.KubeJobSpec != nil {
 		in, out := &in.KubeJobSpec, &out.KubeJobSpec
-		*out = new(v1.JobSpec)
+		*out = new(batchv1.JobSpec)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.VolcanoJobSpec != nil {
@@ -121,6 +360,21 @@ func (in *ItemJobTemplate) DeepCopyInto(out *ItemJobTemplate) {
 		*out = new(batchv1alpha1.JobSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Script != nil {
+		in, out := &in.Script, &out.Script
+		*out = new(ScriptJobSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Unstructured != nil {
+		in, out := &in.Unstructured, &out.Unstructured
+		*out = new(UnstructuredJobSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JobSetSpec != nil {
+		in, out := &in.JobSetSpec, &out.JobSetSpec
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ItemJobTemplate.
@@ -133,6 +387,21 @@ func (in *ItemJobTemplate) DeepCopy() *ItemJobTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ItemLoop) DeepCopyInto(out *ItemLoop) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ItemLoop.
+func (in *ItemLoop) DeepCopy() *ItemLoop {
+	if in == nil {
+		return nil
+	}
+	out := new(ItemLoop)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ItemModuleResource) DeepCopyInto(out *ItemModuleResource) {
 	*out = *in
@@ -176,6 +445,48 @@ func (in *ItemModuleResource) DeepCopyInto(out *ItemModuleResource) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]ServiceAccountTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]RoleTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RoleBindings != nil {
+		in, out := &in.RoleBindings, &out.RoleBindings
+		*out = make([]RoleBindingTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NetworkPolicies != nil {
+		in, out := &in.NetworkPolicies, &out.NetworkPolicies
+		*out = make([]NetworkPolicyTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Ingresses != nil {
+		in, out := &in.Ingresses, &out.Ingresses
+		*out = make([]IngressTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodDisruptionBudgets != nil {
+		in, out := &in.PodDisruptionBudgets, &out.PodDisruptionBudgets
+		*out = make([]PodDisruptionBudgetTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ItemModuleResource.
@@ -188,6 +499,21 @@ func (in *ItemModuleResource) DeepCopy() *ItemModuleResource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ItemNameReference) DeepCopyInto(out *ItemNameReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ItemNameReference.
+func (in *ItemNameReference) DeepCopy() *ItemNameReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ItemNameReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ItemNode) DeepCopyInto(out *ItemNode) {
 	*out = *in
@@ -219,6 +545,21 @@ func (in *ItemNode) DeepCopy() *ItemNode {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ItemOutputReference) DeepCopyInto(out *ItemOutputReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ItemOutputReference.
+func (in *ItemOutputReference) DeepCopy() *ItemOutputReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ItemOutputReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ItemStatus) DeepCopyInto(out *ItemStatus) {
 	*out = *in
@@ -237,6 +578,33 @@ func (in *ItemStatus) DeepCopyInto(out *ItemStatus) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SubJobStatus != nil {
+		in, out := &in.SubJobStatus, &out.SubJobStatus
+		*out = new(SubJobStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Iterations != nil {
+		in, out := &in.Iterations, &out.Iterations
+		*out = make([]ItemIterationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.JobStatus != nil {
 		in, out := &in.JobStatus, &out.JobStatus
 		*out = make(map[string]batchv1alpha1.JobState, len(*in))
@@ -279,22 +647,79 @@ func (in *ItemStatus) DeepCopyInto(out *ItemStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ItemStatus.
-func (in *ItemStatus) DeepCopy() *ItemStatus {
-	if in == nil {
-		return nil
+	if in.ServiceAccountStatus != nil {
+		in, out := &in.ServiceAccountStatus, &out.ServiceAccountStatus
+		*out = make(map[string]RegularModuleStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
 	}
-	out := new(ItemStatus)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Job) DeepCopyInto(out *Job) {
-	*out = *in
-	out.TypeMeta = in.TypeMeta
+	if in.RoleStatus != nil {
+		in, out := &in.RoleStatus, &out.RoleStatus
+		*out = make(map[string]RegularModuleStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.RoleBindingStatus != nil {
+		in, out := &in.RoleBindingStatus, &out.RoleBindingStatus
+		*out = make(map[string]RegularModuleStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.NetworkPolicyStatus != nil {
+		in, out := &in.NetworkPolicyStatus, &out.NetworkPolicyStatus
+		*out = make(map[string]RegularModuleStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.IngressStatus != nil {
+		in, out := &in.IngressStatus, &out.IngressStatus
+		*out = make(map[string]RegularModuleStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.PodDisruptionBudgetStatus != nil {
+		in, out := &in.PodDisruptionBudgetStatus, &out.PodDisruptionBudgetStatus
+		*out = make(map[string]RegularModuleStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ItemStatus.
+func (in *ItemStatus) DeepCopy() *ItemStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ItemStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JSONPathWait) DeepCopyInto(out *JSONPathWait) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JSONPathWait.
+func (in *JSONPathWait) DeepCopy() *JSONPathWait {
+	if in == nil {
+		return nil
+	}
+	out := new(JSONPathWait)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Job) DeepCopyInto(out *Job) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
@@ -407,6 +832,21 @@ func (in *JobBatchStatus) DeepCopy() *JobBatchStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobDependency) DeepCopyInto(out *JobDependency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobDependency.
+func (in *JobDependency) DeepCopy() *JobDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(JobDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JobList) DeepCopyInto(out *JobList) {
 	*out = *in
@@ -439,6 +879,21 @@ func (in *JobList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobOutput) DeepCopyInto(out *JobOutput) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobOutput.
+func (in *JobOutput) DeepCopy() *JobOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(JobOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JobSpec) DeepCopyInto(out *JobSpec) {
 	*out = *in
@@ -454,6 +909,53 @@ func (in *JobSpec) DeepCopyInto(out *JobSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Parallelism != nil {
+		in, out := &in.Parallelism, &out.Parallelism
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxRunningPods != nil {
+		in, out := &in.MaxRunningPods, &out.MaxRunningPods
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(TemplateReference)
+		**out = **in
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]TemplateParameterValue, len(*in))
+		copy(*out, *in)
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]JobOutput, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]ItemGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NetworkIsolation != nil {
+		in, out := &in.NetworkIsolation, &out.NetworkIsolation
+		*out = new(NetworkIsolation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDefaults != nil {
+		in, out := &in.PodDefaults, &out.PodDefaults
+		*out = new(PodDefaults)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobSpec.
@@ -493,6 +995,30 @@ func (in *JobStatus) DeepCopyInto(out *JobStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.QueuePosition != nil {
+		in, out := &in.QueuePosition, &out.QueuePosition
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TemplateRevision != nil {
+		in, out := &in.TemplateRevision, &out.TemplateRevision
+		*out = new(TemplateRevision)
+		**out = **in
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.GroupStatus != nil {
+		in, out := &in.GroupStatus, &out.GroupStatus
+		*out = make(map[string]GroupStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobStatus.
@@ -506,106 +1032,178 @@ func (in *JobStatus) DeepCopy() *JobStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JobTemplateSpec) DeepCopyInto(out *JobTemplateSpec) {
+func (in *JobTemplate) DeepCopyInto(out *JobTemplate) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobTemplateSpec.
-func (in *JobTemplateSpec) DeepCopy() *JobTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobTemplate.
+func (in *JobTemplate) DeepCopy() *JobTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(JobTemplateSpec)
+	out := new(JobTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *JobTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PvTemplate) DeepCopyInto(out *PvTemplate) {
+func (in *JobTemplateDefinition) DeepCopyInto(out *JobTemplateDefinition) {
 	*out = *in
-	in.TemplateBaseInfo.DeepCopyInto(&out.TemplateBaseInfo)
-	if in.TTLSecondsAfterFinished != nil {
-		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
-		*out = new(int32)
-		**out = **in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]TemplateParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	in.Pv.DeepCopyInto(&out.Pv)
+	in.Template.DeepCopyInto(&out.Template)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PvTemplate.
-func (in *PvTemplate) DeepCopy() *PvTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobTemplateDefinition.
+func (in *JobTemplateDefinition) DeepCopy() *JobTemplateDefinition {
 	if in == nil {
 		return nil
 	}
-	out := new(PvTemplate)
+	out := new(JobTemplateDefinition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PvcTemplate) DeepCopyInto(out *PvcTemplate) {
+func (in *JobTemplateList) DeepCopyInto(out *JobTemplateList) {
 	*out = *in
-	in.TemplateBaseInfo.DeepCopyInto(&out.TemplateBaseInfo)
-	if in.TTLSecondsAfterFinished != nil {
-		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
-		*out = new(int32)
-		**out = **in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]JobTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	in.Pvc.DeepCopyInto(&out.Pvc)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PvcTemplate.
-func (in *PvcTemplate) DeepCopy() *PvcTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobTemplateList.
+func (in *JobTemplateList) DeepCopy() *JobTemplateList {
 	if in == nil {
 		return nil
 	}
-	out := new(PvcTemplate)
+	out := new(JobTemplateList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *JobTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RegularModuleStatus) DeepCopyInto(out *RegularModuleStatus) {
+func (in *JobTemplateSpec) DeepCopyInto(out *JobTemplateSpec) {
 	*out = *in
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegularModuleStatus.
-func (in *RegularModuleStatus) DeepCopy() *RegularModuleStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobTemplateSpec.
+func (in *JobTemplateSpec) DeepCopy() *JobTemplateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RegularModuleStatus)
+	out := new(JobTemplateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SecretTemplate) DeepCopyInto(out *SecretTemplate) {
+func (in *LongRunningTemplate) DeepCopyInto(out *LongRunningTemplate) {
 	*out = *in
 	in.TemplateBaseInfo.DeepCopyInto(&out.TemplateBaseInfo)
-	if in.TTLSecondsAfterFinished != nil {
-		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
-		*out = new(int32)
-		**out = **in
+	if in.Deployment != nil {
+		in, out := &in.Deployment, &out.Deployment
+		*out = new(appsv1.DeploymentSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StatefulSet != nil {
+		in, out := &in.StatefulSet, &out.StatefulSet
+		*out = new(appsv1.StatefulSetSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StopAfter != nil {
+		in, out := &in.StopAfter, &out.StopAfter
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	in.Secret.DeepCopyInto(&out.Secret)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretTemplate.
-func (in *SecretTemplate) DeepCopy() *SecretTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LongRunningTemplate.
+func (in *LongRunningTemplate) DeepCopy() *LongRunningTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(SecretTemplate)
+	out := new(LongRunningTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceTemplate) DeepCopyInto(out *ServiceTemplate) {
+func (in *ModuleValueSource) DeepCopyInto(out *ModuleValueSource) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleValueSource.
+func (in *ModuleValueSource) DeepCopy() *ModuleValueSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleValueSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkIsolation) DeepCopyInto(out *NetworkIsolation) {
+	*out = *in
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = make([]networkingv1.NetworkPolicyEgressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkIsolation.
+func (in *NetworkIsolation) DeepCopy() *NetworkIsolation {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkIsolation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyTemplate) DeepCopyInto(out *NetworkPolicyTemplate) {
 	*out = *in
 	in.TemplateBaseInfo.DeepCopyInto(&out.TemplateBaseInfo)
 	if in.TTLSecondsAfterFinished != nil {
@@ -616,18 +1214,18 @@ func (in *ServiceTemplate) DeepCopyInto(out *ServiceTemplate) {
 	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceTemplate.
-func (in *ServiceTemplate) DeepCopy() *ServiceTemplate {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyTemplate.
+func (in *NetworkPolicyTemplate) DeepCopy() *NetworkPolicyTemplate {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceTemplate)
+	out := new(NetworkPolicyTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TemplateBaseInfo) DeepCopyInto(out *TemplateBaseInfo) {
+func (in *PodDefaults) DeepCopyInto(out *PodDefaults) {
 	*out = *in
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
@@ -643,14 +1241,790 @@ func (in *TemplateBaseInfo) DeepCopyInto(out *TemplateBaseInfo) {
 			(*out)[key] = val
 		}
 	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateBaseInfo.
-func (in *TemplateBaseInfo) DeepCopy() *TemplateBaseInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDefaults.
+func (in *PodDefaults) DeepCopy() *PodDefaults {
 	if in == nil {
 		return nil
 	}
-	out := new(TemplateBaseInfo)
+	out := new(PodDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudgetTemplate) DeepCopyInto(out *PodDisruptionBudgetTemplate) {
+	*out = *in
+	in.TemplateBaseInfo.DeepCopyInto(&out.TemplateBaseInfo)
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetTemplate.
+func (in *PodDisruptionBudgetTemplate) DeepCopy() *PodDisruptionBudgetTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudgetTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PvTemplate) DeepCopyInto(out *PvTemplate) {
+	*out = *in
+	in.TemplateBaseInfo.DeepCopyInto(&out.TemplateBaseInfo)
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	in.Pv.DeepCopyInto(&out.Pv)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PvTemplate.
+func (in *PvTemplate) DeepCopy() *PvTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(PvTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PvcTemplate) DeepCopyInto(out *PvcTemplate) {
+	*out = *in
+	in.TemplateBaseInfo.DeepCopyInto(&out.TemplateBaseInfo)
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	in.Pvc.DeepCopyInto(&out.Pvc)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PvcTemplate.
+func (in *PvcTemplate) DeepCopy() *PvcTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(PvcTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Queue) DeepCopyInto(out *Queue) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Queue.
+func (in *Queue) DeepCopy() *Queue {
+	if in == nil {
+		return nil
+	}
+	out := new(Queue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Queue) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueueList) DeepCopyInto(out *QueueList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Queue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueueList.
+func (in *QueueList) DeepCopy() *QueueList {
+	if in == nil {
+		return nil
+	}
+	out := new(QueueList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QueueList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueueSpec) DeepCopyInto(out *QueueSpec) {
+	*out = *in
+	if in.MaxRunningJobs != nil {
+		in, out := &in.MaxRunningJobs, &out.MaxRunningJobs
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxRunningItems != nil {
+		in, out := &in.MaxRunningItems, &out.MaxRunningItems
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.NamespaceWeights != nil {
+		in, out := &in.NamespaceWeights, &out.NamespaceWeights
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueueSpec.
+func (in *QueueSpec) DeepCopy() *QueueSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QueueSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueueStatus) DeepCopyInto(out *QueueStatus) {
+	*out = *in
+	if in.Allocated != nil {
+		in, out := &in.Allocated, &out.Allocated
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueueStatus.
+func (in *QueueStatus) DeepCopy() *QueueStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(QueueStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegularModuleStatus) DeepCopyInto(out *RegularModuleStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegularModuleStatus.
+func (in *RegularModuleStatus) DeepCopy() *RegularModuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RegularModuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceWait) DeepCopyInto(out *ResourceWait) {
+	*out = *in
+	in.StatusRule.DeepCopyInto(&out.StatusRule)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceWait.
+func (in *ResourceWait) DeepCopy() *ResourceWait {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceWait)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleBindingTemplate) DeepCopyInto(out *RoleBindingTemplate) {
+	*out = *in
+	in.TemplateBaseInfo.DeepCopyInto(&out.TemplateBaseInfo)
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	out.RoleRef = in.RoleRef
+	if in.Subjects != nil {
+		in, out := &in.Subjects, &out.Subjects
+		*out = make([]rbacv1.Subject, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleBindingTemplate.
+func (in *RoleBindingTemplate) DeepCopy() *RoleBindingTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleBindingTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleTemplate) DeepCopyInto(out *RoleTemplate) {
+	*out = *in
+	in.TemplateBaseInfo.DeepCopyInto(&out.TemplateBaseInfo)
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleTemplate.
+func (in *RoleTemplate) DeepCopy() *RoleTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledJob) DeepCopyInto(out *ScheduledJob) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledJob.
+func (in *ScheduledJob) DeepCopy() *ScheduledJob {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduledJob) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledJobList) DeepCopyInto(out *ScheduledJobList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ScheduledJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledJobList.
+func (in *ScheduledJobList) DeepCopy() *ScheduledJobList {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledJobList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduledJobList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledJobSpec) DeepCopyInto(out *ScheduledJobSpec) {
+	*out = *in
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
+	}
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	in.JobTemplate.DeepCopyInto(&out.JobTemplate)
+	if in.SuccessfulJobsHistoryLimit != nil {
+		in, out := &in.SuccessfulJobsHistoryLimit, &out.SuccessfulJobsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedJobsHistoryLimit != nil {
+		in, out := &in.FailedJobsHistoryLimit, &out.FailedJobsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledJobSpec.
+func (in *ScheduledJobSpec) DeepCopy() *ScheduledJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledJobStatus) DeepCopyInto(out *ScheduledJobStatus) {
+	*out = *in
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]v1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledJobStatus.
+func (in *ScheduledJobStatus) DeepCopy() *ScheduledJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScriptJobSpec) DeepCopyInto(out *ScriptJobSpec) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScriptJobSpec.
+func (in *ScriptJobSpec) DeepCopy() *ScriptJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScriptJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretTemplate) DeepCopyInto(out *SecretTemplate) {
+	*out = *in
+	in.TemplateBaseInfo.DeepCopyInto(&out.TemplateBaseInfo)
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	in.Secret.DeepCopyInto(&out.Secret)
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = make([]ModuleValueSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EncryptedData != nil {
+		in, out := &in.EncryptedData, &out.EncryptedData
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretTemplate.
+func (in *SecretTemplate) DeepCopy() *SecretTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountTemplate) DeepCopyInto(out *ServiceAccountTemplate) {
+	*out = *in
+	in.TemplateBaseInfo.DeepCopyInto(&out.TemplateBaseInfo)
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	in.ServiceAccount.DeepCopyInto(&out.ServiceAccount)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountTemplate.
+func (in *ServiceAccountTemplate) DeepCopy() *ServiceAccountTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceTemplate) DeepCopyInto(out *ServiceTemplate) {
+	*out = *in
+	in.TemplateBaseInfo.DeepCopyInto(&out.TemplateBaseInfo)
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceTemplate.
+func (in *ServiceTemplate) DeepCopy() *ServiceTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatusRule) DeepCopyInto(out *StatusRule) {
+	*out = *in
+	if in.Condition != nil {
+		in, out := &in.Condition, &out.Condition
+		*out = new(WaitCondition)
+		**out = **in
+	}
+	if in.JSONPath != nil {
+		in, out := &in.JSONPath, &out.JSONPath
+		*out = new(JSONPathWait)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatusRule.
+func (in *StatusRule) DeepCopy() *StatusRule {
+	if in == nil {
+		return nil
+	}
+	out := new(StatusRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubJobStatus) DeepCopyInto(out *SubJobStatus) {
+	*out = *in
+	in.State.DeepCopyInto(&out.State)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubJobStatus.
+func (in *SubJobStatus) DeepCopy() *SubJobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SubJobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubJobTemplate) DeepCopyInto(out *SubJobTemplate) {
+	*out = *in
+	in.TemplateBaseInfo.DeepCopyInto(&out.TemplateBaseInfo)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubJobTemplate.
+func (in *SubJobTemplate) DeepCopy() *SubJobTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(SubJobTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateBaseInfo) DeepCopyInto(out *TemplateBaseInfo) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateBaseInfo.
+func (in *TemplateBaseInfo) DeepCopy() *TemplateBaseInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateBaseInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateParameter) DeepCopyInto(out *TemplateParameter) {
+	*out = *in
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateParameter.
+func (in *TemplateParameter) DeepCopy() *TemplateParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateParameterValue) DeepCopyInto(out *TemplateParameterValue) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateParameterValue.
+func (in *TemplateParameterValue) DeepCopy() *TemplateParameterValue {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateParameterValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateReference) DeepCopyInto(out *TemplateReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateReference.
+func (in *TemplateReference) DeepCopy() *TemplateReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateRevision) DeepCopyInto(out *TemplateRevision) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateRevision.
+func (in *TemplateRevision) DeepCopy() *TemplateRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnstructuredJobSpec) DeepCopyInto(out *UnstructuredJobSpec) {
+	*out = *in
+	in.Manifest.DeepCopyInto(&out.Manifest)
+	in.SuccessRule.DeepCopyInto(&out.SuccessRule)
+	if in.FailureRule != nil {
+		in, out := &in.FailureRule, &out.FailureRule
+		*out = new(StatusRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RunningRule != nil {
+		in, out := &in.RunningRule, &out.RunningRule
+		*out = new(StatusRule)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnstructuredJobSpec.
+func (in *UnstructuredJobSpec) DeepCopy() *UnstructuredJobSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UnstructuredJobSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WaitCondition) DeepCopyInto(out *WaitCondition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WaitCondition.
+func (in *WaitCondition) DeepCopy() *WaitCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(WaitCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WaitTemplate) DeepCopyInto(out *WaitTemplate) {
+	*out = *in
+	if in.Resource != nil {
+		in, out := &in.Resource, &out.Resource
+		*out = new(ResourceWait)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPWait)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WaitTemplate.
+func (in *WaitTemplate) DeepCopy() *WaitTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(WaitTemplate)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -8,6 +8,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
 	"sync"
+	"time"
 	alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 )
 
@@ -23,14 +24,25 @@ type JobItemGraph struct {
 
 	workNodes map[string]*v1alpha1.ItemNode
 
+	// itemIndex is the index of item in job spec, used to keep the declared order
+	itemIndex map[string]int
+
 	itemStatus map[string]*v1alpha1.ItemStatus
+
+	// retryLimits is the max num of retries of items, set by the retry policy of groups
+	retryLimits map[string]int32
+
+	// historySource is the source of job in ItemDurationHistory
+	historySource string
 }
 
 func NewJobItemGraph() *JobItemGraph {
 	return &JobItemGraph{
 		startItemNode: &v1alpha1.ItemNode{},
 		workNodes:     map[string]*v1alpha1.ItemNode{},
+		itemIndex:     map[string]int{},
 		itemStatus:    map[string]*v1alpha1.ItemStatus{},
+		retryLimits:   map[string]int32{},
 	}
 }
 
@@ -54,6 +66,20 @@ func (t *JobItemGraph) GetItemStatus(itemName string) (*v1alpha1.ItemStatus, boo
 	return status.DeepCopy(), true
 }
 
+// GetItem returns the spec of item, false if item is not in graph yet.
+func (t *JobItemGraph) GetItem(itemName string) (*v1alpha1.Item, bool) {
+
+	t.Lock()
+	defer t.Unlock()
+
+	node, ok := t.workNodes[itemName]
+	if !ok || node.Item == nil {
+		return nil, false
+	}
+
+	return node.Item.DeepCopy(), true
+}
+
 func (t *JobItemGraph) GetAllItemStatus() map[string]*v1alpha1.ItemStatus {
 
 	t.Lock()
@@ -68,6 +94,8 @@ func (t *JobItemGraph) GetAllItemStatus() map[string]*v1alpha1.ItemStatus {
 	return res
 }
 
+// SyncFromJob merges the topology of job into graph, it is called on every reconcile so that updates of
+// running job take effect. Status of known items is kept, items added by update start pending.
 func (t *JobItemGraph) SyncFromJob(job *v1alpha1.Job) error {
 
 	var err error
@@ -75,12 +103,19 @@ func (t *JobItemGraph) SyncFromJob(job *v1alpha1.Job) error {
 	t.Name = job.Name
 	t.Uuid = job.UID
 	t.NameSpace = job.Namespace
+	t.historySource = DurationHistorySource(job)
 	if job.DeletionTimestamp != nil {
 		t.DeleteTimestamp = new(metav1.Time)
 		*t.DeleteTimestamp = *job.DeletionTimestamp
 	}
 
-	for _, item := range job.Spec.Items {
+	for i, item := range v1alpha1.CalJobItems(job) {
+		t.itemIndex[item.Name] = i
+
+		if group := v1alpha1.GetItemGroup(job, &item); group != nil && group.BackoffLimit != nil {
+			t.retryLimits[item.Name] = *group.BackoffLimit
+		}
+
 		_, ok := t.itemStatus[item.Name]
 		if !ok {
 			flag := len(job.Status.ItemStatus) == 0
@@ -96,6 +131,11 @@ func (t *JobItemGraph) SyncFromJob(job *v1alpha1.Job) error {
 			} else {
 				status := job.Status.ItemStatus[item.Name]
 				t.itemStatus[item.Name] = status.DeepCopy()
+
+				// durations are kept in the status of jobs, history is rebuilt from them once operator restarted
+				if status.Phase == v1alpha1.ItemCompleted && status.StartTime != nil && status.CompletionTime != nil {
+					ItemDurationHistory.Observe(t.historySource, item.Name, status.CompletionTime.Sub(status.StartTime.Time))
+				}
 			}
 		}
 
@@ -109,6 +149,99 @@ func (t *JobItemGraph) SyncFromJob(job *v1alpha1.Job) error {
 	return nil
 }
 
+// SetItemScheduling marks item dispatched, so that it won't be scheduled again.
+func (t *JobItemGraph) SetItemScheduling(itemName string) {
+	t.Lock()
+	defer t.Unlock()
+
+	now := metav1.Now()
+
+	status, ok := t.itemStatus[itemName]
+	if !ok {
+		status = &v1alpha1.ItemStatus{
+			Name: itemName,
+		}
+	}
+
+	status.Phase = v1alpha1.ItemScheduling
+	status.Reason = ""
+	status.Message = ""
+	status.StartTime = &now
+	status.CompletionTime = nil
+
+	t.itemStatus[itemName] = status
+}
+
+// SetItemPendingReason records why a ready item is still pending.
+func (t *JobItemGraph) SetItemPendingReason(itemName, reason, message string) {
+	t.Lock()
+	defer t.Unlock()
+
+	status, ok := t.itemStatus[itemName]
+	if !ok || status.Phase != v1alpha1.ItemPending {
+		return
+	}
+
+	status.Reason = reason
+	status.Message = message
+}
+
+// SetItemWaitingAdmission records that the job of scheduled item is waiting for admission of Kueue, message
+// from its Workload tells why.
+func (t *JobItemGraph) SetItemWaitingAdmission(itemName, jobName, message string) {
+	t.Lock()
+	defer t.Unlock()
+
+	status, ok := t.itemStatus[itemName]
+	if !ok || status.Phase != v1alpha1.ItemScheduled {
+		return
+	}
+
+	state, ok := status.JobStatus[jobName]
+	if !ok || state.Reason != v1alpha1.WaitingAdmissionReason {
+		return
+	}
+
+	state.Message = message
+	status.JobStatus[jobName] = state
+
+	status.Reason = v1alpha1.WaitingAdmissionReason
+	status.Message = message
+}
+
+// HasItemJob returns true if any item of graph has created the job of name.
+func (t *JobItemGraph) HasItemJob(jobName string) bool {
+	t.RLock()
+	defer t.RUnlock()
+
+	for _, status := range t.itemStatus {
+		if _, ok := status.JobStatus[jobName]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RunningItemNum returns the num of items in Scheduling or Scheduled phase, and the num of pods they want.
+func (t *JobItemGraph) RunningItemNum() (itemNum, podNum int32) {
+	t.RLock()
+	defer t.RUnlock()
+
+	for itemName, status := range t.itemStatus {
+		switch status.Phase {
+		case v1alpha1.ItemScheduling, v1alpha1.ItemScheduled:
+			itemNum++
+
+			if node, ok := t.workNodes[itemName]; ok {
+				podNum += v1alpha1.CalItemPodNum(node.Item)
+			}
+		}
+	}
+
+	return itemNum, podNum
+}
+
 func (t *JobItemGraph) SyncFromObject(object client.Object, fn func(status *v1alpha1.ItemStatus)) error {
 	_, itemName := v1alpha1.GetJobNameAndItemNameFromObject(object)
 
@@ -126,6 +259,7 @@ func (t *JobItemGraph) SyncFromObject(object client.Object, fn func(status *v1al
 
 	switch status.Phase {
 	case v1alpha1.ItemScheduling, v1alpha1.ItemScheduled:
+		initItemStatusMaps(status)
 		fn(status)
 
 	case v1alpha1.ItemPending:
@@ -148,6 +282,46 @@ func (t *JobItemGraph) SyncFromObject(object client.Object, fn func(status *v1al
 	return nil
 }
 
+// initItemStatusMaps makes the status maps of objects writable.
+func initItemStatusMaps(status *v1alpha1.ItemStatus) {
+	if status.JobStatus == nil {
+		status.JobStatus = map[string]alpha1.JobState{}
+	}
+	if status.ServiceStatus == nil {
+		status.ServiceStatus = map[string]v1alpha1.RegularModuleStatus{}
+	}
+	if status.ConfigMapStatus == nil {
+		status.ConfigMapStatus = map[string]v1alpha1.RegularModuleStatus{}
+	}
+	if status.SecretStatus == nil {
+		status.SecretStatus = map[string]v1alpha1.RegularModuleStatus{}
+	}
+	if status.PvcStatus == nil {
+		status.PvcStatus = map[string]v1alpha1.RegularModuleStatus{}
+	}
+	if status.PvStatus == nil {
+		status.PvStatus = map[string]v1alpha1.RegularModuleStatus{}
+	}
+	if status.ServiceAccountStatus == nil {
+		status.ServiceAccountStatus = map[string]v1alpha1.RegularModuleStatus{}
+	}
+	if status.RoleStatus == nil {
+		status.RoleStatus = map[string]v1alpha1.RegularModuleStatus{}
+	}
+	if status.RoleBindingStatus == nil {
+		status.RoleBindingStatus = map[string]v1alpha1.RegularModuleStatus{}
+	}
+	if status.NetworkPolicyStatus == nil {
+		status.NetworkPolicyStatus = map[string]v1alpha1.RegularModuleStatus{}
+	}
+	if status.IngressStatus == nil {
+		status.IngressStatus = map[string]v1alpha1.RegularModuleStatus{}
+	}
+	if status.PodDisruptionBudgetStatus == nil {
+		status.PodDisruptionBudgetStatus = map[string]v1alpha1.RegularModuleStatus{}
+	}
+}
+
 func (t *JobItemGraph) SyncStatusPhase() {
 	for itemName, _ := range t.workNodes {
 		t.syncItemStatusPhase(itemName)
@@ -167,7 +341,23 @@ func (t *JobItemGraph) syncItemStatusPhase(itemName string) {
 		return
 	}
 
+	// finished items keep their phase, even if their objects are deleted later
+	if status.Phase == v1alpha1.ItemCompleted || status.Phase == v1alpha1.ItemFailed {
+		return
+	}
+
+	jobNames := make([]string, 0, len(workNode.Item.ItemJobs.Jobs)+1)
+	for _, job := range workNode.Item.ItemJobs.Jobs {
+		jobNames = append(jobNames, v1alpha1.CalItemJobName(t.Name, workNode.Item, job.Name, status.Iteration))
+	}
+
+	// workload of long-running item counts as one more job of item, which is completed once ready
+	if workNode.Item.LongRunning != nil {
+		jobNames = append(jobNames, v1alpha1.CalItemJobName(t.Name, workNode.Item, workNode.Item.LongRunning.Name, status.Iteration))
+	}
+
 	jobStateNotFoundNum := 0
+	jobNum := int32(len(jobNames))
 
 	if status.RunningJobNum != nil {
 		*status.RunningJobNum = 0
@@ -179,15 +369,19 @@ func (t *JobItemGraph) syncItemStatusPhase(itemName string) {
 		*status.FailedJobNum = 0
 	}
 
-	for _, job := range workNode.Item.ItemJobs.Jobs {
-		name := v1alpha1.CalJobItemSubName(t.Name, itemName, job.Name)
+	waitingAdmission := false
 
+	for _, name := range jobNames {
 		state, ok := status.JobStatus[name]
 		if !ok {
 			jobStateNotFoundNum++
 			continue
 		}
 
+		if state.Reason == v1alpha1.WaitingAdmissionReason {
+			waitingAdmission = true
+		}
+
 		switch state.Phase {
 		case alpha1.Running:
 			if status.RunningJobNum == nil {
@@ -212,19 +406,277 @@ func (t *JobItemGraph) syncItemStatusPhase(itemName string) {
 		}
 	}
 
+	// sub job counts as one more job of item
+	if workNode.Item.SubJob != nil {
+		jobNum++
+
+		// sub job of previous iteration of loop item is not counted
+		subJobName := v1alpha1.CalItemJobName(t.Name, workNode.Item, workNode.Item.SubJob.Name, status.Iteration)
+
+		if status.SubJobStatus == nil || status.SubJobStatus.Name != subJobName {
+			jobStateNotFoundNum++
+		} else {
+			switch status.SubJobStatus.State.Phase {
+			case v1alpha1.Completed:
+				if status.CompletedJobNum == nil {
+					var i int32
+					status.CompletedJobNum = &i
+				}
+				*status.CompletedJobNum++
+
+			case v1alpha1.Failed, v1alpha1.Terminating, v1alpha1.Terminated:
+				if status.FailedJobNum == nil {
+					var i int32
+					status.FailedJobNum = &i
+				}
+				*status.FailedJobNum++
+
+			default:
+				if status.RunningJobNum == nil {
+					var i int32
+					status.RunningJobNum = &i
+				}
+				*status.RunningJobNum++
+			}
+		}
+	}
+
 	if status.FailedJobNum != nil && *status.FailedJobNum > 0 {
 		status.Phase = v1alpha1.ItemFailed
-	} else if status.CompletedJobNum != nil && *status.CompletedJobNum == int32(len(workNode.Item.ItemJobs.Jobs)) {
+	} else if status.CompletedJobNum != nil && *status.CompletedJobNum == jobNum {
 		status.Phase = v1alpha1.ItemCompleted
 	} else if jobStateNotFoundNum == 0 {
 		status.Phase = v1alpha1.ItemScheduled
 	}
 
+	// the reason set by SetItemWaitingAdmission is kept until no job of item waits for admission
+	if status.Reason == v1alpha1.WaitingAdmissionReason && (!waitingAdmission || status.Phase != v1alpha1.ItemScheduled) {
+		status.Reason = ""
+		status.Message = ""
+	}
+
+	switch status.Phase {
+	case v1alpha1.ItemCompleted, v1alpha1.ItemFailed:
+		if status.CompletionTime == nil {
+			now := metav1.Now()
+			status.CompletionTime = &now
+
+			if status.Phase == v1alpha1.ItemCompleted && status.StartTime != nil {
+				ItemDurationHistory.Observe(t.historySource, itemName, now.Sub(status.StartTime.Time))
+			}
+		}
+	}
+
 	t.itemStatus[itemName] = status
 
 	return
 }
 
+// SetItemFailed fails item which can not be dispatched.
+func (t *JobItemGraph) SetItemFailed(itemName, reason, message string) {
+	t.Lock()
+	defer t.Unlock()
+
+	now := metav1.Now()
+
+	status, ok := t.itemStatus[itemName]
+	if !ok {
+		status = &v1alpha1.ItemStatus{
+			Name: itemName,
+		}
+	}
+
+	status.Phase = v1alpha1.ItemFailed
+	status.Reason = reason
+	status.Message = message
+	status.CompletionTime = &now
+
+	t.itemStatus[itemName] = status
+}
+
+// SetItemCompleted completes item which runs no job, like wait item.
+func (t *JobItemGraph) SetItemCompleted(itemName, reason, message string) {
+	t.Lock()
+	defer t.Unlock()
+
+	now := metav1.Now()
+
+	status, ok := t.itemStatus[itemName]
+	if !ok {
+		status = &v1alpha1.ItemStatus{
+			Name: itemName,
+		}
+	}
+
+	status.Phase = v1alpha1.ItemCompleted
+	status.Reason = reason
+	status.Message = message
+	status.CompletionTime = &now
+
+	if status.StartTime != nil {
+		ItemDurationHistory.Observe(t.historySource, itemName, now.Sub(status.StartTime.Time))
+	}
+
+	t.itemStatus[itemName] = status
+}
+
+// SetItemReason records why dispatched item is not finished yet.
+func (t *JobItemGraph) SetItemReason(itemName, reason, message string) {
+	t.Lock()
+	defer t.Unlock()
+
+	status, ok := t.itemStatus[itemName]
+	if !ok {
+		return
+	}
+
+	switch status.Phase {
+	case v1alpha1.ItemScheduling, v1alpha1.ItemScheduled:
+		status.Reason = reason
+		status.Message = message
+	}
+}
+
+// ResetItemForRetry makes a finished item pending again to be dispatched, and counts the retry.
+func (t *JobItemGraph) ResetItemForRetry(itemName, message string) {
+	t.Lock()
+	defer t.Unlock()
+
+	newStatus := &v1alpha1.ItemStatus{
+		Name:    itemName,
+		Phase:   v1alpha1.ItemPending,
+		Reason:  "Retrying",
+		Message: message,
+	}
+
+	// retry reruns the current iteration of loop item
+	if status, ok := t.itemStatus[itemName]; ok {
+		newStatus.RetryCount = status.RetryCount
+		newStatus.Iteration = status.Iteration
+		newStatus.Iterations = status.Iterations
+	}
+	newStatus.RetryCount++
+
+	t.itemStatus[itemName] = newStatus
+}
+
+// NextItemIteration makes a completed loop item pending again to run its next iteration.
+func (t *JobItemGraph) NextItemIteration(itemName, message string) {
+	t.Lock()
+	defer t.Unlock()
+
+	newStatus := &v1alpha1.ItemStatus{
+		Name:    itemName,
+		Phase:   v1alpha1.ItemPending,
+		Reason:  "NextIteration",
+		Message: message,
+	}
+
+	if status, ok := t.itemStatus[itemName]; ok {
+		v1alpha1.RecordItemIteration(status)
+
+		newStatus.RetryCount = status.RetryCount
+		newStatus.Iteration = status.Iteration + 1
+		newStatus.Iterations = status.Iterations
+	}
+
+	t.itemStatus[itemName] = newStatus
+}
+
+// RecordItemIteration records the current iteration of loop item into its status.
+func (t *JobItemGraph) RecordItemIteration(itemName string) {
+	t.Lock()
+	defer t.Unlock()
+
+	if status, ok := t.itemStatus[itemName]; ok {
+		v1alpha1.RecordItemIteration(status)
+	}
+}
+
+// ItemsToCollectOutputs returns the completed items whose outputs are not collected yet.
+func (t *JobItemGraph) ItemsToCollectOutputs() []*v1alpha1.Item {
+	t.RLock()
+	defer t.RUnlock()
+
+	var res []*v1alpha1.Item
+	for itemName, status := range t.itemStatus {
+		if status.Phase != v1alpha1.ItemCompleted || status.Outputs != nil {
+			continue
+		}
+
+		if node, ok := t.workNodes[itemName]; ok {
+			res = append(res, node.Item.DeepCopy())
+		}
+	}
+
+	return res
+}
+
+func (t *JobItemGraph) SetItemOutputs(itemName string, outputs map[string]string) {
+	t.Lock()
+	defer t.Unlock()
+
+	status, ok := t.itemStatus[itemName]
+	if !ok {
+		return
+	}
+
+	status.Outputs = map[string]string{}
+	for k, v := range outputs {
+		status.Outputs[k] = v
+	}
+}
+
+// GetAllItemOutputs returns the outputs of items collected, key is item name.
+func (t *JobItemGraph) GetAllItemOutputs() map[string]map[string]string {
+	t.RLock()
+	defer t.RUnlock()
+
+	res := map[string]map[string]string{}
+	for itemName, status := range t.itemStatus {
+		if status.Outputs == nil {
+			continue
+		}
+
+		outputs := map[string]string{}
+		for k, v := range status.Outputs {
+			outputs[k] = v
+		}
+		res[itemName] = outputs
+	}
+
+	return res
+}
+
+// IsItemSettled returns false if finished item may still run again, that is, a failed item to be retried
+// or a completed loop item whose loop is not decided to stop yet.
+func (t *JobItemGraph) IsItemSettled(status *v1alpha1.ItemStatus) bool {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.isItemSettled(status)
+}
+
+func (t *JobItemGraph) isItemSettled(status *v1alpha1.ItemStatus) bool {
+	switch status.Phase {
+	case v1alpha1.ItemFailed:
+		return status.RetryCount >= t.retryLimits[status.Name]
+
+	case v1alpha1.ItemCompleted:
+		node, ok := t.workNodes[status.Name]
+		if !ok || node.Item.Loop == nil {
+			return true
+		}
+
+		// the loop stops once the completed iteration is recorded
+		n := len(status.Iterations)
+		return n > 0 && status.Iterations[n-1].Iteration == status.Iteration &&
+			status.Iterations[n-1].Phase == v1alpha1.ItemCompleted
+	}
+
+	return true
+}
+
 func (t *JobItemGraph) ItemsNext2Scheduled() []*v1alpha1.Item {
 	var res []*v1alpha1.Item
 
@@ -233,27 +685,124 @@ func (t *JobItemGraph) ItemsNext2Scheduled() []*v1alpha1.Item {
 			continue
 		}
 
+		// status may be kept for item removed from graph, like item of an older job spec
+		node, ok := t.workNodes[itemName]
+		if !ok || node.Item == nil {
+			klog.Warningf("not found item %s from %s/%s tree while scheduling", itemName, t.NameSpace, t.Name)
+			continue
+		}
+
 		flag := true
-		for _, fatherItemName := range t.workNodes[itemName].Item.RunAfter {
+		for _, fatherItemName := range node.Item.RunAfter {
 			status, ok := t.itemStatus[fatherItemName]
 			if !ok {
 				flag = false
 				break
 			}
 
-			if status.Phase != v1alpha1.ItemCompleted {
+			if status.Phase != v1alpha1.ItemCompleted || !t.isItemSettled(status) {
 				flag = false
 				break
 			}
 
-			if t.workNodes[itemName].Item.Truncated != nil && *t.workNodes[itemName].Item.Truncated {
+			if node.Item.Truncated != nil && *node.Item.Truncated {
 				flag = false
 				break
 			}
 		}
 
 		if flag {
-			res = append(res, t.workNodes[itemName].Item.DeepCopy())
+			res = append(res, node.Item.DeepCopy())
+		}
+	}
+
+	return t.filterCoScheduleGroupReady(res)
+}
+
+// filterCoScheduleGroupReady removes the ready items whose co-schedule group members are not all ready,
+// so that the members of a group are always returned together. Members finished and not running again are
+// not waited for, otherwise a retried member would never be returned.
+func (t *JobItemGraph) filterCoScheduleGroupReady(items []*v1alpha1.Item) []*v1alpha1.Item {
+	ready := map[string]bool{}
+	for _, item := range items {
+		ready[item.Name] = true
+	}
+
+	groupReady := map[string]bool{}
+	for itemName, node := range t.workNodes {
+		group := node.Item.CoScheduleGroup
+		if group == "" || (node.Item.Truncated != nil && *node.Item.Truncated) {
+			continue
+		}
+
+		if status, ok := t.itemStatus[itemName]; ok && t.isItemFinished(status) {
+			continue
+		}
+
+		if _, ok := groupReady[group]; !ok {
+			groupReady[group] = true
+		}
+
+		if !ready[itemName] {
+			groupReady[group] = false
+		}
+	}
+
+	var res []*v1alpha1.Item
+	for _, item := range items {
+		if item.CoScheduleGroup != "" && !groupReady[item.CoScheduleGroup] {
+			if status, ok := t.itemStatus[item.Name]; ok {
+				status.Reason = "WaitingForCoScheduleGroup"
+				status.Message = fmt.Sprintf("waiting for other items in co-schedule group %s", item.CoScheduleGroup)
+			}
+			continue
+		}
+
+		res = append(res, item)
+	}
+
+	return res
+}
+
+// isItemFinished returns true if item finished and will not run again.
+func (t *JobItemGraph) isItemFinished(status *v1alpha1.ItemStatus) bool {
+	if status.Phase != v1alpha1.ItemCompleted && status.Phase != v1alpha1.ItemFailed {
+		return false
+	}
+
+	return t.isItemSettled(status)
+}
+
+// ItemsNext2ScheduledByPolicy returns the items to dispatch decided by policy, in the order of dispatching.
+func (t *JobItemGraph) ItemsNext2ScheduledByPolicy(policy SchedulingPolicy) []*v1alpha1.Item {
+	return policy.Schedule(t, t.ItemsNext2Scheduled())
+}
+
+// getHistorySource returns the source of graph in ItemDurationHistory.
+func (t *JobItemGraph) getHistorySource() string {
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.historySource
+}
+
+// itemReadyTime returns the time all parents of item completed.
+func (t *JobItemGraph) itemReadyTime(itemName string) time.Time {
+	var res time.Time
+
+	node, ok := t.workNodes[itemName]
+	if !ok {
+		return res
+	}
+
+	for _, fatherItemName := range node.Item.RunAfter {
+		status, ok := t.itemStatus[fatherItemName]
+		if !ok || status.CompletionTime == nil {
+			continue
+		}
+
+		if status.CompletionTime.Time.After(res) {
+			res = status.CompletionTime.Time
 		}
 	}
 
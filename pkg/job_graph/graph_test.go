@@ -0,0 +1,172 @@
+package job_graph
+
+import (
+	"songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"sort"
+	"testing"
+	alpha1 "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+)
+
+// newTestGraph returns the graph of job with the status of items, items not in status are pending.
+func newTestGraph(t *testing.T, items []v1alpha1.Item, status map[string]v1alpha1.ItemStatus) *JobItemGraph {
+	job := &v1alpha1.Job{Spec: v1alpha1.JobSpec{Items: items}}
+	job.Status.ItemStatus = map[string]v1alpha1.ItemStatus{}
+	for _, item := range items {
+		s, ok := status[item.Name]
+		if !ok {
+			s = v1alpha1.ItemStatus{Phase: v1alpha1.ItemPending}
+		}
+		s.Name = item.Name
+		job.Status.ItemStatus[item.Name] = s
+	}
+
+	g := NewJobItemGraph()
+	if err := g.SyncFromJob(job); err != nil {
+		t.Fatalf("sync graph err: %s", err.Error())
+	}
+
+	return g
+}
+
+func itemNames(items []*v1alpha1.Item) []string {
+	var res []string
+	for _, item := range items {
+		res = append(res, item.Name)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+func TestItemsNext2ScheduledCoScheduleGroup(t *testing.T) {
+	items := []v1alpha1.Item{
+		{Name: "init"},
+		{Name: "a", RunAfter: []string{"init"}, CoScheduleGroup: "g"},
+		{Name: "b", RunAfter: []string{"init"}, CoScheduleGroup: "g"},
+		{Name: "c", RunAfter: []string{"init"}},
+	}
+	completed := v1alpha1.ItemStatus{Phase: v1alpha1.ItemCompleted}
+
+	tests := []struct {
+		name        string
+		status      map[string]v1alpha1.ItemStatus
+		retryLimits map[string]int32
+		expected    []string
+	}{
+		{
+			name:     "members ready together",
+			status:   map[string]v1alpha1.ItemStatus{"init": completed},
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:     "member running",
+			status:   map[string]v1alpha1.ItemStatus{"init": completed, "a": {Phase: v1alpha1.ItemScheduled}},
+			expected: []string{"c"},
+		},
+		{
+			name:     "retried member not waiting for completed member",
+			status:   map[string]v1alpha1.ItemStatus{"init": completed, "a": completed, "c": completed},
+			expected: []string{"b"},
+		},
+		{
+			name: "member failed without retries left not waited for",
+			status: map[string]v1alpha1.ItemStatus{"init": completed, "c": completed,
+				"a": {Phase: v1alpha1.ItemFailed, RetryCount: 1}},
+			retryLimits: map[string]int32{"a": 1},
+			expected:    []string{"b"},
+		},
+		{
+			name: "member to be retried waited for",
+			status: map[string]v1alpha1.ItemStatus{"init": completed, "c": completed,
+				"a": {Phase: v1alpha1.ItemFailed}},
+			retryLimits: map[string]int32{"a": 1},
+		},
+	}
+
+	for _, test := range tests {
+		g := newTestGraph(t, items, test.status)
+		for name, limit := range test.retryLimits {
+			g.retryLimits[name] = limit
+		}
+
+		res := itemNames(g.ItemsNext2Scheduled())
+		if len(res) != len(test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, res)
+			continue
+		}
+		for i := range res {
+			if res[i] != test.expected[i] {
+				t.Errorf("%s: expected %v, got %v", test.name, test.expected, res)
+				break
+			}
+		}
+	}
+}
+
+func TestItemsNext2ScheduledItemNotInGraph(t *testing.T) {
+	g := newTestGraph(t, []v1alpha1.Item{{Name: "a"}}, nil)
+
+	// status kept for an item which has no node in graph
+	g.itemStatus["gone"] = &v1alpha1.ItemStatus{Name: "gone", Phase: v1alpha1.ItemPending}
+
+	if names := itemNames(g.ItemsNext2Scheduled()); len(names) != 1 || names[0] != "a" {
+		t.Errorf("expected only item a scheduled, got %v", names)
+	}
+}
+
+func TestSyncStatusPhaseWaitingAdmission(t *testing.T) {
+	item := v1alpha1.Item{
+		Name: "a",
+		ItemJobs: v1alpha1.ItemJobResource{Jobs: []v1alpha1.ItemJobTemplate{
+			{TemplateBaseInfo: v1alpha1.TemplateBaseInfo{Name: "train"}},
+			{TemplateBaseInfo: v1alpha1.TemplateBaseInfo{Name: "eval"}},
+		}},
+	}
+	train := v1alpha1.CalItemJobName("", &item, "train", 0)
+	eval := v1alpha1.CalItemJobName("", &item, "eval", 0)
+
+	waiting := alpha1.JobState{Phase: alpha1.Pending, Reason: v1alpha1.WaitingAdmissionReason, Message: "insufficient quota"}
+
+	tests := []struct {
+		name      string
+		jobStatus map[string]alpha1.JobState
+		admission bool
+		reason    string
+		phase     v1alpha1.ItemPhase
+		expected  v1alpha1.ItemPhase
+		message   string
+	}{
+		{name: "one job waiting", phase: v1alpha1.ItemScheduled, jobStatus: map[string]alpha1.JobState{train: waiting, eval: {Phase: alpha1.Running}},
+			admission: true, expected: v1alpha1.ItemScheduled, message: "insufficient quota"},
+		{name: "waiting not recorded", phase: v1alpha1.ItemScheduled, jobStatus: map[string]alpha1.JobState{train: waiting},
+			expected: v1alpha1.ItemScheduled},
+		{name: "waiting kept", phase: v1alpha1.ItemScheduled, jobStatus: map[string]alpha1.JobState{train: waiting},
+			reason: v1alpha1.WaitingAdmissionReason, expected: v1alpha1.ItemScheduled, message: "insufficient quota"},
+		{name: "all jobs admitted", phase: v1alpha1.ItemScheduled, jobStatus: map[string]alpha1.JobState{train: {Phase: alpha1.Running}, eval: {Phase: alpha1.Running}},
+			reason: v1alpha1.WaitingAdmissionReason, expected: v1alpha1.ItemScheduled},
+		{name: "job not created", jobStatus: map[string]alpha1.JobState{train: waiting},
+			admission: true, phase: v1alpha1.ItemPending, expected: v1alpha1.ItemPending},
+		{name: "other job failed", phase: v1alpha1.ItemScheduled, jobStatus: map[string]alpha1.JobState{train: waiting, eval: {Phase: alpha1.Failed}},
+			admission: true, expected: v1alpha1.ItemFailed},
+	}
+
+	for _, test := range tests {
+		g := newTestGraph(t, []v1alpha1.Item{item}, map[string]v1alpha1.ItemStatus{
+			"a": {Phase: test.phase, Reason: test.reason, Message: test.message, JobStatus: test.jobStatus},
+		})
+		if test.admission {
+			g.SetItemWaitingAdmission("a", train, "insufficient quota")
+		}
+		g.SyncStatusPhase()
+
+		status, _ := g.GetItemStatus("a")
+		expectedReason := ""
+		if test.message != "" {
+			expectedReason = v1alpha1.WaitingAdmissionReason
+		}
+		if status.Phase != test.expected || status.Reason != expectedReason || status.Message != test.message {
+			t.Errorf("%s: expected %s/%s/%s, got %s/%s/%s", test.name, test.expected, expectedReason, test.message,
+				status.Phase, status.Reason, status.Message)
+		}
+	}
+}
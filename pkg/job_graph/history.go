@@ -0,0 +1,103 @@
+package job_graph
+
+import (
+	"container/list"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"sync"
+	"time"
+)
+
+// defaultDurationHistoryLimit is the max num of items kept in ItemDurationHistory.
+const defaultDurationHistoryLimit = 10000
+
+// ItemDurationHistory records durations of completed items, read by duration aware scheduling policies.
+// It lives in memory only, the durations come from the start and completion time in the status of items,
+// so that history is rebuilt from the Jobs kept in cluster when their graphs are loaded after restart.
+var ItemDurationHistory = NewDurationHistory(defaultDurationHistoryLimit)
+
+// DurationHistory keeps a moving average of item durations, key is the source of job and item name,
+// so that the items of jobs rendered from the same template or created by the same ScheduledJob share
+// their history, while items of unrelated jobs with the same name do not. Items not observed for the
+// longest time are evicted once the num of items exceeds limit.
+type DurationHistory struct {
+	sync.RWMutex
+
+	limit int
+
+	durations map[string]*list.Element
+
+	// recent orders the items by the time they were observed, the front is the latest one
+	recent *list.List
+}
+
+type durationRecord struct {
+	key      string
+	duration time.Duration
+}
+
+func NewDurationHistory(limit int) *DurationHistory {
+	return &DurationHistory{
+		limit:     limit,
+		durations: map[string]*list.Element{},
+		recent:    list.New(),
+	}
+}
+
+// DurationHistorySource returns the source of job which its items share history with, the template
+// job is rendered from, or the ScheduledJob creating it, or job itself.
+func DurationHistorySource(job *v1alpha1.Job) string {
+	if job.Spec.TemplateRef != nil {
+		kind := job.Spec.TemplateRef.Kind
+		if kind == "" {
+			kind = v1alpha1.JobTemplateKind
+		}
+		return job.Namespace + "/" + kind + "/" + job.Spec.TemplateRef.Name
+	}
+
+	if owner := metav1.GetControllerOf(job); owner != nil && owner.Kind == "ScheduledJob" {
+		return job.Namespace + "/" + owner.Kind + "/" + owner.Name
+	}
+
+	return job.Namespace + "/Job/" + job.Name
+}
+
+func durationHistoryKey(source, itemName string) string {
+	return source + "/" + itemName
+}
+
+// Observe records a duration of item, recent durations weigh more than old ones.
+func (h *DurationHistory) Observe(source, itemName string, duration time.Duration) {
+	h.Lock()
+	defer h.Unlock()
+
+	key := durationHistoryKey(source, itemName)
+
+	if element, ok := h.durations[key]; ok {
+		record := element.Value.(*durationRecord)
+		record.duration = (record.duration + duration) / 2
+		h.recent.MoveToFront(element)
+		return
+	}
+
+	h.durations[key] = h.recent.PushFront(&durationRecord{key: key, duration: duration})
+
+	for h.limit > 0 && h.recent.Len() > h.limit {
+		oldest := h.recent.Back()
+		h.recent.Remove(oldest)
+		delete(h.durations, oldest.Value.(*durationRecord).key)
+	}
+}
+
+// Expect returns the expected duration of item, false if item never completed before.
+func (h *DurationHistory) Expect(source, itemName string) (time.Duration, bool) {
+	h.RLock()
+	defer h.RUnlock()
+
+	element, ok := h.durations[durationHistoryKey(source, itemName)]
+	if !ok {
+		return 0, false
+	}
+
+	return element.Value.(*durationRecord).duration, true
+}
@@ -0,0 +1,100 @@
+package job_graph
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+	"time"
+)
+
+func TestDurationHistoryObserve(t *testing.T) {
+	h := NewDurationHistory(2)
+
+	if _, ok := h.Expect("ns/Job/job", "a"); ok {
+		t.Errorf("item never completed should have no history")
+	}
+
+	h.Observe("ns/Job/job", "a", 10*time.Second)
+	h.Observe("ns/Job/job", "a", 20*time.Second)
+	if duration, _ := h.Expect("ns/Job/job", "a"); duration != 15*time.Second {
+		t.Errorf("expected 15s, got %s", duration)
+	}
+
+	if _, ok := h.Expect("ns/Job/other", "a"); ok {
+		t.Errorf("history should not be shared across jobs")
+	}
+
+	// a is observed again after b, so that b is the oldest one evicted by c
+	h.Observe("ns/Job/job", "b", time.Second)
+	h.Observe("ns/Job/job", "a", 15*time.Second)
+	h.Observe("ns/Job/job", "c", time.Second)
+	if _, ok := h.Expect("ns/Job/job", "b"); ok {
+		t.Errorf("item observed the longest time ago should be evicted")
+	}
+	if _, ok := h.Expect("ns/Job/job", "a"); !ok {
+		t.Errorf("item observed recently should be kept")
+	}
+}
+
+func TestDurationHistorySource(t *testing.T) {
+	controller := true
+
+	tests := []struct {
+		name     string
+		job      *v1alpha1.Job
+		expected string
+	}{
+		{
+			name:     "job",
+			job:      &v1alpha1.Job{ObjectMeta: metav1.ObjectMeta{Name: "job-1", Namespace: "ns"}},
+			expected: "ns/Job/job-1",
+		},
+		{
+			name: "rendered from template",
+			job: &v1alpha1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job-1", Namespace: "ns"},
+				Spec:       v1alpha1.JobSpec{TemplateRef: &v1alpha1.TemplateReference{Name: "train"}},
+			},
+			expected: "ns/JobTemplate/train",
+		},
+		{
+			name: "created by ScheduledJob",
+			job: &v1alpha1.Job{ObjectMeta: metav1.ObjectMeta{Name: "nightly-1", Namespace: "ns",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "ScheduledJob", Name: "nightly", Controller: &controller}}}},
+			expected: "ns/ScheduledJob/nightly",
+		},
+	}
+
+	for _, test := range tests {
+		if source := DurationHistorySource(test.job); source != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, source)
+		}
+	}
+}
+
+func TestSyncFromJobRebuildsHistory(t *testing.T) {
+	start := metav1.NewTime(time.Now().Add(-time.Minute))
+	completion := metav1.NewTime(start.Add(30 * time.Second))
+
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job", Namespace: "history-rebuilt"},
+		Spec:       v1alpha1.JobSpec{Items: []v1alpha1.Item{{Name: "a"}, {Name: "b", RunAfter: []string{"a"}}}},
+		Status: v1alpha1.JobStatus{ItemStatus: map[string]v1alpha1.ItemStatus{
+			"a": {Name: "a", Phase: v1alpha1.ItemCompleted, StartTime: &start, CompletionTime: &completion},
+			"b": {Name: "b", Phase: v1alpha1.ItemScheduled, StartTime: &start},
+		}},
+	}
+
+	// the graph of job loaded after restart
+	g := NewJobItemGraph()
+	if err := g.SyncFromJob(job); err != nil {
+		t.Fatalf("sync graph err: %s", err.Error())
+	}
+
+	if duration, ok := ItemDurationHistory.Expect(DurationHistorySource(job), "a"); !ok || duration != 30*time.Second {
+		t.Errorf("expected history of 30s rebuilt from status, got %s %t", duration, ok)
+	}
+	if _, ok := ItemDurationHistory.Expect(DurationHistorySource(job), "b"); ok {
+		t.Errorf("item not completed should have no history")
+	}
+}
@@ -0,0 +1,202 @@
+package job_graph
+
+import (
+	"fmt"
+	"songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SchedulingPolicy decides which ready items of a job to dispatch and in what order.
+type SchedulingPolicy interface {
+	// Name is the name set in JobSpec.SchedulingPolicy to select the policy.
+	Name() string
+
+	// Schedule receives the items whose parents all completed, and returns the items to dispatch
+	// in the order of dispatching. Items not returned stay pending and will be offered again.
+	Schedule(graph *JobItemGraph, items []*v1alpha1.Item) []*v1alpha1.Item
+}
+
+var (
+	policyLock         sync.RWMutex
+	schedulingPolicies = map[string]SchedulingPolicy{}
+)
+
+func init() {
+	RegisterSchedulingPolicy(&fifoPolicy{})
+	RegisterSchedulingPolicy(&priorityPolicy{})
+	RegisterSchedulingPolicy(&shortestExpectedDurationPolicy{})
+	RegisterSchedulingPolicy(&criticalPathPolicy{})
+}
+
+// RegisterSchedulingPolicy registers a policy, policy with the same name will be replaced.
+func RegisterSchedulingPolicy(policy SchedulingPolicy) {
+	policyLock.Lock()
+	defer policyLock.Unlock()
+
+	schedulingPolicies[policy.Name()] = policy
+}
+
+func GetSchedulingPolicy(name string) (SchedulingPolicy, error) {
+	policyLock.RLock()
+	defer policyLock.RUnlock()
+
+	policy, ok := schedulingPolicies[name]
+	if !ok {
+		return nil, fmt.Errorf("scheduling policy %s not registered", name)
+	}
+
+	return policy, nil
+}
+
+// sortItems sorts items by less, and by fifo order if equal. The fifo order is read from graph before
+// sorting, so that graph is not read while less is called.
+func sortItems(graph *JobItemGraph, items []*v1alpha1.Item, less func(a, b *v1alpha1.Item) (bool, bool)) []*v1alpha1.Item {
+	res := append([]*v1alpha1.Item{}, items...)
+
+	readyTimes, indexes := map[string]time.Time{}, map[string]int{}
+	graph.RLock()
+	for _, item := range res {
+		readyTimes[item.Name] = graph.itemReadyTime(item.Name)
+		indexes[item.Name] = graph.itemIndex[item.Name]
+	}
+	graph.RUnlock()
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if less != nil {
+			if isLess, decided := less(res[i], res[j]); decided {
+				return isLess
+			}
+		}
+
+		readyI, readyJ := readyTimes[res[i].Name], readyTimes[res[j].Name]
+		if !readyI.Equal(readyJ) {
+			return readyI.Before(readyJ)
+		}
+
+		return indexes[res[i].Name] < indexes[res[j].Name]
+	})
+
+	return res
+}
+
+// fifoPolicy dispatches items in the order they became ready, items ready at the same time
+// are dispatched in the order declared in job.
+type fifoPolicy struct{}
+
+func (p *fifoPolicy) Name() string {
+	return v1alpha1.FIFOSchedulingPolicy
+}
+
+func (p *fifoPolicy) Schedule(graph *JobItemGraph, items []*v1alpha1.Item) []*v1alpha1.Item {
+	return sortItems(graph, items, nil)
+}
+
+// priorityPolicy dispatches items with higher songf.sh/item-priority annotation first.
+type priorityPolicy struct{}
+
+func (p *priorityPolicy) Name() string {
+	return v1alpha1.PrioritySchedulingPolicy
+}
+
+func itemPriority(item *v1alpha1.Item) int64 {
+	value, ok := item.Annotations[v1alpha1.ItemPriorityAnnotation]
+	if !ok {
+		return 0
+	}
+
+	priority, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0
+	}
+
+	return priority
+}
+
+func (p *priorityPolicy) Schedule(graph *JobItemGraph, items []*v1alpha1.Item) []*v1alpha1.Item {
+	return sortItems(graph, items, func(a, b *v1alpha1.Item) (bool, bool) {
+		priorityA, priorityB := itemPriority(a), itemPriority(b)
+		return priorityA > priorityB, priorityA != priorityB
+	})
+}
+
+// shortestExpectedDurationPolicy dispatches items which are expected to finish sooner first,
+// expected duration comes from the history of the items with the same name in the jobs of the same
+// source, which only covers the Jobs kept in cluster. Items without history are dispatched after the others.
+type shortestExpectedDurationPolicy struct{}
+
+func (p *shortestExpectedDurationPolicy) Name() string {
+	return v1alpha1.ShortestExpectedDurationSchedulingPolicy
+}
+
+func (p *shortestExpectedDurationPolicy) Schedule(graph *JobItemGraph, items []*v1alpha1.Item) []*v1alpha1.Item {
+	source := graph.getHistorySource()
+
+	return sortItems(graph, items, func(a, b *v1alpha1.Item) (bool, bool) {
+		durationA, okA := ItemDurationHistory.Expect(source, a.Name)
+		durationB, okB := ItemDurationHistory.Expect(source, b.Name)
+
+		if okA != okB {
+			return okA, true
+		}
+
+		return durationA < durationB, durationA != durationB
+	})
+}
+
+// criticalPathPolicy dispatches items on the longest path to the end of job first.
+// The length of path is the sum of expected durations of its items, item without history counts 1s.
+type criticalPathPolicy struct{}
+
+func (p *criticalPathPolicy) Name() string {
+	return v1alpha1.CriticalPathSchedulingPolicy
+}
+
+func (p *criticalPathPolicy) Schedule(graph *JobItemGraph, items []*v1alpha1.Item) []*v1alpha1.Item {
+	lengths := map[string]time.Duration{}
+
+	graph.RLock()
+	for _, item := range items {
+		criticalPathLength(graph, item.Name, lengths)
+	}
+	graph.RUnlock()
+
+	return sortItems(graph, items, func(a, b *v1alpha1.Item) (bool, bool) {
+		lengthA, lengthB := lengths[a.Name], lengths[b.Name]
+		return lengthA > lengthB, lengthA != lengthB
+	})
+}
+
+// criticalPathLength returns the length of the longest path from item to the end of job, caller must
+// hold the read lock of graph.
+func criticalPathLength(graph *JobItemGraph, itemName string, lengths map[string]time.Duration) time.Duration {
+	if length, ok := lengths[itemName]; ok {
+		return length
+	}
+
+	// set before visiting children, so that a cycle can not loop forever
+	lengths[itemName] = 0
+
+	length, ok := ItemDurationHistory.Expect(graph.historySource, itemName)
+	if !ok || length < time.Second {
+		length = time.Second
+	}
+
+	var longestChild time.Duration
+	if node, ok := graph.workNodes[itemName]; ok {
+		for _, child := range node.Child {
+			if child.Item.Truncated != nil && *child.Item.Truncated {
+				continue
+			}
+
+			if childLength := criticalPathLength(graph, child.Item.Name, lengths); childLength > longestChild {
+				longestChild = childLength
+			}
+		}
+	}
+
+	lengths[itemName] = length + longestChild
+	return lengths[itemName]
+}
@@ -0,0 +1,76 @@
+package job_graph
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSchedulingPolicies(t *testing.T) {
+	truncated := true
+	early := metav1.NewTime(time.Now().Add(-time.Hour))
+	late := metav1.NewTime(early.Add(time.Minute))
+
+	// b and c are ready since s completed, a is ready later since m completed
+	items := []v1alpha1.Item{
+		{Name: "s"},
+		{Name: "m", RunAfter: []string{"s"}},
+		{Name: "a", RunAfter: []string{"m"}, Annotations: map[string]string{v1alpha1.ItemPriorityAnnotation: "5"}},
+		{Name: "b", RunAfter: []string{"s"}},
+		{Name: "c", RunAfter: []string{"s"}, Annotations: map[string]string{v1alpha1.ItemPriorityAnnotation: "1"}},
+		{Name: "d", RunAfter: []string{"b"}},
+		{Name: "e", RunAfter: []string{"c"}, Truncated: &truncated},
+	}
+	status := map[string]v1alpha1.ItemStatus{
+		"s": {Phase: v1alpha1.ItemCompleted, CompletionTime: &early},
+		"m": {Phase: v1alpha1.ItemCompleted, CompletionTime: &late},
+	}
+
+	g := newTestGraph(t, items, status)
+	g.historySource = "scheduling-policies/Job/job"
+
+	ItemDurationHistory.Observe(g.historySource, "a", 10*time.Second)
+	ItemDurationHistory.Observe(g.historySource, "b", 30*time.Second)
+	ItemDurationHistory.Observe(g.historySource, "d", time.Minute)
+	ItemDurationHistory.Observe(g.historySource, "e", time.Hour)
+
+	tests := []struct {
+		policy string
+		order  string
+	}{
+		// ready time first, then the order declared in job
+		{policy: v1alpha1.FIFOSchedulingPolicy, order: "b,c,a"},
+		// items without priority count 0
+		{policy: v1alpha1.PrioritySchedulingPolicy, order: "a,c,b"},
+		// items without history go last
+		{policy: v1alpha1.ShortestExpectedDurationSchedulingPolicy, order: "a,b,c"},
+		// b leads to d, truncated e is not on the path of c
+		{policy: v1alpha1.CriticalPathSchedulingPolicy, order: "b,a,c"},
+	}
+
+	for _, test := range tests {
+		policy, err := GetSchedulingPolicy(test.policy)
+		if err != nil {
+			t.Fatalf("%s: get policy err: %s", test.policy, err.Error())
+		}
+
+		var ready []*v1alpha1.Item
+		for _, name := range []string{"a", "c", "b"} {
+			ready = append(ready, g.workNodes[name].Item)
+		}
+
+		var order []string
+		for _, item := range policy.Schedule(g, ready) {
+			order = append(order, item.Name)
+		}
+		if strings.Join(order, ",") != test.order {
+			t.Errorf("%s: expected order %s, got %v", test.policy, test.order, order)
+		}
+	}
+
+	if _, err := GetSchedulingPolicy("unknown"); err == nil {
+		t.Errorf("unknown policy should not be found")
+	}
+}
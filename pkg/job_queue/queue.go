@@ -0,0 +1,199 @@
+package job_queue
+
+import (
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	"songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"sort"
+)
+
+// Usage describes the resources held by the running Jobs of a queue.
+type Usage struct {
+	Running      int32
+	RunningItems int32
+	Allocated    corev1.ResourceList
+
+	namespaceRunning map[string]int32
+}
+
+func IsJobQueued(job *v1alpha1.Job) bool {
+	return job.Status.State.Phase == v1alpha1.Queued
+}
+
+// IsJobRunning returns true if the job was admitted and has not finished yet.
+func IsJobRunning(job *v1alpha1.Job) bool {
+	switch job.Status.State.Phase {
+	case v1alpha1.Scheduled, v1alpha1.Completing:
+		return true
+	}
+
+	return false
+}
+
+func CalRunningItemNum(job *v1alpha1.Job) int32 {
+	var num int32
+
+	for _, status := range job.Status.ItemStatus {
+		switch status.Phase {
+		case v1alpha1.ItemScheduling, v1alpha1.ItemScheduled:
+			num++
+		}
+	}
+
+	return num
+}
+
+// CalUsage sums the usage of running jobs, jobs in other phases are ignored.
+func CalUsage(jobs []*v1alpha1.Job) *Usage {
+	usage := &Usage{
+		Allocated:        corev1.ResourceList{},
+		namespaceRunning: map[string]int32{},
+	}
+
+	for _, job := range jobs {
+		if !IsJobRunning(job) {
+			continue
+		}
+
+		usage.Running++
+		usage.RunningItems += CalRunningItemNum(job)
+		usage.namespaceRunning[job.Namespace]++
+
+		for name, quantity := range v1alpha1.CalJobResourceRequests(job) {
+			total := usage.Allocated[name]
+			total.Add(quantity)
+			usage.Allocated[name] = total
+		}
+	}
+
+	return usage
+}
+
+func (u *Usage) ToStatus(pending int32) v1alpha1.QueueStatus {
+	return v1alpha1.QueueStatus{
+		Pending:      pending,
+		Running:      u.Running,
+		RunningItems: u.RunningItems,
+		Allocated:    u.Allocated.DeepCopy(),
+	}
+}
+
+func namespaceWeight(queue *v1alpha1.Queue, namespace string) int32 {
+	weight, ok := queue.Spec.NamespaceWeights[namespace]
+	if !ok || weight <= 0 {
+		return 1
+	}
+
+	return weight
+}
+
+func jobPriority(job *v1alpha1.Job) int32 {
+	if job.Spec.Priority == nil {
+		return 0
+	}
+
+	return *job.Spec.Priority
+}
+
+// OrderQueuedJobs returns queued jobs in the order they will be admitted.
+// Inside a namespace, jobs are ordered by priority and then creation time.
+// Between namespaces, the next job always comes from the namespace which has
+// the lowest num of running jobs divided by its weight.
+func OrderQueuedJobs(queue *v1alpha1.Queue, usage *Usage, queued []*v1alpha1.Job) []*v1alpha1.Job {
+
+	namespaceJobs := map[string][]*v1alpha1.Job{}
+	for _, job := range queued {
+		namespaceJobs[job.Namespace] = append(namespaceJobs[job.Namespace], job)
+	}
+
+	var namespaces []string
+	for namespace, jobs := range namespaceJobs {
+		namespaces = append(namespaces, namespace)
+
+		sort.SliceStable(jobs, func(i, j int) bool {
+			if jobPriority(jobs[i]) != jobPriority(jobs[j]) {
+				return jobPriority(jobs[i]) > jobPriority(jobs[j])
+			}
+
+			if !jobs[i].CreationTimestamp.Equal(&jobs[j].CreationTimestamp) {
+				return jobs[i].CreationTimestamp.Before(&jobs[j].CreationTimestamp)
+			}
+
+			return jobs[i].Name < jobs[j].Name
+		})
+	}
+	sort.Strings(namespaces)
+
+	running := map[string]int32{}
+	for namespace, num := range usage.namespaceRunning {
+		running[namespace] = num
+	}
+
+	var res []*v1alpha1.Job
+	for len(res) < len(queued) {
+		next := ""
+		var nextShare float64
+
+		for _, namespace := range namespaces {
+			if len(namespaceJobs[namespace]) == 0 {
+				continue
+			}
+
+			share := float64(running[namespace]) / float64(namespaceWeight(queue, namespace))
+			if next == "" || share < nextShare {
+				next = namespace
+				nextShare = share
+			}
+		}
+
+		res = append(res, namespaceJobs[next][0])
+		namespaceJobs[next] = namespaceJobs[next][1:]
+		running[next]++
+	}
+
+	return res
+}
+
+// CanAdmit checks whether job fits into the rest of queue, if not, the reason is returned.
+func CanAdmit(queue *v1alpha1.Queue, usage *Usage, job *v1alpha1.Job) (bool, string) {
+
+	if queue.Spec.MaxRunningJobs != nil && usage.Running >= *queue.Spec.MaxRunningJobs {
+		return false, fmt.Sprintf("queue %s reached max running jobs %d", queue.Name, *queue.Spec.MaxRunningJobs)
+	}
+
+	if queue.Spec.MaxRunningItems != nil && usage.RunningItems >= *queue.Spec.MaxRunningItems {
+		return false, fmt.Sprintf("queue %s reached max running items %d", queue.Name, *queue.Spec.MaxRunningItems)
+	}
+
+	requests := v1alpha1.CalJobResourceRequests(job)
+	for name, quota := range queue.Spec.Quota {
+		request, ok := requests[name]
+		if !ok {
+			continue
+		}
+
+		total := usage.Allocated[name]
+		total.Add(request)
+		if total.Cmp(quota) > 0 {
+			return false, fmt.Sprintf("queue %s quota of %s exceeded: %s requested, %s allocated, %s quota",
+				queue.Name, name, request.String(), usage.Allocated.Name(name, quota.Format).String(), quota.String())
+		}
+	}
+
+	return true, ""
+}
+
+// ItemBudget returns the num of items which can still be dispatched by the running jobs of queue,
+// -1 means not limited.
+func ItemBudget(queue *v1alpha1.Queue, usage *Usage) int32 {
+	if queue.Spec.MaxRunningItems == nil {
+		return -1
+	}
+
+	budget := *queue.Spec.MaxRunningItems - usage.RunningItems
+	if budget < 0 {
+		return 0
+	}
+
+	return budget
+}
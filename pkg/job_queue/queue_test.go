@@ -0,0 +1,221 @@
+package job_queue
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"songf.sh/songf/pkg/api/apps.songf.sh/v1alpha1"
+	"testing"
+	"time"
+)
+
+var testCreated = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// newTestJob returns a job of one item requesting cpu, created after testCreated by the seconds.
+func newTestJob(namespace, name string, priority *int32, seconds int, cpu string, phase v1alpha1.JobPhase) *v1alpha1.Job {
+	job := &v1alpha1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         namespace,
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(testCreated.Add(time.Duration(seconds) * time.Second)),
+		},
+		Spec: v1alpha1.JobSpec{Priority: priority},
+	}
+	job.Status.State.Phase = phase
+
+	if cpu != "" {
+		job.Spec.Items = []v1alpha1.Item{{
+			Name: "a",
+			ItemJobs: v1alpha1.ItemJobResource{Jobs: []v1alpha1.ItemJobTemplate{{
+				TemplateBaseInfo: v1alpha1.TemplateBaseInfo{Name: "train"},
+				KubeJobSpec: &batchv1.JobSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+					}}},
+				}}},
+			}}},
+		}}
+	}
+
+	return job
+}
+
+func jobNames(jobs []*v1alpha1.Job) []string {
+	var res []string
+	for _, job := range jobs {
+		res = append(res, job.Namespace+"/"+job.Name)
+	}
+
+	return res
+}
+
+func TestOrderQueuedJobs(t *testing.T) {
+	high := int32(10)
+
+	tests := []struct {
+		name    string
+		weights map[string]int32
+		running []*v1alpha1.Job
+		queued  []*v1alpha1.Job
+		order   []string
+	}{
+		{
+			name: "priority before creation time",
+			queued: []*v1alpha1.Job{
+				newTestJob("a", "old", nil, 0, "", v1alpha1.Queued),
+				newTestJob("a", "new", nil, 10, "", v1alpha1.Queued),
+				newTestJob("a", "high", &high, 20, "", v1alpha1.Queued),
+			},
+			order: []string{"a/high", "a/old", "a/new"},
+		},
+		{
+			name: "name breaks ties",
+			queued: []*v1alpha1.Job{
+				newTestJob("a", "y", nil, 0, "", v1alpha1.Queued),
+				newTestJob("a", "x", nil, 0, "", v1alpha1.Queued),
+			},
+			order: []string{"a/x", "a/y"},
+		},
+		{
+			name: "namespaces take turns",
+			queued: []*v1alpha1.Job{
+				newTestJob("a", "1", nil, 0, "", v1alpha1.Queued),
+				newTestJob("a", "2", nil, 1, "", v1alpha1.Queued),
+				newTestJob("b", "1", nil, 2, "", v1alpha1.Queued),
+				newTestJob("b", "2", nil, 3, "", v1alpha1.Queued),
+			},
+			order: []string{"a/1", "b/1", "a/2", "b/2"},
+		},
+		{
+			name:    "namespace running less goes first",
+			running: []*v1alpha1.Job{newTestJob("a", "running", nil, 0, "", v1alpha1.Scheduled)},
+			queued: []*v1alpha1.Job{
+				newTestJob("a", "1", nil, 0, "", v1alpha1.Queued),
+				newTestJob("b", "1", &high, 10, "", v1alpha1.Queued),
+				newTestJob("b", "2", nil, 10, "", v1alpha1.Queued),
+			},
+			order: []string{"b/1", "a/1", "b/2"},
+		},
+		{
+			name:    "namespace weighted",
+			weights: map[string]int32{"a": 2},
+			queued: []*v1alpha1.Job{
+				newTestJob("a", "1", nil, 0, "", v1alpha1.Queued),
+				newTestJob("a", "2", nil, 1, "", v1alpha1.Queued),
+				newTestJob("a", "3", nil, 2, "", v1alpha1.Queued),
+				newTestJob("b", "1", nil, 0, "", v1alpha1.Queued),
+				newTestJob("b", "2", nil, 1, "", v1alpha1.Queued),
+			},
+			order: []string{"a/1", "b/1", "a/2", "a/3", "b/2"},
+		},
+	}
+
+	for _, test := range tests {
+		queue := &v1alpha1.Queue{Spec: v1alpha1.QueueSpec{NamespaceWeights: test.weights}}
+
+		order := jobNames(OrderQueuedJobs(queue, CalUsage(test.running), test.queued))
+		if len(order) != len(test.order) {
+			t.Errorf("%s: expected order %v, got %v", test.name, test.order, order)
+			continue
+		}
+		for i := range order {
+			if order[i] != test.order[i] {
+				t.Errorf("%s: expected order %v, got %v", test.name, test.order, order)
+				break
+			}
+		}
+	}
+}
+
+func TestCanAdmit(t *testing.T) {
+	one := int32(1)
+
+	tests := []struct {
+		name    string
+		spec    v1alpha1.QueueSpec
+		running []*v1alpha1.Job
+		job     *v1alpha1.Job
+		admit   bool
+	}{
+		{
+			name:  "not limited",
+			job:   newTestJob("a", "job", nil, 0, "4", v1alpha1.Queued),
+			admit: true,
+		},
+		{
+			name:    "max running jobs reached",
+			spec:    v1alpha1.QueueSpec{MaxRunningJobs: &one},
+			running: []*v1alpha1.Job{newTestJob("a", "running", nil, 0, "", v1alpha1.Scheduled)},
+			job:     newTestJob("a", "job", nil, 0, "", v1alpha1.Queued),
+		},
+		{
+			name:    "finished jobs not counted",
+			spec:    v1alpha1.QueueSpec{MaxRunningJobs: &one},
+			running: []*v1alpha1.Job{newTestJob("a", "done", nil, 0, "", v1alpha1.Completed)},
+			job:     newTestJob("a", "job", nil, 0, "", v1alpha1.Queued),
+			admit:   true,
+		},
+		{
+			name: "max running items reached",
+			spec: v1alpha1.QueueSpec{MaxRunningItems: &one},
+			running: func() []*v1alpha1.Job {
+				job := newTestJob("a", "running", nil, 0, "", v1alpha1.Scheduled)
+				job.Status.ItemStatus = map[string]v1alpha1.ItemStatus{"a": {Phase: v1alpha1.ItemScheduled}}
+				return []*v1alpha1.Job{job}
+			}(),
+			job: newTestJob("a", "job", nil, 0, "", v1alpha1.Queued),
+		},
+		{
+			name:    "quota fits",
+			spec:    v1alpha1.QueueSpec{Quota: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}},
+			running: []*v1alpha1.Job{newTestJob("a", "running", nil, 0, "2", v1alpha1.Scheduled)},
+			job:     newTestJob("a", "job", nil, 0, "2", v1alpha1.Queued),
+			admit:   true,
+		},
+		{
+			name:    "quota exceeded",
+			spec:    v1alpha1.QueueSpec{Quota: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}},
+			running: []*v1alpha1.Job{newTestJob("a", "running", nil, 0, "2", v1alpha1.Scheduled)},
+			job:     newTestJob("a", "job", nil, 0, "2500m", v1alpha1.Queued),
+		},
+		{
+			name:  "resource without quota not limited",
+			spec:  v1alpha1.QueueSpec{Quota: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}},
+			job:   newTestJob("a", "job", nil, 0, "8", v1alpha1.Queued),
+			admit: true,
+		},
+	}
+
+	for _, test := range tests {
+		queue := &v1alpha1.Queue{ObjectMeta: metav1.ObjectMeta{Name: "q"}, Spec: test.spec}
+
+		admit, reason := CanAdmit(queue, CalUsage(test.running), test.job)
+		if admit != test.admit {
+			t.Errorf("%s: expected admit %t, got %t %s", test.name, test.admit, admit, reason)
+		}
+		if !admit && reason == "" {
+			t.Errorf("%s: reason should be returned if not admitted", test.name)
+		}
+	}
+}
+
+func TestItemBudget(t *testing.T) {
+	two := int32(2)
+
+	running := newTestJob("a", "running", nil, 0, "", v1alpha1.Scheduled)
+	running.Status.ItemStatus = map[string]v1alpha1.ItemStatus{
+		"a": {Phase: v1alpha1.ItemScheduled},
+		"b": {Phase: v1alpha1.ItemScheduling},
+		"c": {Phase: v1alpha1.ItemScheduled},
+		"d": {Phase: v1alpha1.ItemCompleted},
+	}
+	usage := CalUsage([]*v1alpha1.Job{running})
+
+	if budget := ItemBudget(&v1alpha1.Queue{}, usage); budget != -1 {
+		t.Errorf("expected budget not limited, got %d", budget)
+	}
+	if budget := ItemBudget(&v1alpha1.Queue{Spec: v1alpha1.QueueSpec{MaxRunningItems: &two}}, usage); budget != 0 {
+		t.Errorf("expected no budget left, got %d", budget)
+	}
+}
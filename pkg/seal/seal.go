@@ -0,0 +1,166 @@
+// Package seal encrypts the data of secret templates by the public key of operator, so that only operator
+// can decrypt them. A value is sealed for the namespace of Job and the name of secret template, and can not
+// be opened for others.
+//
+// A sealed value is the base64 of the length of encrypted key in 2 bytes, the AES-256 key encrypted by
+// RSA-OAEP with SHA-256, the nonce and the data encrypted by AES-GCM with "<namespace>/<name>" as additional data.
+package seal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+)
+
+// PrivateKeyKey is the key of the PEM encoded private key in the Secret of operator.
+const PrivateKeyKey = "private.pem"
+
+const sessionKeySize = 32
+
+// Seal encrypts data for the secret template named name in namespace.
+func Seal(pub *rsa.PublicKey, namespace, name string, data []byte) (string, error) {
+	sessionKey := make([]byte, sessionKeySize)
+	if _, err := rand.Read(sessionKey); err != nil {
+		return "", err
+	}
+
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, sessionKey, nil)
+	if err != nil {
+		return "", fmt.Errorf("encrypt session key err: %s", err.Error())
+	}
+
+	gcm, err := newGCM(sessionKey)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	res := binary.BigEndian.AppendUint16(nil, uint16(len(encryptedKey)))
+	res = append(res, encryptedKey...)
+	res = append(res, nonce...)
+	res = gcm.Seal(res, nonce, data, additionalData(namespace, name))
+
+	return base64.StdEncoding.EncodeToString(res), nil
+}
+
+// Open decrypts the value sealed for the secret template named name in namespace.
+func Open(priv *rsa.PrivateKey, namespace, name, sealed string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, fmt.Errorf("decode sealed value err: %s", err.Error())
+	}
+
+	if len(raw) < 2 {
+		return nil, fmt.Errorf("sealed value too short")
+	}
+	keyLen := int(binary.BigEndian.Uint16(raw))
+	raw = raw[2:]
+	if len(raw) < keyLen {
+		return nil, fmt.Errorf("sealed value too short")
+	}
+
+	sessionKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, priv, raw[:keyLen], nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt session key err: %s", err.Error())
+	}
+	raw = raw[keyLen:]
+
+	gcm, err := newGCM(sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) < gcm.NonceSize() {
+		return nil, fmt.Errorf("sealed value too short")
+	}
+
+	data, err := gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], additionalData(namespace, name))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt sealed value for %s/%s err: %s", namespace, name, err.Error())
+	}
+
+	return data, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func additionalData(namespace, name string) []byte {
+	return []byte(namespace + "/" + name)
+}
+
+// ParsePublicKey parses a PEM encoded RSA public key, in PKIX or PKCS #1 form, or the one of a certificate.
+func ParsePublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse public key err: %s", err.Error())
+	}
+
+	pub, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is not RSA")
+	}
+
+	return pub, nil
+}
+
+// ParsePrivateKey parses a PEM encoded RSA private key, in PKCS #1 or PKCS #8 form.
+func ParsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse private key err: %s", err.Error())
+		}
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key err: %s", err.Error())
+	}
+
+	priv, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not RSA")
+	}
+
+	return priv, nil
+}